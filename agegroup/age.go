@@ -0,0 +1,68 @@
+// Package agegroup calculates the race age of participants and assigns them to the age groups of
+// an event.
+package agegroup
+
+import (
+	"time"
+
+	"github.com/raceresult/go-model/date"
+)
+
+// AgeRule defines on which date the age of a participant is calculated.
+type AgeRule int
+
+const (
+	// AgeOnRaceDay is the age in completed years on the race day.
+	AgeOnRaceDay AgeRule = iota
+	// AgeAtYearEnd is the age on 31st December of the year of the race, i.e. the year of the race
+	// minus the year of birth. This is common in athletics and cycling.
+	AgeAtYearEnd
+	// AgeOnReferenceDate is the age in completed years on a configurable date, e.g. the start or
+	// the end of a season.
+	AgeOnReferenceDate
+)
+
+// Config contains the settings for the age calculation.
+type Config struct {
+	Rule AgeRule
+	// RaceDay is the day of the race, used by AgeOnRaceDay and AgeAtYearEnd.
+	RaceDay date.Date
+	// ReferenceDate is used by AgeOnReferenceDate.
+	ReferenceDate date.Date
+}
+
+// AgeDate returns the date on which the age is calculated. The result is the zero date if the
+// date required by the rule is not set.
+func (c Config) AgeDate() date.Date {
+	switch c.Rule {
+	case AgeAtYearEnd:
+		if c.RaceDay.IsZero() {
+			return date.Date{}
+		}
+		return date.New(c.RaceDay.Year(), time.December, 31)
+	case AgeOnReferenceDate:
+		return c.ReferenceDate
+	default:
+		return c.RaceDay
+	}
+}
+
+// Age calculates the age of a participant born on dateOfBirth. The result is false if the date of
+// birth or the date required by the rule is not set.
+func (c Config) Age(dateOfBirth date.Date) (int, bool) {
+	d := c.AgeDate()
+	if dateOfBirth.IsZero() || d.IsZero() {
+		return 0, false
+	}
+	return AgeOn(dateOfBirth, d), true
+}
+
+// AgeOn returns the age in completed years on the given day of a person born on dateOfBirth.
+// People born on 29th February have their birthday on 1st March in other years.
+func AgeOn(dateOfBirth, day date.Date) int {
+	age := day.Year() - dateOfBirth.Year()
+	if day.Month() < dateOfBirth.Month() || day.Month() == dateOfBirth.Month() && day.Day() < dateOfBirth.Day() {
+		age--
+	}
+	return age
+}
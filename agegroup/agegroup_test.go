@@ -0,0 +1,145 @@
+package agegroup
+
+import (
+	"testing"
+	"time"
+
+	sesbase "github.com/raceresult/go-model"
+	"github.com/raceresult/go-model/date"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgeOn(t *testing.T) {
+	dob := date.New(1990, time.June, 15)
+	assert.Equal(t, 33, AgeOn(dob, date.New(2024, time.June, 14)))
+	assert.Equal(t, 34, AgeOn(dob, date.New(2024, time.June, 15)))
+	assert.Equal(t, 34, AgeOn(dob, date.New(2024, time.December, 31)))
+
+	leap := date.New(2000, time.February, 29)
+	assert.Equal(t, 22, AgeOn(leap, date.New(2023, time.February, 28)))
+	assert.Equal(t, 23, AgeOn(leap, date.New(2023, time.March, 1)))
+	assert.Equal(t, 24, AgeOn(leap, date.New(2024, time.February, 29)))
+}
+
+func TestConfigAge(t *testing.T) {
+	dob := date.New(1990, time.June, 15)
+	raceDay := date.New(2024, time.May, 1)
+
+	age, ok := Config{Rule: AgeOnRaceDay, RaceDay: raceDay}.Age(dob)
+	assert.True(t, ok)
+	assert.Equal(t, 33, age)
+
+	age, ok = Config{Rule: AgeAtYearEnd, RaceDay: raceDay}.Age(dob)
+	assert.True(t, ok)
+	assert.Equal(t, 34, age)
+
+	age, ok = Config{Rule: AgeOnReferenceDate, RaceDay: raceDay, ReferenceDate: date.New(2025, time.January, 1)}.Age(dob)
+	assert.True(t, ok)
+	assert.Equal(t, 34, age)
+
+	_, ok = Config{Rule: AgeOnRaceDay, RaceDay: raceDay}.Age(date.Date{})
+	assert.False(t, ok)
+	_, ok = Config{Rule: AgeAtYearEnd}.Age(dob)
+	assert.False(t, ok)
+	_, ok = Config{Rule: AgeOnReferenceDate, RaceDay: raceDay}.Age(dob)
+	assert.False(t, ok)
+}
+
+func TestResolve(t *testing.T) {
+	groups := []sesbase.AgeGroup{
+		{ID: 1, AGSet: 1, AgeFrom: 0, AgeTo: 19, OrderPos: 1},
+		{ID: 2, AGSet: 1, AgeFrom: 20, AgeTo: 39, OrderPos: 2},
+		{ID: 3, AGSet: 1, AgeFrom: 40, OrderPos: 3},
+		{ID: 4, AGSet: 1, AgeFrom: 30, AgeTo: 39, Sex: "f", OrderPos: 4},
+		{ID: 5, AGSet: 1, AgeFrom: 20, AgeTo: 49, Contest: 2, OrderPos: 5},
+		{ID: 10, AGSet: 2, Sex: "m"},
+		{ID: 11, AGSet: 2, Sex: "f"},
+		{ID: 20, AGSet: 3, Contest: 3, DateStart: date.New(1990, time.January, 1), DateEnd: date.New(1994, time.December, 31)},
+	}
+	a := NewAssigner(Config{Rule: AgeOnRaceDay, RaceDay: date.New(2024, time.May, 1)}, groups)
+
+	p := sesbase.Participant{ID: 1, Contest: 1, Sex: "M", DateOfBirth: date.New(1990, time.June, 15)}
+	g, issue := a.Resolve(p, 1)
+	assert.Nil(t, issue)
+	assert.Equal(t, 2, g.ID)
+
+	// the sex specific group wins over the general one
+	p.Sex = "f"
+	g, issue = a.Resolve(p, 1)
+	assert.Nil(t, issue)
+	assert.Equal(t, 4, g.ID)
+
+	// the contest specific group wins over the sex specific one
+	p.Contest = 2
+	g, issue = a.Resolve(p, 1)
+	assert.Nil(t, issue)
+	assert.Equal(t, 5, g.ID)
+
+	// without date of birth, only groups without limits match
+	p.DateOfBirth = date.Date{}
+	g, issue = a.Resolve(p, 1)
+	assert.Nil(t, g)
+	assert.Equal(t, &Issue{Participant: 1, AGSet: 1, Kind: Unmatched}, issue)
+	g, issue = a.Resolve(p, 2)
+	assert.Nil(t, issue)
+	assert.Equal(t, 11, g.ID)
+
+	// set 3 only has groups for contest 3
+	g, issue = a.Resolve(p, 3)
+	assert.Nil(t, g)
+	assert.Nil(t, issue)
+	p.Contest = 3
+	g, issue = a.Resolve(p, 3)
+	assert.Nil(t, g)
+	assert.Equal(t, Unmatched, issue.Kind)
+	p.DateOfBirth = date.New(1994, time.December, 31)
+	g, issue = a.Resolve(p, 3)
+	assert.Nil(t, issue)
+	assert.Equal(t, 20, g.ID)
+}
+
+func TestResolveAmbiguous(t *testing.T) {
+	groups := []sesbase.AgeGroup{
+		{ID: 7, AGSet: 1, AgeFrom: 18, AgeTo: 40, OrderPos: 1},
+		{ID: 3, AGSet: 1, AgeFrom: 30, AgeTo: 50, OrderPos: 1},
+		{ID: 5, AGSet: 1, AgeFrom: 30, OrderPos: 2},
+	}
+	a := NewAssigner(Config{Rule: AgeAtYearEnd, RaceDay: date.New(2024, time.May, 1)}, groups)
+
+	p := sesbase.Participant{ID: 12, DateOfBirth: date.New(1990, time.June, 15)}
+	g, issue := a.Resolve(p, 1)
+	assert.Equal(t, 3, g.ID)
+	assert.Equal(t, &Issue{Participant: 12, AGSet: 1, Kind: Ambiguous, Candidates: []int{3, 7}}, issue)
+	assert.Equal(t, "participant 12, set 1: ambiguous (3, 7)", issue.String())
+
+	// the lower OrderPos decides
+	p.DateOfBirth = date.New(1980, time.June, 15)
+	g, issue = a.Resolve(p, 1)
+	assert.Nil(t, issue)
+	assert.Equal(t, 3, g.ID)
+}
+
+func TestAssignAll(t *testing.T) {
+	groups := []sesbase.AgeGroup{
+		{ID: 1, AGSet: 1, AgeTo: 39},
+		{ID: 2, AGSet: 1, AgeFrom: 40},
+		{ID: 3, AGSet: 3, Sex: "m"},
+	}
+	a := NewAssigner(Config{Rule: AgeOnRaceDay, RaceDay: date.New(2024, time.May, 1)}, groups)
+
+	participants := []sesbase.Participant{
+		{ID: 1, Sex: "m", DateOfBirth: date.New(1980, time.January, 1), AgeGroup2: 99},
+		{ID: 2, Sex: "f", DateOfBirth: date.New(1990, time.January, 1)},
+		{ID: 3, Sex: "f"},
+	}
+	issues := a.AssignAll(participants)
+
+	assert.Equal(t, [3]int{2, 0, 3}, [3]int{participants[0].AgeGroup1, participants[0].AgeGroup2, participants[0].AgeGroup3})
+	assert.Equal(t, [3]int{1, 0, 0}, [3]int{participants[1].AgeGroup1, participants[1].AgeGroup2, participants[1].AgeGroup3})
+	assert.Equal(t, [3]int{0, 0, 0}, [3]int{participants[2].AgeGroup1, participants[2].AgeGroup2, participants[2].AgeGroup3})
+	assert.Equal(t, []Issue{
+		{Participant: 2, AGSet: 3, Kind: Unmatched},
+		{Participant: 3, AGSet: 1, Kind: Unmatched},
+		{Participant: 3, AGSet: 3, Kind: Unmatched},
+	}, issues)
+}
@@ -0,0 +1,185 @@
+package agegroup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sesbase "github.com/raceresult/go-model"
+)
+
+// Assigner assigns participants to age groups. The age groups of each AGSet are tested in order
+// of precedence: groups of the participant's contest before groups for all contests (Contest 0),
+// groups of the participant's sex before groups for both sexes (empty Sex), then by OrderPos and ID.
+//
+// A group matches if the contest and the sex match, the age is within AgeFrom and AgeTo and the
+// date of birth within DateStart and DateEnd (both inclusive). AgeTo 0 means no upper limit and
+// zero dates leave the birth date range open; a group without any age or birth date limits
+// matches all participants, also those without date of birth.
+type Assigner struct {
+	Config Config
+	sets   map[int][]sesbase.AgeGroup
+}
+
+// NewAssigner creates an Assigner for the age groups.
+func NewAssigner(config Config, groups []sesbase.AgeGroup) *Assigner {
+	a := &Assigner{Config: config, sets: map[int][]sesbase.AgeGroup{}}
+	for _, g := range groups {
+		a.sets[g.AGSet] = append(a.sets[g.AGSet], g)
+	}
+	return a
+}
+
+// IssueKind is the kind of an Issue.
+type IssueKind int
+
+const (
+	// Unmatched means that no age group of the set matches although the set has age groups for
+	// the contest of the participant.
+	Unmatched IssueKind = iota + 1
+	// Ambiguous means that several age groups of the same precedence match; the first one by ID
+	// is assigned.
+	Ambiguous
+)
+
+// String returns "unmatched" or "ambiguous".
+func (k IssueKind) String() string {
+	switch k {
+	case Unmatched:
+		return "unmatched"
+	case Ambiguous:
+		return "ambiguous"
+	default:
+		return ""
+	}
+}
+
+// Issue reports a participant who could not be assigned unambiguously.
+type Issue struct {
+	Participant int
+	AGSet       int
+	Kind        IssueKind
+	// Candidates are the IDs of the matching age groups of the same precedence if ambiguous.
+	Candidates []int
+}
+
+// String describes the issue, e.g. "participant 12, set 1: ambiguous (3, 4)".
+func (s Issue) String() string {
+	str := fmt.Sprintf("participant %d, set %d: %s", s.Participant, s.AGSet, s.Kind)
+	if len(s.Candidates) > 0 {
+		ids := make([]string, len(s.Candidates))
+		for i, id := range s.Candidates {
+			ids[i] = fmt.Sprint(id)
+		}
+		str += " (" + strings.Join(ids, ", ") + ")"
+	}
+	return str
+}
+
+// Resolve finds the age group of the participant in an AGSet. The result is nil if no group
+// matches. The issue is nil if the assignment is unique or the set has no age groups for the
+// contest of the participant.
+func (a *Assigner) Resolve(p sesbase.Participant, agSet int) (*sesbase.AgeGroup, *Issue) {
+	age, hasAge := a.Config.Age(p.DateOfBirth)
+
+	var candidates []sesbase.AgeGroup
+	relevant := false
+	for _, g := range a.sets[agSet] {
+		if g.Contest != 0 && g.Contest != p.Contest {
+			continue
+		}
+		relevant = true
+		if g.Sex != "" && !strings.EqualFold(g.Sex, p.Sex) {
+			continue
+		}
+		if !matchesBirth(g, p, age, hasAge) {
+			continue
+		}
+		candidates = append(candidates, g)
+	}
+	if len(candidates) == 0 {
+		if !relevant {
+			return nil, nil
+		}
+		return nil, &Issue{Participant: p.ID, AGSet: agSet, Kind: Unmatched}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if c := comparePrecedence(candidates[i], candidates[j]); c != 0 {
+			return c < 0
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	best := candidates[0]
+	var issue *Issue
+	if len(candidates) > 1 && comparePrecedence(best, candidates[1]) == 0 {
+		issue = &Issue{Participant: p.ID, AGSet: agSet, Kind: Ambiguous}
+		for _, g := range candidates {
+			if comparePrecedence(best, g) == 0 {
+				issue.Candidates = append(issue.Candidates, g.ID)
+			}
+		}
+	}
+	return &best, issue
+}
+
+func matchesBirth(g sesbase.AgeGroup, p sesbase.Participant, age int, hasAge bool) bool {
+	if g.AgeFrom != 0 || g.AgeTo != 0 {
+		if !hasAge || age < g.AgeFrom || g.AgeTo != 0 && age > g.AgeTo {
+			return false
+		}
+	}
+	if !g.DateStart.IsZero() || !g.DateEnd.IsZero() {
+		if p.DateOfBirth.IsZero() ||
+			!g.DateStart.IsZero() && p.DateOfBirth.Before(g.DateStart) ||
+			!g.DateEnd.IsZero() && p.DateOfBirth.After(g.DateEnd) {
+			return false
+		}
+	}
+	return true
+}
+
+// comparePrecedence compares the precedence of two matching age groups, negative if a is
+// preferred.
+func comparePrecedence(a, b sesbase.AgeGroup) int {
+	if c := boolRank(a.Contest != 0) - boolRank(b.Contest != 0); c != 0 {
+		return c
+	}
+	if c := boolRank(a.Sex != "") - boolRank(b.Sex != ""); c != 0 {
+		return c
+	}
+	return a.OrderPos - b.OrderPos
+}
+
+func boolRank(specific bool) int {
+	if specific {
+		return 0
+	}
+	return 1
+}
+
+// Assign sets AgeGroup1, AgeGroup2 and AgeGroup3 of the participant to the resolved age groups of
+// AGSet 1 to 3, or 0 if no group matches, and returns the issues.
+func (a *Assigner) Assign(p *sesbase.Participant) []Issue {
+	var issues []Issue
+	for set, field := range []*int{&p.AgeGroup1, &p.AgeGroup2, &p.AgeGroup3} {
+		g, issue := a.Resolve(*p, set+1)
+		*field = 0
+		if g != nil {
+			*field = g.ID
+		}
+		if issue != nil {
+			issues = append(issues, *issue)
+		}
+	}
+	return issues
+}
+
+// AssignAll assigns all participants like Assign and returns the issues of all participants.
+func (a *Assigner) AssignAll(participants []sesbase.Participant) []Issue {
+	var issues []Issue
+	for i := range participants {
+		issues = append(issues, a.Assign(&participants[i])...)
+	}
+	return issues
+}
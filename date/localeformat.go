@@ -0,0 +1,269 @@
+package date
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// FormatStyle selects one of the default date or time patterns of a locale.
+type FormatStyle int
+
+const (
+	// FormatNone omits the date or time.
+	FormatNone FormatStyle = iota
+	// FormatShort is numeric, e.g. 01.05.24 in German.
+	FormatShort
+	// FormatMedium has an abbreviated month name or a 4-digit year, e.g. 01.05.2024 in German.
+	FormatMedium
+	// FormatLong has the wide month name, e.g. 1. Mai 2024 in German.
+	FormatLong
+	// FormatFull adds the weekday, e.g. Mittwoch, 1. Mai 2024 in German.
+	FormatFull
+)
+
+// localePatterns contains the CLDR default patterns of a language.
+type localePatterns struct {
+	date     [4]string // short, medium, long, full
+	time     [2]string // short, medium
+	dateTime [4]string // patterns joining date {1} and time {0} by date style: short, medium, long, full
+	amPm     [2]string // abbreviated day periods
+}
+
+var patternTable = map[string]localePatterns{
+	"en": {[4]string{"M/d/yy", "MMM d, y", "MMMM d, y", "EEEE, MMMM d, y"}, [2]string{"h:mm a", "h:mm:ss a"},
+		[4]string{"{1}, {0}", "{1}, {0}", "{1} 'at' {0}", "{1} 'at' {0}"}, [2]string{"AM", "PM"}},
+	"de": {[4]string{"dd.MM.yy", "dd.MM.y", "d. MMMM y", "EEEE, d. MMMM y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1}, {0}", "{1}, {0}", "{1} 'um' {0}", "{1} 'um' {0}"}, [2]string{"AM", "PM"}},
+	"fr": {[4]string{"dd/MM/y", "d MMM y", "d MMMM y", "EEEE d MMMM y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1} {0}", "{1} {0}", "{1} 'à' {0}", "{1} 'à' {0}"}, [2]string{"AM", "PM"}},
+	"es": {[4]string{"d/M/yy", "d MMM y", "d 'de' MMMM 'de' y", "EEEE, d 'de' MMMM 'de' y"}, [2]string{"H:mm", "H:mm:ss"},
+		[4]string{"{1}, {0}", "{1}, {0}", "{1}, {0}", "{1}, {0}"}, [2]string{"a.\u00a0m.", "p.\u00a0m."}},
+	"it": {[4]string{"dd/MM/yy", "d MMM y", "d MMMM y", "EEEE d MMMM y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1}, {0}", "{1}, {0}", "{1} {0}", "{1} {0}"}, [2]string{"AM", "PM"}},
+	"nl": {[4]string{"dd-MM-y", "d MMM y", "d MMMM y", "EEEE d MMMM y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1} {0}", "{1} {0}", "{1} 'om' {0}", "{1} 'om' {0}"}, [2]string{"a.m.", "p.m."}},
+	"pt": {[4]string{"dd/MM/y", "d 'de' MMM 'de' y", "d 'de' MMMM 'de' y", "EEEE, d 'de' MMMM 'de' y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"}, [2]string{"AM", "PM"}},
+	"da": {[4]string{"dd.MM.y", "d. MMM y", "d. MMMM y", "EEEE 'den' d. MMMM y"}, [2]string{"HH.mm", "HH.mm.ss"},
+		[4]string{"{1} {0}", "{1} {0}", "{1} 'kl'. {0}", "{1} 'kl'. {0}"}, [2]string{"AM", "PM"}},
+	"sv": {[4]string{"y-MM-dd", "d MMM y", "d MMMM y", "EEEE d MMMM y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1} {0}", "{1} {0}", "{1} {0}", "{1} {0}"}, [2]string{"fm", "em"}},
+	"pl": {[4]string{"d.MM.y", "d MMM y", "d MMMM y", "EEEE, d MMMM y"}, [2]string{"HH:mm", "HH:mm:ss"},
+		[4]string{"{1}, {0}", "{1}, {0}", "{1} 'o' {0}", "{1} 'o' {0}"}, [2]string{"AM", "PM"}},
+	"cs": {[4]string{"dd.MM.yy", "d. M. y", "d. MMMM y", "EEEE d. MMMM y"}, [2]string{"H:mm", "H:mm:ss"},
+		[4]string{"{1} {0}", "{1} {0}", "{1} 'v' {0}", "{1} 'v' {0}"}, [2]string{"dop.", "odp."}},
+}
+
+func patternsOf(tag language.Tag) localePatterns {
+	base, _ := tag.Base()
+	if p, ok := patternTable[base.String()]; ok {
+		return p
+	}
+	return patternTable["en"]
+}
+
+// DatePattern returns the CLDR date pattern of the locale for the style, e.g. "d. MMMM y" for German
+// and FormatLong. Unsupported languages use the English patterns, FormatNone returns "".
+func DatePattern(tag language.Tag, style FormatStyle) string {
+	if style < FormatShort || style > FormatFull {
+		return ""
+	}
+	return patternsOf(tag).date[style-FormatShort]
+}
+
+// TimePattern returns the CLDR time pattern of the locale for the style, hours and minutes for
+// FormatShort and with seconds for longer styles. FormatNone returns "".
+func TimePattern(tag language.Tag, style FormatStyle) string {
+	switch {
+	case style == FormatNone:
+		return ""
+	case style == FormatShort:
+		return patternsOf(tag).time[0]
+	default:
+		return patternsOf(tag).time[1]
+	}
+}
+
+// DateTimePattern combines the date and time patterns of the locale, see JoinDateTime.
+func DateTimePattern(tag language.Tag, dateStyle, timeStyle FormatStyle) string {
+	return JoinDateTime(tag, DatePattern(tag, dateStyle), TimePattern(tag, timeStyle))
+}
+
+// JoinDateTime joins a date and a time pattern with the CLDR date time pattern of the locale, e.g.
+// "{1} 'um' {0}" for the German long date pattern. The style is chosen by the date pattern, custom
+// date patterns are joined like medium ones. If one of the patterns is empty, the other is returned.
+func JoinDateTime(tag language.Tag, datePattern, timePattern string) string {
+	if datePattern == "" || timePattern == "" {
+		return datePattern + timePattern
+	}
+	p := patternsOf(tag)
+	glue := p.dateTime[FormatMedium-FormatShort]
+	for i, d := range p.date {
+		if d == datePattern {
+			glue = p.dateTime[i]
+			break
+		}
+	}
+	return strings.NewReplacer("{1}", datePattern, "{0}", timePattern).Replace(glue)
+}
+
+// FormatPattern formats the time with a CLDR pattern using the month and weekday names of the locale.
+// Supported fields are y, yy, yyyy (year), M, MM, MMM, MMMM (month in dates), L to LLLL (standalone month),
+// d, dd (day), E to EEE, EEEE (weekday), H, HH, h, hh (hour), m, mm (minute), s, ss (second),
+// S... (fraction) and a (day period of the locale, e.g. AM/PM). Text in single quotes is copied, two
+// single quotes result in one.
+func FormatPattern(t time.Time, pattern string, tag language.Tag) string {
+	names, _ := LocaleNames(tag)
+	amPm := patternsOf(tag).amPm
+	var sb strings.Builder
+	rs := []rune(pattern)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		if r == '\'' {
+			j := i + 1
+			if j < len(rs) && rs[j] == '\'' {
+				sb.WriteRune('\'')
+				i += 2
+				continue
+			}
+			for ; j < len(rs); j++ {
+				if rs[j] == '\'' {
+					if j+1 < len(rs) && rs[j+1] == '\'' {
+						sb.WriteRune('\'')
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteRune(rs[j])
+			}
+			i = j + 1
+			continue
+		}
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			sb.WriteRune(r)
+			i++
+			continue
+		}
+
+		n := 1
+		for i+n < len(rs) && rs[i+n] == r {
+			n++
+		}
+		i += n
+		switch r {
+		case 'y':
+			switch n {
+			case 2:
+				sb.WriteString(pad(t.Year()%100, 2))
+			case 1:
+				sb.WriteString(strconv.Itoa(t.Year()))
+			default:
+				sb.WriteString(pad(t.Year(), n))
+			}
+		case 'M', 'L':
+			switch {
+			case n >= 4 && r == 'M':
+				sb.WriteString(names.Month(t.Month()))
+			case n >= 4:
+				sb.WriteString(names.MonthStandalone(t.Month()))
+			case n == 3:
+				sb.WriteString(names.MonthShort(t.Month()))
+			default:
+				sb.WriteString(pad(int(t.Month()), n))
+			}
+		case 'd':
+			sb.WriteString(pad(t.Day(), n))
+		case 'E':
+			if n >= 4 {
+				sb.WriteString(names.Weekday(t.Weekday()))
+			} else {
+				sb.WriteString(names.WeekdayShort(t.Weekday()))
+			}
+		case 'H':
+			sb.WriteString(pad(t.Hour(), n))
+		case 'h':
+			h := t.Hour() % 12
+			if h == 0 {
+				h = 12
+			}
+			sb.WriteString(pad(h, n))
+		case 'm':
+			sb.WriteString(pad(t.Minute(), n))
+		case 's':
+			sb.WriteString(pad(t.Second(), n))
+		case 'S':
+			frac := pad(t.Nanosecond(), 9)
+			for len(frac) < n {
+				frac += "0"
+			}
+			sb.WriteString(frac[:n])
+		case 'a':
+			if t.Hour() < 12 {
+				sb.WriteString(amPm[0])
+			} else {
+				sb.WriteString(amPm[1])
+			}
+		default:
+			sb.WriteString(strings.Repeat(string(r), n))
+		}
+	}
+	return sb.String()
+}
+
+func pad(x, n int) string {
+	s := strconv.Itoa(x)
+	for len(s) < n {
+		s = "0" + s
+	}
+	return s
+}
+
+// FormatLocale formats the date with the default pattern of the locale, e.g. "1. Mai 2024" for
+// German and FormatLong. The zero date results in an empty string.
+func (d Date) FormatLocale(tag language.Tag, style FormatStyle) string {
+	return d.FormatPattern(DatePattern(tag, style), tag)
+}
+
+// FormatPattern formats the date with a CLDR pattern such as "EEEE, d. MMMM y", see FormatPattern.
+// The zero date results in an empty string.
+func (d Date) FormatPattern(pattern string, tag language.Tag) string {
+	if d.IsZero() {
+		return ""
+	}
+	return FormatPattern(decode(d.day), pattern, tag)
+}
+
+// ParseLocaleFormat parses a localised date format as it can be used instead of a Go layout with the
+// ToStringWithDateFormat functions of the datetime and variant packages. The format starts with @
+// and the language, followed by a colon and either a style (short, medium, long, full) or a CLDR
+// pattern, e.g. "@de:long" or "@fr:EEEE d MMMM y". The result is false if the string is no
+// localised format.
+func ParseLocaleFormat(format string) (language.Tag, string, bool) {
+	if !strings.HasPrefix(format, "@") {
+		return language.Und, "", false
+	}
+	p := strings.IndexByte(format, ':')
+	if p < 0 {
+		return language.Und, "", false
+	}
+	tag, err := language.Parse(format[1:p])
+	if err != nil {
+		return language.Und, "", false
+	}
+	pattern := format[p+1:]
+	switch strings.ToLower(pattern) {
+	case "short":
+		pattern = DatePattern(tag, FormatShort)
+	case "medium", "":
+		pattern = DatePattern(tag, FormatMedium)
+	case "long":
+		pattern = DatePattern(tag, FormatLong)
+	case "full":
+		pattern = DatePattern(tag, FormatFull)
+	}
+	return tag, pattern, true
+}
@@ -0,0 +1,74 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestDate_FormatLocale(t *testing.T) {
+	d := New(2024, 5, 1)
+	tests := []struct {
+		tag      string
+		style    FormatStyle
+		expected string
+	}{
+		{"en", FormatShort, "5/1/24"},
+		{"en", FormatMedium, "May 1, 2024"},
+		{"en", FormatFull, "Wednesday, May 1, 2024"},
+		{"de", FormatShort, "01.05.24"},
+		{"de", FormatMedium, "01.05.2024"},
+		{"de-AT", FormatLong, "1. Mai 2024"},
+		{"de", FormatFull, "Mittwoch, 1. Mai 2024"},
+		{"fr", FormatMedium, "1 mai 2024"},
+		{"fr", FormatFull, "mercredi 1 mai 2024"},
+		{"nl", FormatLong, "1 mei 2024"},
+		{"es", FormatLong, "1 de mayo de 2024"},
+		{"pl", FormatLong, "1 maja 2024"},
+		{"cs", FormatLong, "1. května 2024"},
+		{"da", FormatFull, "onsdag den 1. maj 2024"},
+		{"ja", FormatLong, "May 1, 2024"},
+		{"de", FormatNone, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, d.FormatLocale(language.MustParse(tt.tag), tt.style), "%s %d", tt.tag, tt.style)
+	}
+	assert.Equal(t, "", ZeroDateVB.FormatLocale(language.German, FormatLong))
+}
+
+func TestFormatPattern(t *testing.T) {
+	x := time.Date(2024, 9, 3, 20, 5, 9, 123456789, time.UTC)
+	assert.Equal(t, "Di., 3. Sept. 2024", FormatPattern(x, "EEE, d. MMM y", language.German))
+	assert.Equal(t, "wrzesień 2024", FormatPattern(x, "LLLL y", language.Polish))
+	assert.Equal(t, "3 września 2024", FormatPattern(x, "d MMMM y", language.Polish))
+	assert.Equal(t, "8:05:09.12 PM", FormatPattern(x, "h:mm:ss.SS a", language.English))
+	assert.Equal(t, "8:05 p.\u00a0m.", FormatPattern(x, "h:mm a", language.Spanish))
+	assert.Equal(t, "8:05 em", FormatPattern(x, "h:mm a", language.Swedish))
+	assert.Equal(t, "8:05 PM", FormatPattern(x, "h:mm a", language.Japanese))
+	assert.Equal(t, "20h05 o'clock 09", FormatPattern(x, "HH'h'mm 'o''clock' ss", language.English))
+	assert.Equal(t, "24/2024/09/9", FormatPattern(x, "yy/yyyy/MM/M", language.English))
+	assert.Equal(t, "dd.MM.y, HH:mm", DateTimePattern(language.German, FormatMedium, FormatShort))
+	assert.Equal(t, "d. MMMM y 'um' HH:mm:ss", DateTimePattern(language.German, FormatLong, FormatMedium))
+	assert.Equal(t, "MMM d, y, h:mm a", DateTimePattern(language.English, FormatMedium, FormatShort))
+	assert.Equal(t, "d MMMM y 'om' HH:mm", JoinDateTime(language.Dutch, "d MMMM y", "HH:mm"))
+	assert.Equal(t, "d MMMM HH:mm", JoinDateTime(language.Dutch, "d MMMM", "HH:mm"))
+	assert.Equal(t, "h:mm a", DateTimePattern(language.English, FormatNone, FormatShort))
+}
+
+func TestParseLocaleFormat(t *testing.T) {
+	tag, pattern, ok := ParseLocaleFormat("@de:long")
+	assert.True(t, ok)
+	assert.Equal(t, language.German, tag)
+	assert.Equal(t, "d. MMMM y", pattern)
+
+	_, pattern, ok = ParseLocaleFormat("@fr:EEEE d MMMM")
+	assert.True(t, ok)
+	assert.Equal(t, "EEEE d MMMM", pattern)
+
+	for _, s := range []string{"02.01.2006", "@de", "@???:long"} {
+		_, _, ok = ParseLocaleFormat(s)
+		assert.False(t, ok, s)
+	}
+}
@@ -0,0 +1,180 @@
+package date
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Names contains the localised month and weekday names of a language as defined by CLDR.
+type Names struct {
+	Language language.Tag
+	// Months are the wide month names as used in dates, e.g. "1. Mai 2024" or "1 maja 2024".
+	Months [12]string
+	// MonthsStandalone are the wide month names used without day, e.g. "maj 2024". They only differ
+	// from Months in languages with grammatical cases such as Polish or Czech.
+	MonthsStandalone [12]string
+	// MonthsShort are the abbreviated month names.
+	MonthsShort [12]string
+	// Weekdays are the wide weekday names, starting with Sunday as time.Weekday.
+	Weekdays [7]string
+	// WeekdaysShort are the abbreviated weekday names, starting with Sunday.
+	WeekdaysShort [7]string
+}
+
+// Month returns the wide name of the month as used in dates.
+func (s Names) Month(m time.Month) string {
+	return s.Months[m-1]
+}
+
+// MonthStandalone returns the wide name of the month as used without day.
+func (s Names) MonthStandalone(m time.Month) string {
+	if x := s.MonthsStandalone[m-1]; x != "" {
+		return x
+	}
+	return s.Months[m-1]
+}
+
+// MonthShort returns the abbreviated name of the month.
+func (s Names) MonthShort(m time.Month) string {
+	return s.MonthsShort[m-1]
+}
+
+// Weekday returns the wide name of the weekday.
+func (s Names) Weekday(d time.Weekday) string {
+	return s.Weekdays[d]
+}
+
+// WeekdayShort returns the abbreviated name of the weekday.
+func (s Names) WeekdayShort(d time.Weekday) string {
+	return s.WeekdaysShort[d]
+}
+
+// LookupMonth finds the month by its wide, standalone or abbreviated name. The comparison is
+// case-insensitive and ignores a trailing dot.
+func (s Names) LookupMonth(name string) (time.Month, bool) {
+	name = normalizeName(name)
+	for i := 0; i < 12; i++ {
+		if name == normalizeName(s.Months[i]) || name == normalizeName(s.MonthsShort[i]) ||
+			(s.MonthsStandalone[i] != "" && name == normalizeName(s.MonthsStandalone[i])) {
+			return time.Month(i + 1), true
+		}
+	}
+	return 0, false
+}
+
+// LookupWeekday finds the weekday by its wide or abbreviated name. The comparison is case-insensitive
+// and ignores a trailing dot.
+func (s Names) LookupWeekday(name string) (time.Weekday, bool) {
+	name = normalizeName(name)
+	for i := 0; i < 7; i++ {
+		if name == normalizeName(s.Weekdays[i]) || name == normalizeName(s.WeekdaysShort[i]) {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+func normalizeName(s string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(s), "."))
+}
+
+var namesTable = []Names{
+	{
+		Language:      language.English,
+		Months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		MonthsShort:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		WeekdaysShort: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	},
+	{
+		Language:      language.German,
+		Months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		MonthsShort:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		Weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		WeekdaysShort: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+	},
+	{
+		Language:      language.French,
+		Months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		MonthsShort:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		WeekdaysShort: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+	},
+	{
+		Language:      language.Spanish,
+		Months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		MonthsShort:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		WeekdaysShort: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+	},
+	{
+		Language:      language.Italian,
+		Months:        [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		MonthsShort:   [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		Weekdays:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		WeekdaysShort: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+	},
+	{
+		Language:      language.Dutch,
+		Months:        [12]string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		MonthsShort:   [12]string{"jan", "feb", "mrt", "apr", "mei", "jun", "jul", "aug", "sep", "okt", "nov", "dec"},
+		Weekdays:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		WeekdaysShort: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+	},
+	{
+		Language:      language.Portuguese,
+		Months:        [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		MonthsShort:   [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		Weekdays:      [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		WeekdaysShort: [7]string{"dom.", "seg.", "ter.", "qua.", "qui.", "sex.", "sáb."},
+	},
+	{
+		Language:      language.Danish,
+		Months:        [12]string{"januar", "februar", "marts", "april", "maj", "juni", "juli", "august", "september", "oktober", "november", "december"},
+		MonthsShort:   [12]string{"jan.", "feb.", "mar.", "apr.", "maj", "jun.", "jul.", "aug.", "sep.", "okt.", "nov.", "dec."},
+		Weekdays:      [7]string{"søndag", "mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag"},
+		WeekdaysShort: [7]string{"søn.", "man.", "tirs.", "ons.", "tors.", "fre.", "lør."},
+	},
+	{
+		Language:      language.Swedish,
+		Months:        [12]string{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september", "oktober", "november", "december"},
+		MonthsShort:   [12]string{"jan.", "feb.", "mars", "apr.", "maj", "juni", "juli", "aug.", "sep.", "okt.", "nov.", "dec."},
+		Weekdays:      [7]string{"söndag", "måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag"},
+		WeekdaysShort: [7]string{"sön", "mån", "tis", "ons", "tors", "fre", "lör"},
+	},
+	{
+		Language:         language.Polish,
+		Months:           [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+		MonthsStandalone: [12]string{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec", "lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień"},
+		MonthsShort:      [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+		Weekdays:         [7]string{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"},
+		WeekdaysShort:    [7]string{"niedz.", "pon.", "wt.", "śr.", "czw.", "pt.", "sob."},
+	},
+	{
+		Language:         language.Czech,
+		Months:           [12]string{"ledna", "února", "března", "dubna", "května", "června", "července", "srpna", "září", "října", "listopadu", "prosince"},
+		MonthsStandalone: [12]string{"leden", "únor", "březen", "duben", "květen", "červen", "červenec", "srpen", "září", "říjen", "listopad", "prosinec"},
+		MonthsShort:      [12]string{"led", "úno", "bře", "dub", "kvě", "čvn", "čvc", "srp", "zář", "říj", "lis", "pro"},
+		Weekdays:         [7]string{"neděle", "pondělí", "úterý", "středa", "čtvrtek", "pátek", "sobota"},
+		WeekdaysShort:    [7]string{"ne", "po", "út", "st", "čt", "pá", "so"},
+	},
+}
+
+// LocaleNames returns the month and weekday names for the language of the tag. The result is
+// false if the language is not supported; English names are returned in that case.
+func LocaleNames(tag language.Tag) (Names, bool) {
+	base, _ := tag.Base()
+	for _, n := range namesTable {
+		if b, _ := n.Language.Base(); b == base {
+			return n, true
+		}
+	}
+	return namesTable[0], false
+}
+
+// AllLocaleNames returns the names of all supported languages.
+func AllLocaleNames() []Names {
+	return append([]Names(nil), namesTable...)
+}
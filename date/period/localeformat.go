@@ -0,0 +1,238 @@
+package period
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	textplural "golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// UnitNames contains the names of a period unit for each CLDR plural form of a language. Each name
+// must include a "%v" placeholder for the number. Forms that a language does not use can be left
+// empty; Other is used instead.
+type UnitNames struct {
+	Zero, One, Two, Few, Many, Other string
+}
+
+func (u UnitNames) format(form textplural.Form, number string) string {
+	var s string
+	switch form {
+	case textplural.Zero:
+		s = u.Zero
+	case textplural.One:
+		s = u.One
+	case textplural.Two:
+		s = u.Two
+	case textplural.Few:
+		s = u.Few
+	case textplural.Many:
+		s = u.Many
+	}
+	if s == "" {
+		s = u.Other
+	}
+	return fmt.Sprintf(s, number)
+}
+
+// LocaleNames contains the localised unit names used by FormatLocale. The plural form of each
+// number is chosen with the CLDR plural rules of Language.
+type LocaleNames struct {
+	Language         language.Tag
+	DecimalSeparator string
+	Separator        string
+
+	Years, Months, Weeks, Days, Hours, Minutes, Seconds UnitNames
+}
+
+var localeNamesTable = []LocaleNames{
+	{
+		Language: language.English, DecimalSeparator: ".", Separator: ", ",
+		Years:   UnitNames{One: "%v year", Other: "%v years"},
+		Months:  UnitNames{One: "%v month", Other: "%v months"},
+		Weeks:   UnitNames{One: "%v week", Other: "%v weeks"},
+		Days:    UnitNames{One: "%v day", Other: "%v days"},
+		Hours:   UnitNames{One: "%v hour", Other: "%v hours"},
+		Minutes: UnitNames{One: "%v minute", Other: "%v minutes"},
+		Seconds: UnitNames{One: "%v second", Other: "%v seconds"},
+	},
+	{
+		Language: language.German, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v Jahr", Other: "%v Jahre"},
+		Months:  UnitNames{One: "%v Monat", Other: "%v Monate"},
+		Weeks:   UnitNames{One: "%v Woche", Other: "%v Wochen"},
+		Days:    UnitNames{One: "%v Tag", Other: "%v Tage"},
+		Hours:   UnitNames{One: "%v Stunde", Other: "%v Stunden"},
+		Minutes: UnitNames{One: "%v Minute", Other: "%v Minuten"},
+		Seconds: UnitNames{One: "%v Sekunde", Other: "%v Sekunden"},
+	},
+	{
+		Language: language.French, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v an", Other: "%v ans"},
+		Months:  UnitNames{One: "%v mois", Other: "%v mois"},
+		Weeks:   UnitNames{One: "%v semaine", Other: "%v semaines"},
+		Days:    UnitNames{One: "%v jour", Other: "%v jours"},
+		Hours:   UnitNames{One: "%v heure", Other: "%v heures"},
+		Minutes: UnitNames{One: "%v minute", Other: "%v minutes"},
+		Seconds: UnitNames{One: "%v seconde", Other: "%v secondes"},
+	},
+	{
+		Language: language.Spanish, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v año", Other: "%v años"},
+		Months:  UnitNames{One: "%v mes", Other: "%v meses"},
+		Weeks:   UnitNames{One: "%v semana", Other: "%v semanas"},
+		Days:    UnitNames{One: "%v día", Other: "%v días"},
+		Hours:   UnitNames{One: "%v hora", Other: "%v horas"},
+		Minutes: UnitNames{One: "%v minuto", Other: "%v minutos"},
+		Seconds: UnitNames{One: "%v segundo", Other: "%v segundos"},
+	},
+	{
+		Language: language.Italian, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v anno", Other: "%v anni"},
+		Months:  UnitNames{One: "%v mese", Other: "%v mesi"},
+		Weeks:   UnitNames{One: "%v settimana", Other: "%v settimane"},
+		Days:    UnitNames{One: "%v giorno", Other: "%v giorni"},
+		Hours:   UnitNames{One: "%v ora", Other: "%v ore"},
+		Minutes: UnitNames{One: "%v minuto", Other: "%v minuti"},
+		Seconds: UnitNames{One: "%v secondo", Other: "%v secondi"},
+	},
+	{
+		Language: language.Dutch, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v jaar", Other: "%v jaar"},
+		Months:  UnitNames{One: "%v maand", Other: "%v maanden"},
+		Weeks:   UnitNames{One: "%v week", Other: "%v weken"},
+		Days:    UnitNames{One: "%v dag", Other: "%v dagen"},
+		Hours:   UnitNames{One: "%v uur", Other: "%v uur"},
+		Minutes: UnitNames{One: "%v minuut", Other: "%v minuten"},
+		Seconds: UnitNames{One: "%v seconde", Other: "%v seconden"},
+	},
+	{
+		Language: language.Portuguese, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v ano", Other: "%v anos"},
+		Months:  UnitNames{One: "%v mês", Other: "%v meses"},
+		Weeks:   UnitNames{One: "%v semana", Other: "%v semanas"},
+		Days:    UnitNames{One: "%v dia", Other: "%v dias"},
+		Hours:   UnitNames{One: "%v hora", Other: "%v horas"},
+		Minutes: UnitNames{One: "%v minuto", Other: "%v minutos"},
+		Seconds: UnitNames{One: "%v segundo", Other: "%v segundos"},
+	},
+	{
+		Language: language.Danish, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v år", Other: "%v år"},
+		Months:  UnitNames{One: "%v måned", Other: "%v måneder"},
+		Weeks:   UnitNames{One: "%v uge", Other: "%v uger"},
+		Days:    UnitNames{One: "%v dag", Other: "%v dage"},
+		Hours:   UnitNames{One: "%v time", Other: "%v timer"},
+		Minutes: UnitNames{One: "%v minut", Other: "%v minutter"},
+		Seconds: UnitNames{One: "%v sekund", Other: "%v sekunder"},
+	},
+	{
+		Language: language.Swedish, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v år", Other: "%v år"},
+		Months:  UnitNames{One: "%v månad", Other: "%v månader"},
+		Weeks:   UnitNames{One: "%v vecka", Other: "%v veckor"},
+		Days:    UnitNames{One: "%v dag", Other: "%v dagar"},
+		Hours:   UnitNames{One: "%v timme", Other: "%v timmar"},
+		Minutes: UnitNames{One: "%v minut", Other: "%v minuter"},
+		Seconds: UnitNames{One: "%v sekund", Other: "%v sekunder"},
+	},
+	{
+		Language: language.Polish, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v rok", Few: "%v lata", Many: "%v lat", Other: "%v roku"},
+		Months:  UnitNames{One: "%v miesiąc", Few: "%v miesiące", Many: "%v miesięcy", Other: "%v miesiąca"},
+		Weeks:   UnitNames{One: "%v tydzień", Few: "%v tygodnie", Many: "%v tygodni", Other: "%v tygodnia"},
+		Days:    UnitNames{One: "%v dzień", Few: "%v dni", Many: "%v dni", Other: "%v dnia"},
+		Hours:   UnitNames{One: "%v godzina", Few: "%v godziny", Many: "%v godzin", Other: "%v godziny"},
+		Minutes: UnitNames{One: "%v minuta", Few: "%v minuty", Many: "%v minut", Other: "%v minuty"},
+		Seconds: UnitNames{One: "%v sekunda", Few: "%v sekundy", Many: "%v sekund", Other: "%v sekundy"},
+	},
+	{
+		Language: language.Czech, DecimalSeparator: ",", Separator: ", ",
+		Years:   UnitNames{One: "%v rok", Few: "%v roky", Many: "%v roku", Other: "%v let"},
+		Months:  UnitNames{One: "%v měsíc", Few: "%v měsíce", Many: "%v měsíce", Other: "%v měsíců"},
+		Weeks:   UnitNames{One: "%v týden", Few: "%v týdny", Many: "%v týdne", Other: "%v týdnů"},
+		Days:    UnitNames{One: "%v den", Few: "%v dny", Many: "%v dne", Other: "%v dní"},
+		Hours:   UnitNames{One: "%v hodina", Few: "%v hodiny", Many: "%v hodiny", Other: "%v hodin"},
+		Minutes: UnitNames{One: "%v minuta", Few: "%v minuty", Many: "%v minuty", Other: "%v minut"},
+		Seconds: UnitNames{One: "%v sekunda", Few: "%v sekundy", Many: "%v sekundy", Other: "%v sekund"},
+	},
+}
+
+// LocaleNamesOf returns the unit names for the language of the tag. The result is false if the
+// language is not supported; English names are returned in that case.
+func LocaleNamesOf(tag language.Tag) (LocaleNames, bool) {
+	base, _ := tag.Base()
+	for _, n := range localeNamesTable {
+		if b, _ := n.Language.Base(); b == base {
+			return n, true
+		}
+	}
+	return localeNamesTable[0], false
+}
+
+// FormatLocale converts the period to human-readable form in the language of the tag, e.g.
+// "3 Tage, 4 Stunden" in German. Multiples of 7 days are shown as weeks. Unlike Format, the
+// CLDR plural rules of the language are used, so fractions such as "0.5 days" are plural in English.
+// Unsupported languages are formatted in English.
+func (period Period) FormatLocale(tag language.Tag) string {
+	names, _ := LocaleNamesOf(tag)
+	return period.FormatWithLocaleNames(names, true)
+}
+
+// FormatLocaleWithoutWeeks is like FormatLocale but multiples of 7 days are not shown as weeks.
+func (period Period) FormatLocaleWithoutWeeks(tag language.Tag) string {
+	names, _ := LocaleNamesOf(tag)
+	return period.FormatWithLocaleNames(names, false)
+}
+
+// FormatWithLocaleNames converts the period to human-readable form using the given names.
+func (period Period) FormatWithLocaleNames(names LocaleNames, withWeeks bool) string {
+	period = period.Abs()
+
+	parts := make([]string, 0)
+	add := func(u UnitNames, v int16) {
+		if v != 0 {
+			parts = append(parts, names.formatUnit(u, v))
+		}
+	}
+	add(names.Years, period.years)
+	add(names.Months, period.months)
+
+	if period.IsZero() {
+		parts = append(parts, names.formatUnit(names.Days, 0))
+	} else if withWeeks {
+		weeks := period.days / 70
+		add(names.Weeks, weeks*10)
+		add(names.Days, period.days%70)
+	} else {
+		add(names.Days, period.days)
+	}
+	add(names.Hours, period.hours)
+	add(names.Minutes, period.minutes)
+	add(names.Seconds, period.seconds)
+
+	sep := names.Separator
+	if sep == "" {
+		sep = ", "
+	}
+	return strings.Join(parts, sep)
+}
+
+// formatUnit formats a field stored in tenths with the plural form of the number.
+func (names LocaleNames) formatUnit(u UnitNames, v int16) string {
+	i, f := int(v/10), int(v%10)
+	number := strconv.Itoa(i)
+	var form textplural.Form
+	if f == 0 {
+		form = textplural.Cardinal.MatchPlural(names.Language, i, 0, 0, 0, 0)
+	} else {
+		sep := names.DecimalSeparator
+		if sep == "" {
+			sep = "."
+		}
+		number += sep + strconv.Itoa(f)
+		form = textplural.Cardinal.MatchPlural(names.Language, i, 1, 1, f, f)
+	}
+	return u.format(form, number)
+}
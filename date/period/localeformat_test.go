@@ -0,0 +1,86 @@
+package period
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/language"
+)
+
+func TestPeriodFormatLocale(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		period string
+		tag    language.Tag
+		expect string
+	}{
+		{"P3DT4H", language.English, "3 days, 4 hours"},
+		{"P1DT1H", language.English, "1 day, 1 hour"},
+		{"P0.5D", language.English, "0.5 days"},
+		{"P0D", language.English, "0 days"},
+		{"P1Y1M8D", language.English, "1 year, 1 month, 1 week, 1 day"},
+		{"P3DT4H", language.Make("en-GB"), "3 days, 4 hours"},
+		{"P3DT4H", language.Japanese, "3 days, 4 hours"},
+
+		{"P3DT4H", language.German, "3 Tage, 4 Stunden"},
+		{"P1DT1H1M", language.German, "1 Tag, 1 Stunde, 1 Minute"},
+		{"PT1.5H", language.German, "1,5 Stunden"},
+		{"P0D", language.German, "0 Tage"},
+		{"P2W", language.German, "2 Wochen"},
+
+		{"P1.5D", language.French, "1,5 jour"},
+		{"P2DT1H", language.French, "2 jours, 1 heure"},
+		{"P3M", language.Spanish, "3 meses"},
+		{"P1Y2M", language.Italian, "1 anno, 2 mesi"},
+		{"P2YT1H", language.Dutch, "2 jaar, 1 uur"},
+		{"P1DT2H", language.Portuguese, "1 dia, 2 horas"},
+		{"P2DT1M", language.Danish, "2 dage, 1 minut"},
+		{"P2DT1S", language.Swedish, "2 dagar, 1 sekund"},
+
+		{"P1D", language.Polish, "1 dzień"},
+		{"PT2H", language.Polish, "2 godziny"},
+		{"PT5H", language.Polish, "5 godzin"},
+		{"PT12H", language.Polish, "12 godzin"},
+		{"PT22H", language.Polish, "22 godziny"},
+		{"PT1.5H", language.Polish, "1,5 godziny"},
+		{"P2Y", language.Polish, "2 lata"},
+		{"P5Y", language.Polish, "5 lat"},
+
+		{"P1D", language.Czech, "1 den"},
+		{"P3D", language.Czech, "3 dny"},
+		{"P5D", language.Czech, "5 dní"},
+		{"P1.5D", language.Czech, "1,5 dne"},
+		{"PT22H", language.Czech, "22 hodin"},
+	}
+	for i, c := range cases {
+		p := MustParse(c.period, false)
+		g.Expect(p.FormatLocale(c.tag)).To(Equal(c.expect), info(i, "%s %s -> %s", p, c.tag, c.expect))
+		g.Expect(p.Negate().FormatLocale(c.tag)).To(Equal(c.expect), info(i, "-%s %s -> %s", p, c.tag, c.expect))
+	}
+}
+
+func TestPeriodFormatLocaleWithoutWeeks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(MustParse("P14DT3H", false).FormatLocaleWithoutWeeks(language.German)).To(Equal("14 Tage, 3 Stunden"))
+	g.Expect(MustParse("P14DT3H", false).FormatLocale(language.German)).To(Equal("2 Wochen, 3 Stunden"))
+}
+
+func TestLocaleNamesOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, tag := range []language.Tag{language.German, language.French, language.Spanish, language.Italian,
+		language.Dutch, language.Polish, language.Czech, language.Danish, language.Swedish, language.Portuguese} {
+		n, ok := LocaleNamesOf(tag)
+		g.Expect(ok).To(BeTrue(), tag.String())
+		g.Expect(n.Language).To(Equal(tag))
+	}
+	n, ok := LocaleNamesOf(language.Make("de-AT"))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(n.Language).To(Equal(language.German))
+
+	n, ok = LocaleNamesOf(language.Japanese)
+	g.Expect(ok).To(BeFalse())
+	g.Expect(n.Language).To(Equal(language.English))
+}
@@ -56,6 +56,46 @@ func NewMonthOf(year int, month time.Month) DateRange {
 	return DateRange{start, date.PeriodOfDays(end.Sub(start))}
 }
 
+// NewISOWeekOf constructs the range encompassing the ISO 8601 week specified, from Monday to
+// Sunday. Week 1 is the week with the first Thursday of the year, see date.Date.ISOWeek.
+func NewISOWeekOf(year, week int) DateRange {
+	jan4 := date.New(year, time.January, 4)
+	monday := jan4.Add(-date.PeriodOfDays((jan4.Weekday() + 6) % 7))
+	return DateRange{monday.Add(date.PeriodOfDays(7 * (week - 1))), 7}
+}
+
+// NewQuarterOf constructs the range encompassing the quarter (1 to 4) specified for a given year.
+func NewQuarterOf(year, quarter int) DateRange {
+	return newMonthsOf(year, time.Month(3*quarter-2), 1, 3)
+}
+
+// NewHalfYearOf constructs the range encompassing the half-year (1 or 2) specified for a given year.
+func NewHalfYearOf(year, half int) DateRange {
+	return newMonthsOf(year, time.Month(6*half-5), 1, 6)
+}
+
+// NewSeasonOf constructs the range of a season year that starts on the given month and day, e.g.
+// NewSeasonOf(time.October, 1, 2023) is the range from 1st October 2023 to 30th September 2024.
+func NewSeasonOf(startMonth time.Month, startDay, year int) DateRange {
+	return newMonthsOf(year, startMonth, startDay, 12)
+}
+
+// NewSeasonContaining constructs the range of the season year that starts on the given month and
+// day and contains the date.
+func NewSeasonContaining(d date.Date, startMonth time.Month, startDay int) DateRange {
+	season := NewSeasonOf(startMonth, startDay, d.Year())
+	if d.Before(season.Start()) {
+		return season.Prev()
+	}
+	return season
+}
+
+func newMonthsOf(year int, month time.Month, day, months int) DateRange {
+	start := date.New(year, month, day)
+	end := start.AddDate(0, months, 0)
+	return DateRange{start, date.PeriodOfDays(end.Sub(start))}
+}
+
 // EmptyRange constructs an empty range. This is often a useful basis for
 // further operations but note that the end date is undefined.
 func EmptyRange(day date.Date) DateRange {
@@ -195,6 +235,66 @@ func (dateRange DateRange) ExtendByPeriod(delta period.Period) DateRange {
 	return NewDateRange(dateRange.Start(), newEnd)
 }
 
+// months returns the number of months if the range spans whole months, i.e. the end is the same
+// day of a later month as the start, otherwise zero.
+func (dateRange DateRange) months() int {
+	s, e := dateRange.Start(), dateRange.End()
+	if dateRange.days == 0 || s.Day() != e.Day() {
+		return 0
+	}
+	k := (e.Year()-s.Year())*12 + int(e.Month()-s.Month())
+	if k <= 0 || s.AddDate(0, k, 0) != e {
+		return 0
+	}
+	return k
+}
+
+// Next returns the range that follows this one. Ranges spanning whole months, such as those of
+// NewMonthOf, NewQuarterOf, NewSeasonOf or NewYearOf, are followed by a range of the same number
+// of months, so the next month of February is March with 31 days. All other ranges, e.g. ISO
+// weeks, are followed by a range with the same number of days.
+func (dateRange DateRange) Next() DateRange {
+	norm := dateRange.Normalise()
+	if k := norm.months(); k > 0 {
+		return NewDateRange(norm.End(), norm.End().AddDate(0, k, 0))
+	}
+	return norm.ShiftBy(norm.days)
+}
+
+// Prev returns the range that precedes this one, see Next.
+func (dateRange DateRange) Prev() DateRange {
+	norm := dateRange.Normalise()
+	if k := norm.months(); k > 0 {
+		return NewDateRange(norm.Start().AddDate(0, -k, 0), norm.Start())
+	}
+	return norm.ShiftBy(-norm.days)
+}
+
+// EachDay calls fn for each date of the range in ascending order until fn returns false.
+func (dateRange DateRange) EachDay(fn func(d date.Date) bool) {
+	norm := dateRange.Normalise()
+	for i := date.PeriodOfDays(0); i < norm.days; i++ {
+		if !fn(norm.mark.Add(i)) {
+			return
+		}
+	}
+}
+
+// EachWeek calls fn for each ISO week (Monday to Sunday) overlapping the range in ascending order
+// until fn returns false. The first and the last week are cut to the range, so they can be shorter
+// than 7 days.
+func (dateRange DateRange) EachWeek(fn func(week DateRange) bool) {
+	norm := dateRange.Normalise()
+	start, end := norm.Start(), norm.End()
+	for start.Before(end) {
+		next := start.Add(7 - date.PeriodOfDays((start.Weekday()+6)%7))
+		if !fn(NewDateRange(start, next.Min(end))) {
+			return
+		}
+		start = next
+	}
+}
+
 // String describes the date range in human-readable form.
 func (dateRange DateRange) String() string {
 	norm := dateRange.Normalise()
@@ -154,6 +154,108 @@ func TestNewMonthOf(t *testing.T) {
 	isEq(t, 0, dr.End(), New(2015, time.March, 1))
 }
 
+func TestNewISOWeekOf(t *testing.T) {
+	cases := []struct {
+		year, week int
+		start      Date
+	}{
+		{2015, 1, New(2014, time.December, 29)},
+		{2015, 13, New(2015, time.March, 23)},
+		{2015, 53, New(2015, time.December, 28)},
+		{2021, 1, New(2021, time.January, 4)},
+		{2024, 1, New(2024, time.January, 1)},
+	}
+	for i, c := range cases {
+		dr := NewISOWeekOf(c.year, c.week)
+		isEq(t, i, dr.Start(), c.start)
+		isEq(t, i, dr.Days(), PeriodOfDays(7))
+		y, w := dr.Start().ISOWeek()
+		isEq(t, i, y, c.year)
+		isEq(t, i, w, c.week)
+		y, w = dr.Last().ISOWeek()
+		isEq(t, i, y, c.year)
+		isEq(t, i, w, c.week)
+	}
+}
+
+func TestNewQuarterAndHalfYearOf(t *testing.T) {
+	isEq(t, 0, NewQuarterOf(2024, 1), NewDateRange(New(2024, time.January, 1), New(2024, time.April, 1)))
+	isEq(t, 1, NewQuarterOf(2024, 4), NewDateRange(New(2024, time.October, 1), New(2025, time.January, 1)))
+	isEq(t, 2, NewQuarterOf(2024, 1).Days(), PeriodOfDays(91))
+	isEq(t, 3, NewHalfYearOf(2024, 1), NewDateRange(New(2024, time.January, 1), New(2024, time.July, 1)))
+	isEq(t, 4, NewHalfYearOf(2024, 2), NewDateRange(New(2024, time.July, 1), New(2025, time.January, 1)))
+}
+
+func TestNewSeasonOf(t *testing.T) {
+	dr := NewSeasonOf(time.October, 1, 2023)
+	isEq(t, 0, dr.Start(), New(2023, time.October, 1))
+	isEq(t, 0, dr.Last(), New(2024, time.September, 30))
+	isEq(t, 0, dr.Days(), PeriodOfDays(366))
+	isEq(t, 1, NewSeasonOf(time.January, 1, 2024), NewYearOf(2024))
+
+	isEq(t, 2, NewSeasonContaining(New(2024, time.September, 30), time.October, 1), dr)
+	isEq(t, 3, NewSeasonContaining(New(2023, time.October, 1), time.October, 1), dr)
+	isEq(t, 4, NewSeasonContaining(New(2024, time.October, 1), time.October, 1), NewSeasonOf(time.October, 1, 2024))
+}
+
+func TestNextPrev(t *testing.T) {
+	cases := []struct {
+		dr, next, prev DateRange
+	}{
+		{NewMonthOf(2015, time.February), NewMonthOf(2015, time.March), NewMonthOf(2015, time.January)},
+		{NewMonthOf(2015, time.January), NewMonthOf(2015, time.February), NewMonthOf(2014, time.December)},
+		{NewQuarterOf(2015, 4), NewQuarterOf(2016, 1), NewQuarterOf(2015, 3)},
+		{NewHalfYearOf(2015, 1), NewHalfYearOf(2015, 2), NewHalfYearOf(2014, 2)},
+		{NewYearOf(2015), NewYearOf(2016), NewYearOf(2014)},
+		{NewSeasonOf(time.October, 15, 2015), NewSeasonOf(time.October, 15, 2016), NewSeasonOf(time.October, 15, 2014)},
+		{NewISOWeekOf(2015, 53), NewISOWeekOf(2016, 1), NewISOWeekOf(2015, 52)},
+		{DayRange(d0327, 3), DayRange(d0330, 3), DayRange(New(2015, time.March, 24), 3)},
+		{DayRange(d0330, -3), DayRange(d0330, 3), DayRange(New(2015, time.March, 24), 3)},
+		{EmptyRange(d0327), EmptyRange(d0327), EmptyRange(d0327)},
+	}
+	for i, c := range cases {
+		isEq(t, i, c.dr.Next(), c.next)
+		isEq(t, i, c.dr.Prev(), c.prev)
+	}
+}
+
+func TestEachDay(t *testing.T) {
+	var days []string
+	DayRange(d0330, 4).EachDay(func(d Date) bool {
+		days = append(days, d.String())
+		return true
+	})
+	isEq(t, 0, strings.Join(days, " "), "2015-03-30 2015-03-31 2015-04-01 2015-04-02")
+
+	days = nil
+	DayRange(d0330, 4).EachDay(func(d Date) bool {
+		days = append(days, d.String())
+		return len(days) < 2
+	})
+	isEq(t, 1, strings.Join(days, " "), "2015-03-30 2015-03-31")
+
+	EmptyRange(d0330).EachDay(func(d Date) bool {
+		t.Errorf("unexpected %s", d)
+		return true
+	})
+}
+
+func TestEachWeek(t *testing.T) {
+	var weeks []string
+	NewMonthOf(2015, time.March).EachWeek(func(w DateRange) bool {
+		weeks = append(weeks, fmt.Sprintf("%s/%d", w.Start(), w.Days()))
+		return true
+	})
+	isEq(t, 0, strings.Join(weeks, " "), "2015-03-01/1 2015-03-02/7 2015-03-09/7 2015-03-16/7 2015-03-23/7 2015-03-30/2")
+
+	weeks = nil
+	NewISOWeekOf(2015, 10).EachWeek(func(w DateRange) bool {
+		weeks = append(weeks, fmt.Sprintf("%s/%d", w.Start(), w.Days()))
+		return false
+	})
+	isEq(t, 1, strings.Join(weeks, " "), "2015-03-02/7")
+}
+
 func TestShiftAndExtend(t *testing.T) {
 	cases := []struct {
 		dr    DateRange
@@ -0,0 +1,265 @@
+package timespan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/date/period"
+)
+
+// DateRangeSet is a set of dates described by a list of date ranges, e.g. the days on which
+// registration is open. A normalised set is sorted and contains neither empty, overlapping nor
+// adjacent ranges. All functions and methods returning a DateRangeSet return normalised sets;
+// the receivers and parameters need not be normalised.
+type DateRangeSet []DateRange
+
+// NewDateRangeSet creates a normalised set of the given date ranges.
+func NewDateRangeSet(ranges ...DateRange) DateRangeSet {
+	return DateRangeSet(ranges).Normalise()
+}
+
+// Normalise sorts the ranges, removes empty ranges and merges overlapping and adjacent ranges.
+// The receiver is not modified.
+func (set DateRangeSet) Normalise() DateRangeSet {
+	list := make(DateRangeSet, 0, len(set))
+	for _, r := range set {
+		if !r.IsEmpty() {
+			list = append(list, r.Normalise())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].mark.Before(list[j].mark)
+	})
+
+	res := list[:0]
+	for _, r := range list {
+		if n := len(res); n > 0 && !r.Start().After(res[n-1].End()) {
+			if r.End().After(res[n-1].End()) {
+				res[n-1] = NewDateRange(res[n-1].Start(), r.End())
+			}
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// IsEmpty returns true if the set does not contain any day.
+func (set DateRangeSet) IsEmpty() bool {
+	for _, r := range set {
+		if !r.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Days returns the number of days contained in the set.
+func (set DateRangeSet) Days() date.PeriodOfDays {
+	var days date.PeriodOfDays
+	for _, r := range set.Normalise() {
+		days += r.Days()
+	}
+	return days
+}
+
+// Span returns the smallest date range that contains all ranges of the set. The result is the
+// zero DateRange if the set is empty.
+func (set DateRangeSet) Span() DateRange {
+	norm := set.Normalise()
+	if len(norm) == 0 {
+		return DateRange{}
+	}
+	return NewDateRange(norm[0].Start(), norm[len(norm)-1].End())
+}
+
+// Contains tests whether one of the ranges contains the date.
+func (set DateRangeSet) Contains(d date.Date) bool {
+	for _, r := range set {
+		if r.Contains(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// Union returns the set of all days contained in either set.
+func (set DateRangeSet) Union(other DateRangeSet) DateRangeSet {
+	all := make(DateRangeSet, 0, len(set)+len(other))
+	all = append(all, set...)
+	return append(all, other...).Normalise()
+}
+
+// Intersect returns the set of all days contained in both sets.
+func (set DateRangeSet) Intersect(other DateRangeSet) DateRangeSet {
+	a, b := set.Normalise(), other.Normalise()
+	res := DateRangeSet{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		s := a[i].Start().Max(b[j].Start())
+		e := a[i].End().Min(b[j].End())
+		if s.Before(e) {
+			res = append(res, NewDateRange(s, e))
+		}
+		if a[i].End().Before(b[j].End()) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// Subtract returns the set of all days contained in this set but not in the other set.
+func (set DateRangeSet) Subtract(other DateRangeSet) DateRangeSet {
+	a, b := set.Normalise(), other.Normalise()
+	res := DateRangeSet{}
+	j := 0
+	for _, r := range a {
+		s, e := r.Start(), r.End()
+		for j < len(b) && !b[j].End().After(s) {
+			j++
+		}
+		for k := j; k < len(b) && b[k].Start().Before(e); k++ {
+			if b[k].Start().After(s) {
+				res = append(res, NewDateRange(s, b[k].Start()))
+			}
+			s = s.Max(b[k].End())
+		}
+		if s.Before(e) {
+			res = append(res, NewDateRange(s, e))
+		}
+	}
+	return res
+}
+
+// Gaps returns the days between the ranges of the set, i.e. the days within Span that are not
+// contained in the set. To find the gaps within a given period, use Subtract instead.
+func (set DateRangeSet) Gaps() DateRangeSet {
+	norm := set.Normalise()
+	res := DateRangeSet{}
+	for i := 1; i < len(norm); i++ {
+		res = append(res, NewDateRange(norm[i-1].End(), norm[i].Start()))
+	}
+	return res
+}
+
+// Overlaps tests whether the two sets have at least one day in common.
+func (set DateRangeSet) Overlaps(other DateRangeSet) bool {
+	return len(set.Intersect(other)) > 0
+}
+
+// TimeSpansIn converts the set to a TimeSpanSet of the days in a specified location.
+func (set DateRangeSet) TimeSpansIn(loc *time.Location) TimeSpanSet {
+	norm := set.Normalise()
+	res := make(TimeSpanSet, len(norm))
+	for i, r := range norm {
+		res[i] = r.TimeSpanIn(loc)
+	}
+	return res
+}
+
+// String describes the set in human-readable form.
+func (set DateRangeSet) String() string {
+	norm := set.Normalise()
+	if len(norm) == 0 {
+		return "no days"
+	}
+	parts := make([]string, len(norm))
+	for i, r := range norm {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RFC5545DateLayout is the format string used by iCalendar (RFC5545) for values of type DATE.
+const RFC5545DateLayout = "20060102"
+
+// FormatRFC5545 formats the set as a comma-separated list of ranges in RFC5545 date format,
+// each consisting of the start date and the (exclusive) end date separated by slash, e.g.
+// "20150320/20150325,20150401/20150402".
+func (set DateRangeSet) FormatRFC5545() string {
+	norm := set.Normalise()
+	parts := make([]string, len(norm))
+	for i, r := range norm {
+		parts[i] = r.Start().Format(RFC5545DateLayout) + "/" + r.End().Format(RFC5545DateLayout)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseDateRangeSetRFC5545 parses a set in the format of FormatRFC5545. The end of each range may
+// also be given as a period of days, weeks, months or years such as "P5D". A blank string results
+// in an empty set.
+func ParseDateRangeSetRFC5545(text string) (DateRangeSet, error) {
+	res := DateRangeSet{}
+	if strings.TrimSpace(text) == "" {
+		return res, nil
+	}
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		slash := strings.IndexByte(part, '/')
+		if slash < 0 {
+			return nil, fmt.Errorf("cannot parse %q because there is no separator '/'", part)
+		}
+		start, err := date.Parse(RFC5545DateLayout, part[:slash])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse start date in %q: %s", part, err.Error())
+		}
+		rest := part[slash+1:]
+		if strings.HasPrefix(rest, "P") {
+			pe, err := period.Parse(rest)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse period in %q: %s", part, err.Error())
+			}
+			res = append(res, EmptyRange(start).ExtendByPeriod(pe))
+			continue
+		}
+		end, err := date.Parse(RFC5545DateLayout, rest)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse end date in %q: %s", part, err.Error())
+		}
+		res = append(res, NewDateRange(start, end))
+	}
+	return res.Normalise(), nil
+}
+
+// MarshalJSON encodes the normalised set as an array of ISO 8601 intervals with the start date and
+// the (exclusive) end date of each range, e.g. ["2015-03-20/2015-03-25"], like the time spans of
+// TimeSpanSet.
+func (set DateRangeSet) MarshalJSON() ([]byte, error) {
+	norm := set.Normalise()
+	list := make([]string, len(norm))
+	for i, r := range norm {
+		list[i] = r.Start().String() + "/" + r.End().String()
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON decodes a set encoded by MarshalJSON. The result is normalised.
+func (set *DateRangeSet) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	res := make(DateRangeSet, len(list))
+	for i, s := range list {
+		start, end, ok := strings.Cut(s, "/")
+		if !ok {
+			return fmt.Errorf("cannot parse %q because there is no separator '/'", s)
+		}
+		sd, err := date.ParseISO(start)
+		if err != nil {
+			return fmt.Errorf("cannot parse start date in %q: %s", s, err.Error())
+		}
+		ed, err := date.ParseISO(end)
+		if err != nil {
+			return fmt.Errorf("cannot parse end date in %q: %s", s, err.Error())
+		}
+		res[i] = NewDateRange(sd, ed)
+	}
+	*set = res.Normalise()
+	return nil
+}
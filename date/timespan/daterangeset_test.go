@@ -0,0 +1,98 @@
+package timespan
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/raceresult/go-model/date"
+)
+
+func TestDateRangeSetNormalise(t *testing.T) {
+	cases := []struct {
+		in  DateRangeSet
+		exp string
+	}{
+		{nil, ""},
+		{DateRangeSet{EmptyRange(d0320)}, ""},
+		{DateRangeSet{NewDateRange(d0320, d0325)}, "20150320/20150325"},
+		{DateRangeSet{DayRange(d0325, -5)}, "20150320/20150325"},
+		{DateRangeSet{NewDateRange(d0401, d0403), NewDateRange(d0320, d0325)}, "20150320/20150325,20150401/20150403"},
+		{DateRangeSet{NewDateRange(d0320, d0325), NewDateRange(d0325, d0327)}, "20150320/20150327"},
+		{DateRangeSet{NewDateRange(d0320, d0401), NewDateRange(d0325, d0327)}, "20150320/20150401"},
+		{DateRangeSet{NewDateRange(d0320, d0326), NewDateRange(d0325, d0401), EmptyRange(d0407)}, "20150320/20150401"},
+	}
+	for i, c := range cases {
+		isEq(t, i, c.in.Normalise().FormatRFC5545(), c.exp)
+	}
+}
+
+func TestDateRangeSetOperations(t *testing.T) {
+	a := NewDateRangeSet(NewDateRange(d0320, d0326), NewDateRange(d0401, d0404))
+	b := NewDateRangeSet(NewDateRange(d0325, d0402), NewDateRange(d0403, d0407))
+	c := NewDateRangeSet(NewDateRange(d0408, d0410))
+
+	isEq(t, 0, a.Union(b).FormatRFC5545(), "20150320/20150407")
+	isEq(t, 1, a.Union(c).FormatRFC5545(), "20150320/20150326,20150401/20150404,20150408/20150410")
+	isEq(t, 2, a.Intersect(b).FormatRFC5545(), "20150325/20150326,20150401/20150402,20150403/20150404")
+	isEq(t, 3, a.Intersect(c).FormatRFC5545(), "")
+	isEq(t, 4, a.Subtract(b).FormatRFC5545(), "20150320/20150325,20150402/20150403")
+	isEq(t, 5, b.Subtract(a).FormatRFC5545(), "20150326/20150401,20150404/20150407")
+	isEq(t, 6, a.Subtract(c).FormatRFC5545(), a.FormatRFC5545())
+	isEq(t, 7, a.Gaps().FormatRFC5545(), "20150326/20150401")
+	isEq(t, 8, a.Union(c).Gaps().FormatRFC5545(), "20150326/20150401,20150404/20150408")
+	isEq(t, 9, a.Overlaps(b), true)
+	isEq(t, 10, a.Overlaps(c), false)
+	isEq(t, 11, a.Days(), PeriodOfDays(9))
+	isEq(t, 12, a.Span(), NewDateRange(d0320, d0404))
+	isEq(t, 13, a.Contains(d0325), true)
+	isEq(t, 14, a.Contains(d0326), false)
+	isEq(t, 15, a.Contains(d0403), true)
+	isEq(t, 16, DateRangeSet{}.IsEmpty(), true)
+	isEq(t, 17, a.IsEmpty(), false)
+	isEq(t, 18, DateRangeSet{}.Span().IsZero(), true)
+
+	// registration open from 20th March to 10th April, except for the closed dates in a
+	open := NewDateRangeSet(NewDateRange(d0320, d0410))
+	isEq(t, 19, open.Subtract(a).FormatRFC5545(), "20150326/20150401,20150404/20150410")
+}
+
+func TestDateRangeSetRFC5545(t *testing.T) {
+	s, err := ParseDateRangeSetRFC5545("20150401/20150403, 20150320/P5D,20150325/20150326")
+	isEq(t, 0, err, nil)
+	isEq(t, 0, s.FormatRFC5545(), "20150320/20150326,20150401/20150403")
+
+	s, err = ParseDateRangeSetRFC5545("")
+	isEq(t, 1, err, nil)
+	isEq(t, 1, len(s), 0)
+
+	for i, text := range []string{"20150320", "2015032/20150325", "20150320/x", "20150320/P5X"} {
+		_, err = ParseDateRangeSetRFC5545(text)
+		isEq(t, i, err != nil, true, text)
+	}
+}
+
+func TestDateRangeSetJSON(t *testing.T) {
+	s := NewDateRangeSet(NewDateRange(d0401, d0403), NewDateRange(d0320, d0325))
+	b, err := json.Marshal(s)
+	isEq(t, 0, err, nil)
+	isEq(t, 0, string(b), `["2015-03-20/2015-03-25","2015-04-01/2015-04-03"]`)
+
+	var u DateRangeSet
+	err = json.Unmarshal([]byte(`["2015-04-01/2015-04-03","2015-03-25/2015-03-20"]`), &u)
+	isEq(t, 1, err, nil)
+	isEq(t, 1, u.FormatRFC5545(), s.FormatRFC5545())
+
+	for i, x := range []string{`["x"]`, `["2015-04-01"]`, `["2015-04-01/x"]`, `[{"Start":"2015-04-01","End":"2015-04-03"}]`} {
+		err = json.Unmarshal([]byte(x), &u)
+		isEq(t, 3+i, err != nil, true)
+	}
+
+	b, err = json.Marshal(DateRangeSet{})
+	isEq(t, 2, err, nil)
+	isEq(t, 2, string(b), `[]`)
+}
+
+func TestDateRangeSetTimeSpansIn(t *testing.T) {
+	s := NewDateRangeSet(NewDateRange(d0328, d0330))
+	isEq(t, 0, s.TimeSpansIn(london).Duration().Hours(), 47.0)
+}
@@ -0,0 +1,483 @@
+package timespan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/date/period"
+)
+
+// Recurrence describes a recurring event by the DTSTART, DURATION, RRULE and EXDATE properties of
+// RFC5545, e.g. a parkrun every Saturday at 9:00. The location of Start determines the local time
+// of the occurrences, so an event at 9:00 stays at 9:00 when daylight saving time begins or ends.
+type Recurrence struct {
+	// Start is the first occurrence (DTSTART), it always counts as an occurrence.
+	Start time.Time
+	// Duration is the length of each occurrence.
+	Duration time.Duration
+	// Rule is the recurrence rule; if Rule.Freq is zero, Start is the only occurrence.
+	Rule RRule
+	// ExDates are excluded occurrences; they still count for Rule.Count.
+	ExDates []time.Time
+}
+
+// Occurrences expands the recurrence to the time spans of all occurrences that start within the
+// date range. The date range is taken in the location of Start.
+//
+// For frequencies below DAILY, the BY rule parts only filter the occurrences and BYSETPOS is ignored.
+func (r Recurrence) Occurrences(within DateRange) []TimeSpan {
+	loc := r.Start.Location()
+	from, to := within.StartTimeIn(loc), within.EndTimeIn(loc)
+	var res []TimeSpan
+	r.each(to, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) && !r.isExcluded(t) {
+			res = append(res, TimeSpanOf(t, r.Duration))
+		}
+		return true
+	})
+	return res
+}
+
+// Last returns the start of the last occurrence that is not excluded if the recurrence ends before
+// limit. The result is false for rules without COUNT and UNTIL, for rules with occurrences after
+// limit and if all occurrences are excluded.
+func (r Recurrence) Last(limit time.Time) (time.Time, bool) {
+	rule := r.Rule
+	endsBeforeLimit := rule.Freq == 0 || !rule.Until.IsZero() && !rule.Until.After(limit)
+	if !endsBeforeLimit && rule.Count == 0 {
+		return time.Time{}, false
+	}
+	var last time.Time
+	ok := false
+	n := 0
+	r.each(limit, func(t time.Time) bool {
+		n++
+		if !r.isExcluded(t) {
+			last, ok = t, true
+		}
+		return true
+	})
+	if !endsBeforeLimit && n < rule.Count {
+		return time.Time{}, false
+	}
+	return last, ok
+}
+
+func (r Recurrence) isExcluded(t time.Time) bool {
+	for _, ex := range r.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// each calls yield for the occurrences in chronological order, including excluded ones, until
+// yield returns false, the rule ends or the periods of the rule start after limit.
+func (r Recurrence) each(limit time.Time, yield func(time.Time) bool) {
+	rule := r.Rule
+	count := 0
+	emit := func(t time.Time) bool {
+		if !rule.Until.IsZero() && t.After(rule.Until) {
+			return false
+		}
+		count++
+		return yield(t) && (rule.Count == 0 || count < rule.Count)
+	}
+	if !emit(r.Start) || rule.Freq == 0 {
+		return
+	}
+
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	if rule.Freq < Daily {
+		step := time.Second
+		switch rule.Freq {
+		case Minutely:
+			step = time.Minute
+		case Hourly:
+			step = time.Hour
+		}
+		step *= time.Duration(interval)
+		for t := r.Start.Add(step); !t.After(limit); t = t.Add(step) {
+			y, m, d := t.Date()
+			if !rule.matchesDay(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+				// skip to the last step before the next day, so that days without match are cheap
+				if skip := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location()).Sub(t) / step; skip > 1 {
+					t = t.Add((skip - 1) * step)
+				}
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+		return
+	}
+
+	loc := r.Start.Location()
+	y, m, d := r.Start.Date()
+	hh, mm, ss := r.Start.Clock()
+	startDay := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for k := 0; ; k++ {
+		var first time.Time
+		var days []time.Time
+		switch rule.Freq {
+		case Daily:
+			first = startDay.AddDate(0, 0, k*interval)
+			if rule.matchesDay(first) {
+				days = []time.Time{first}
+			}
+		case Weekly:
+			offset := (7 + int(startDay.Weekday()) - int(rule.WeekStart)) % 7
+			first = startDay.AddDate(0, 0, 7*k*interval-offset)
+			days = rule.weekDays(first, startDay.Weekday())
+		case Monthly:
+			first = time.Date(y, m+time.Month(k*interval), 1, 0, 0, 0, 0, time.UTC)
+			days = rule.monthDays(first, d)
+		default:
+			first = time.Date(y+k*interval, time.January, 1, 0, 0, 0, 0, time.UTC)
+			days = rule.yearDays(first, m, d)
+		}
+		if time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc).After(limit) {
+			return
+		}
+
+		for _, day := range applySetPos(days, rule.BySetPos) {
+			t := time.Date(day.Year(), day.Month(), day.Day(), hh, mm, ss, r.Start.Nanosecond(), loc)
+			if t.After(r.Start) && !emit(t) {
+				return
+			}
+		}
+	}
+}
+
+func (r RRule) inMonths(m time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, x := range r.ByMonth {
+		if x == m {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDay tests the BYMONTH, BYMONTHDAY and BYDAY rule parts for a single day, ignoring
+// the numbers of BYDAY.
+func (r RRule) matchesDay(day time.Time) bool {
+	if !r.inMonths(day.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(day.Day(), daysInMonth(day)) {
+		return false
+	}
+	if len(r.ByDay) > 0 {
+		for _, w := range r.ByDay {
+			if w.Weekday == day.Weekday() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (r RRule) matchesMonthDay(day, n int) bool {
+	for _, md := range r.ByMonthDay {
+		if md == day || md < 0 && n+md+1 == day {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesByDay tests the BYDAY rule part for the day with the (1-based) index idx within a month
+// or year of n days.
+func (r RRule) matchesByDay(wd time.Weekday, idx, n int) bool {
+	for _, w := range r.ByDay {
+		if w.Weekday != wd {
+			continue
+		}
+		if w.N == 0 || w.N > 0 && (idx-1)/7+1 == w.N || w.N < 0 && (n-idx)/7+1 == -w.N {
+			return true
+		}
+	}
+	return false
+}
+
+func (r RRule) weekDays(first time.Time, defaultWeekday time.Weekday) []time.Time {
+	var days []time.Time
+	for i := 0; i < 7; i++ {
+		day := first.AddDate(0, 0, i)
+		if !r.inMonths(day.Month()) {
+			continue
+		}
+		if len(r.ByDay) == 0 && day.Weekday() == defaultWeekday || len(r.ByDay) > 0 && r.matchesByDay(day.Weekday(), 1, 7) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r RRule) monthDays(first time.Time, defaultDay int) []time.Time {
+	if !r.inMonths(first.Month()) {
+		return nil
+	}
+	n := daysInMonth(first)
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if defaultDay > n {
+			return nil
+		}
+		return []time.Time{first.AddDate(0, 0, defaultDay-1)}
+	}
+	var days []time.Time
+	for i := 1; i <= n; i++ {
+		day := first.AddDate(0, 0, i-1)
+		if (len(r.ByMonthDay) == 0 || r.matchesMonthDay(i, n)) &&
+			(len(r.ByDay) == 0 || r.matchesByDay(day.Weekday(), i, n)) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r RRule) yearDays(first time.Time, defaultMonth time.Month, defaultDay int) []time.Time {
+	if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 {
+		if len(r.ByDay) == 0 {
+			return RRule{ByMonth: []time.Month{defaultMonth}}.monthDays(first.AddDate(0, int(defaultMonth)-1, 0), defaultDay)
+		}
+		// BYDAY with numbers relative to the year
+		n := time.Date(first.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		var days []time.Time
+		for i := 1; i <= n; i++ {
+			day := first.AddDate(0, 0, i-1)
+			if r.matchesByDay(day.Weekday(), i, n) {
+				days = append(days, day)
+			}
+		}
+		return days
+	}
+	var days []time.Time
+	for m := 0; m < 12; m++ {
+		days = append(days, r.monthDays(first.AddDate(0, m, 0), defaultDay)...)
+	}
+	return days
+}
+
+func applySetPos(days []time.Time, pos []int) []time.Time {
+	if len(pos) == 0 || len(days) == 0 {
+		return days
+	}
+	var res []time.Time
+	for _, p := range pos {
+		i := p - 1
+		if p < 0 {
+			i = len(days) + p
+		}
+		if i >= 0 && i < len(days) {
+			res = append(res, days[i])
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Before(res[j]) })
+	out := res[:0]
+	for _, t := range res {
+		if len(out) == 0 || !t.Equal(out[len(out)-1]) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// FormatRFC5545 formats the recurrence as RFC5545 content lines separated by CRLF, e.g.
+//
+//	DTSTART;TZID=Europe/Berlin:20240106T090000
+//	DURATION:PT1H
+//	RRULE:FREQ=WEEKLY;BYDAY=SA
+//	EXDATE;TZID=Europe/Berlin:20241228T090000
+//
+// Start times in UTC are expressed as UTC zulu, other times with the TZID of their location.
+// Start times in time.Local or a fixed zone are expressed as UTC zulu as well, because their
+// location has no TZID. Lines are not folded.
+func (r Recurrence) FormatRFC5545() string {
+	loc := rfc5545Location(r.Start.Location())
+	lines := []string{"DTSTART" + rfc5545TimeParams(loc) + ":" + formatRFC5545TimeValue(r.Start, loc)}
+	if r.Duration != 0 {
+		p, _ := period.NewOf(r.Duration)
+		lines = append(lines, "DURATION:"+p.String())
+	}
+	if r.Rule.Freq != 0 {
+		lines = append(lines, "RRULE:"+r.Rule.String())
+	}
+	if len(r.ExDates) > 0 {
+		values := make([]string, len(r.ExDates))
+		for i, ex := range r.ExDates {
+			values[i] = formatRFC5545TimeValue(ex, loc)
+		}
+		lines = append(lines, "EXDATE"+rfc5545TimeParams(loc)+":"+strings.Join(values, ","))
+	}
+	return strings.Join(lines, "\r\n")
+}
+
+// rfc5545Location returns the location in which the times are written. Locations without a
+// time zone database name, i.e. time.Local and fixed zones, cannot be referenced by a TZID and
+// are replaced by UTC.
+func rfc5545Location(loc *time.Location) *time.Location {
+	name := loc.String()
+	if name == "" || name == "Local" {
+		return time.UTC
+	}
+	l, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return l
+}
+
+// rfc5545TimeParams returns the parameters of a date-time property in the location, e.g.
+// ";TZID=Europe/Berlin". Times in UTC are expressed as UTC zulu and have no parameters.
+func rfc5545TimeParams(loc *time.Location) string {
+	if loc == time.UTC {
+		return ""
+	}
+	return ";TZID=" + loc.String()
+}
+
+// formatRFC5545TimeValue formats the time in the location for rfc5545TimeParams.
+func formatRFC5545TimeValue(t time.Time, loc *time.Location) string {
+	if loc == time.UTC {
+		return t.UTC().Format(RFC5545DateTimeZulu)
+	}
+	return t.In(loc).Format(RFC5545DateTimeLayout)
+}
+
+// ParseRecurrenceInLocation parses the RFC5545 content lines DTSTART, DTEND, DURATION, RRULE and
+// EXDATE, e.g. as written by FormatRFC5545. Other lines are ignored. Times with a TZID parameter
+// are read in that location, times ending in "Z" in UTC, and other times in the specified location.
+func ParseRecurrenceInLocation(text string, loc *time.Location) (Recurrence, error) {
+	var r Recurrence
+	var end time.Time
+	var rule string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, params, value, err := splitContentLine(line)
+		if err != nil {
+			return Recurrence{}, err
+		}
+		tloc := loc
+		if tzid, ok := params["TZID"]; ok {
+			if tloc, err = time.LoadLocation(tzid); err != nil {
+				return Recurrence{}, fmt.Errorf("cannot parse %q: %s", line, err.Error())
+			}
+		}
+		switch name {
+		case "DTSTART":
+			r.Start, err = parseRFC5545TimeOrDate(value, tloc)
+		case "DTEND":
+			end, err = parseRFC5545TimeOrDate(value, tloc)
+		case "DURATION":
+			var p period.Period
+			if p, err = period.Parse(value); err == nil {
+				r.Duration = p.DurationApprox()
+			}
+		case "RRULE":
+			rule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var ex time.Time
+				if ex, err = parseRFC5545TimeOrDate(v, tloc); err != nil {
+					break
+				}
+				r.ExDates = append(r.ExDates, ex)
+			}
+		}
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("cannot parse %q: %s", line, err.Error())
+		}
+	}
+	if r.Start.IsZero() {
+		return Recurrence{}, fmt.Errorf("cannot parse recurrence because DTSTART is missing")
+	}
+	if !end.IsZero() {
+		r.Duration = end.Sub(r.Start)
+	}
+	if rule != "" {
+		var err error
+		if r.Rule, err = ParseRRuleInLocation(rule, r.Start.Location()); err != nil {
+			return Recurrence{}, err
+		}
+	}
+	return r, nil
+}
+
+func parseRFC5545TimeOrDate(value string, loc *time.Location) (time.Time, error) {
+	if len(value) == len(RFC5545DateLayout) {
+		return time.ParseInLocation(RFC5545DateLayout, value, loc)
+	}
+	return parseTimeInLocation(value, loc)
+}
+
+// splitContentLine splits an unfolded RFC5545 content line into the upper case name, the
+// parameters and the value. Quoted parameter values may contain colons and semicolons.
+func splitContentLine(line string) (name string, params map[string]string, value string, err error) {
+	params = map[string]string{}
+	inQuotes := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case ':':
+			if !inQuotes {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", fmt.Errorf("cannot parse %q because there is no ':'", line)
+	}
+	value = line[colon+1:]
+	parts := splitOutsideQuotes(line[:colon], ';')
+	name = strings.ToUpper(parts[0])
+	for _, p := range parts[1:] {
+		eq := strings.IndexByte(p, '=')
+		if eq < 0 {
+			return "", nil, "", fmt.Errorf("cannot parse parameter %q in %q", p, line)
+		}
+		params[strings.ToUpper(p[:eq])] = strings.Trim(p[eq+1:], `"`)
+	}
+	return name, params, value, nil
+}
+
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	inQuotes := false
+	last := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case sep:
+			if !inQuotes {
+				parts = append(parts, s[last:i])
+				last = i + 1
+			}
+		}
+	}
+	return append(parts, s[last:])
+}
@@ -0,0 +1,190 @@
+package timespan
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+)
+
+func formatOccurrences(spans []TimeSpan) string {
+	s := make([]string, len(spans))
+	for i, ts := range spans {
+		s[i] = ts.Start().Format("2006-01-02 Mon 15:04 -07")
+	}
+	return strings.Join(s, ", ")
+}
+
+func mustParseRRule(text string) RRule {
+	r, err := ParseRRule(text)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	sat := time.Date(2024, 3, 16, 9, 0, 0, 0, berlin)
+	march := NewMonthOf(2024, time.March)
+	spring := NewDateRange(date.New(2024, 3, 1), date.New(2024, 6, 1))
+
+	cases := []struct {
+		start  time.Time
+		rule   string
+		within DateRange
+		exp    string
+	}{
+		// weekly parkrun, stays at 9:00 when the clocks change on 31st March
+		{sat, "FREQ=WEEKLY;BYDAY=SA", NewDateRange(date.New(2024, 3, 20), date.New(2024, 4, 10)),
+			"2024-03-23 Sat 09:00 +01, 2024-03-30 Sat 09:00 +01, 2024-04-06 Sat 09:00 +02"},
+		{sat, "FREQ=WEEKLY;INTERVAL=2;COUNT=3", spring,
+			"2024-03-16 Sat 09:00 +01, 2024-03-30 Sat 09:00 +01, 2024-04-13 Sat 09:00 +02"},
+		{sat, "FREQ=WEEKLY;BYDAY=SA,SU;UNTIL=20240324T075959Z", spring,
+			"2024-03-16 Sat 09:00 +01, 2024-03-17 Sun 09:00 +01, 2024-03-23 Sat 09:00 +01"},
+		{sat, "FREQ=DAILY;COUNT=3", march,
+			"2024-03-16 Sat 09:00 +01, 2024-03-17 Sun 09:00 +01, 2024-03-18 Mon 09:00 +01"},
+		{sat, "FREQ=DAILY;BYDAY=MO;UNTIL=20240401", spring,
+			"2024-03-16 Sat 09:00 +01, 2024-03-18 Mon 09:00 +01, 2024-03-25 Mon 09:00 +01, 2024-04-01 Mon 09:00 +02"},
+		// monthly cup rounds on the last Friday
+		{time.Date(2024, 3, 29, 18, 0, 0, 0, berlin), "FREQ=MONTHLY;BYDAY=-1FR;COUNT=4", NewYearOf(2024),
+			"2024-03-29 Fri 18:00 +01, 2024-04-26 Fri 18:00 +02, 2024-05-31 Fri 18:00 +02, 2024-06-28 Fri 18:00 +02"},
+		{time.Date(2024, 1, 6, 10, 0, 0, 0, berlin), "FREQ=MONTHLY;BYDAY=1SA,3SA;COUNT=4", NewYearOf(2024),
+			"2024-01-06 Sat 10:00 +01, 2024-01-20 Sat 10:00 +01, 2024-02-03 Sat 10:00 +01, 2024-02-17 Sat 10:00 +01"},
+		// last working day of the month
+		{time.Date(2024, 1, 31, 12, 0, 0, 0, berlin), "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=4", NewYearOf(2024),
+			"2024-01-31 Wed 12:00 +01, 2024-02-29 Thu 12:00 +01, 2024-03-29 Fri 12:00 +01, 2024-04-30 Tue 12:00 +02"},
+		{time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), "FREQ=MONTHLY;COUNT=3", NewYearOf(2024),
+			"2024-01-31 Wed 12:00 +00, 2024-03-31 Sun 12:00 +00, 2024-05-31 Fri 12:00 +00"},
+		{time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=3", NewYearOf(2024),
+			"2024-01-15 Mon 12:00 +00, 2024-01-31 Wed 12:00 +00, 2024-02-01 Thu 12:00 +00"},
+		{time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), "FREQ=YEARLY;COUNT=2", NewDateRange(date.New(2024, 1, 1), date.New(2030, 1, 1)),
+			"2024-02-29 Thu 12:00 +00, 2028-02-29 Tue 12:00 +00"},
+		{time.Date(2024, 3, 31, 3, 0, 0, 0, berlin), "FREQ=YEARLY;BYMONTH=3,10;BYDAY=-1SU;COUNT=4", NewDateRange(date.New(2024, 1, 1), date.New(2030, 1, 1)),
+			"2024-03-31 Sun 03:00 +02, 2024-10-27 Sun 03:00 +01, 2025-03-30 Sun 03:00 +02, 2025-10-26 Sun 03:00 +01"},
+		{time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), "FREQ=YEARLY;BYDAY=20MO;COUNT=2", NewDateRange(date.New(2024, 1, 1), date.New(2030, 1, 1)),
+			"2024-01-01 Mon 12:00 +00, 2024-05-13 Mon 12:00 +00"},
+		{time.Date(2024, 3, 30, 23, 0, 0, 0, berlin), "FREQ=HOURLY;INTERVAL=2;COUNT=3", march,
+			"2024-03-30 Sat 23:00 +01, 2024-03-31 Sun 01:00 +01, 2024-03-31 Sun 04:00 +02"},
+		{time.Date(2024, 3, 31, 23, 30, 0, 0, time.UTC), "FREQ=MINUTELY;INTERVAL=15", march,
+			"2024-03-31 Sun 23:30 +00, 2024-03-31 Sun 23:45 +00"},
+		// no rule
+		{sat, "", march, "2024-03-16 Sat 09:00 +01"},
+		{sat, "", NewMonthOf(2024, time.April), ""},
+	}
+	for i, c := range cases {
+		r := Recurrence{Start: c.start, Duration: time.Hour}
+		if c.rule != "" {
+			r.Rule = mustParseRRule(c.rule)
+		}
+		occ := r.Occurrences(c.within)
+		isEq(t, i, formatOccurrences(occ), c.exp, c.rule)
+		for _, ts := range occ {
+			isEq(t, i, ts.Duration(), time.Hour)
+		}
+	}
+}
+
+func TestRecurrenceExDates(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	r := Recurrence{
+		Start:   time.Date(2024, 12, 14, 9, 0, 0, 0, berlin),
+		Rule:    mustParseRRule("FREQ=WEEKLY;COUNT=4"),
+		ExDates: []time.Time{time.Date(2024, 12, 28, 8, 0, 0, 0, time.UTC)},
+	}
+	isEq(t, 0, formatOccurrences(r.Occurrences(NewDateRange(date.New(2024, 12, 1), date.New(2025, 2, 1)))),
+		"2024-12-14 Sat 09:00 +01, 2024-12-21 Sat 09:00 +01, 2025-01-04 Sat 09:00 +01")
+}
+
+func TestRecurrenceLast(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	start := time.Date(2024, 1, 6, 9, 0, 0, 0, berlin)
+	cases := []struct {
+		rule string
+		exp  string
+	}{
+		{"FREQ=WEEKLY;COUNT=200", "2027-10-30 Sat 09:00 +02"},
+		{"FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20240601T000000Z", "2024-05-31 Fri 09:00 +02"},
+		{"FREQ=DAILY;COUNT=3", "2024-01-07 Sun 09:00 +01"},
+		{"", "2024-01-06 Sat 09:00 +01"},
+		{"FREQ=WEEKLY;COUNT=600", ""},
+		{"FREQ=WEEKLY;UNTIL=20400101T000000Z", ""},
+		{"FREQ=WEEKLY", ""},
+		// rules without matches end at the limit
+		{"FREQ=MINUTELY;COUNT=2;BYMONTH=2;BYMONTHDAY=30", ""},
+		{"FREQ=SECONDLY;COUNT=2;BYMONTH=2;BYMONTHDAY=30", ""},
+	}
+	limit := start.AddDate(10, 0, 0)
+	for i, c := range cases {
+		r := Recurrence{Start: start, ExDates: []time.Time{start.AddDate(0, 0, 2)}}
+		if c.rule != "" {
+			r.Rule = mustParseRRule(c.rule)
+		}
+		last, ok := r.Last(limit)
+		isEq(t, i, ok, c.exp != "", c.rule)
+		if ok {
+			isEq(t, i, last.Format("2006-01-02 Mon 15:04 -07"), c.exp, c.rule)
+		}
+	}
+}
+
+func TestRecurrenceSubDailySkipsDays(t *testing.T) {
+	r := Recurrence{
+		Start: time.Date(2024, 3, 15, 23, 58, 0, 0, time.UTC),
+		Rule:  mustParseRRule("FREQ=MINUTELY;INTERVAL=7;BYDAY=SU;COUNT=3"),
+	}
+	isEq(t, 0, formatOccurrences(r.Occurrences(NewDateRange(date.New(2024, 3, 15), date.New(2024, 3, 18)))),
+		"2024-03-15 Fri 23:58 +00, 2024-03-17 Sun 00:00 +00, 2024-03-17 Sun 00:07 +00")
+}
+
+func TestRecurrenceRFC5545(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	r := Recurrence{
+		Start:    time.Date(2024, 12, 14, 9, 0, 0, 0, berlin),
+		Duration: 90 * time.Minute,
+		Rule:     mustParseRRule("FREQ=WEEKLY;BYDAY=SA"),
+		ExDates:  []time.Time{time.Date(2024, 12, 28, 9, 0, 0, 0, berlin), time.Date(2025, 1, 4, 8, 0, 0, 0, time.UTC)},
+	}
+	text := r.FormatRFC5545()
+	isEq(t, 0, text, "DTSTART;TZID=Europe/Berlin:20241214T090000\r\nDURATION:PT1H30M\r\nRRULE:FREQ=WEEKLY;BYDAY=SA\r\n"+
+		"EXDATE;TZID=Europe/Berlin:20241228T090000,20250104T090000")
+
+	u, err := ParseRecurrenceInLocation(text, time.UTC)
+	isEq(t, 1, err, nil)
+	isEq(t, 1, u.FormatRFC5545(), text)
+	isEq(t, 1, u.Start.Location().String(), "Europe/Berlin")
+
+	u, err = ParseRecurrenceInLocation("SUMMARY:parkrun\nDTSTART:20241214T080000Z\nDTEND:20241214T090000Z\nRRULE:FREQ=DAILY;COUNT=2\nEXDATE:20241215T080000Z\n", berlin)
+	isEq(t, 2, err, nil)
+	isEq(t, 2, u.FormatRFC5545(), "DTSTART:20241214T080000Z\r\nDURATION:PT1H\r\nRRULE:FREQ=DAILY;COUNT=2\r\nEXDATE:20241215T080000Z")
+
+	u, err = ParseRecurrenceInLocation(`DTSTART;TZID="Europe/Berlin":20241214T090000`, time.UTC)
+	isEq(t, 3, err, nil)
+	isEq(t, 3, u.Start.Equal(r.Start), true)
+
+	// locations without TZID are written in UTC
+	for i, loc := range []*time.Location{time.Local, time.FixedZone("", 3600), time.FixedZone("UTC+1", 3600)} {
+		r := Recurrence{Start: time.Date(2024, 12, 14, 9, 0, 0, 0, loc), Rule: mustParseRRule("FREQ=DAILY;COUNT=2"), ExDates: []time.Time{time.Date(2024, 12, 15, 9, 0, 0, 0, loc)}}
+		start := r.Start.UTC().Format(RFC5545DateTimeZulu)
+		ex := r.ExDates[0].UTC().Format(RFC5545DateTimeZulu)
+		isEq(t, 4+i, r.FormatRFC5545(), "DTSTART:"+start+"\r\nRRULE:FREQ=DAILY;COUNT=2\r\nEXDATE:"+ex)
+		u, err = ParseRecurrenceInLocation(r.FormatRFC5545(), berlin)
+		isEq(t, 4+i, err, nil)
+		isEq(t, 4+i, u.Start.Equal(r.Start), true)
+	}
+
+	for i, text := range []string{
+		"RRULE:FREQ=DAILY",
+		"DTSTART:x",
+		"DTSTART;TZID=Nowhere/City:20241214T090000",
+		"DTSTART:20241214T080000Z\nRRULE:FREQ=NEVER",
+		"DTSTART:20241214T080000Z\nDURATION:1H",
+		"DTSTART:20241214T080000Z\nEXDATE:2024",
+		"DTSTART",
+		"DTSTART;TZID:20241214T080000Z",
+	} {
+		_, err = ParseRecurrenceInLocation(text, time.UTC)
+		isEq(t, i, err != nil, true, text)
+	}
+}
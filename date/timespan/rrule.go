@@ -0,0 +1,240 @@
+package timespan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the FREQ rule part of a recurrence rule.
+type Frequency int
+
+const (
+	Secondly Frequency = iota + 1
+	Minutely
+	Hourly
+	Daily
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = []string{"", "SECONDLY", "MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+// String returns the RFC5545 name of the frequency, e.g. "WEEKLY".
+func (f Frequency) String() string {
+	if f < Secondly || f > Yearly {
+		return ""
+	}
+	return frequencyNames[f]
+}
+
+var weekdayNames = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// WeekdayNum is an element of the BYDAY rule part: a weekday, optionally with the number of its
+// occurrence within the month or year, e.g. 1MO for the first Monday or -1FR for the last Friday.
+// N is zero for every occurrence of the weekday.
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// String returns the RFC5545 form, e.g. "-1FR".
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayNames[w.Weekday]
+	}
+	return strconv.Itoa(w.N) + weekdayNames[w.Weekday]
+}
+
+// RRule is a recurrence rule as defined by RFC5545 section 3.3.10. The supported rule parts are
+// FREQ, INTERVAL, COUNT, UNTIL, BYMONTH, BYMONTHDAY, BYDAY, BYSETPOS and WKST. The occurrences
+// are calculated by Recurrence.
+type RRule struct {
+	Freq Frequency
+	// Interval is the number of periods between two sets of occurrences; 0 is the same as 1.
+	Interval int
+	// Count limits the number of occurrences; 0 means no limit.
+	Count int
+	// Until is the last possible occurrence (inclusive); the zero time means no limit.
+	Until      time.Time
+	ByMonth    []time.Month
+	ByMonthDay []int
+	ByDay      []WeekdayNum
+	BySetPos   []int
+	// WeekStart is the first day of the week, which matters for WEEKLY rules with an interval.
+	// ParseRRule sets Monday, the default of RFC5545; note that the zero value is Sunday.
+	WeekStart time.Weekday
+}
+
+// ParseRRule parses a recurrence rule such as "FREQ=WEEKLY;BYDAY=SA;COUNT=10". An optional
+// "RRULE:" prefix is ignored. Values of UNTIL without "Z" are read in UTC; see
+// ParseRRuleInLocation.
+func ParseRRule(text string) (RRule, error) {
+	return ParseRRuleInLocation(text, time.UTC)
+}
+
+// ParseRRuleInLocation parses a recurrence rule like ParseRRule. Values of UNTIL without "Z" are
+// read in the specified location, dates without time mean the end of that day.
+func ParseRRuleInLocation(text string, loc *time.Location) (RRule, error) {
+	r := RRule{WeekStart: time.Monday}
+	text = strings.TrimPrefix(strings.TrimSpace(text), "RRULE:")
+	if text == "" {
+		return RRule{}, fmt.Errorf("cannot parse empty recurrence rule")
+	}
+	for _, part := range strings.Split(text, ";") {
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			return RRule{}, fmt.Errorf("cannot parse %q in recurrence rule because there is no '='", part)
+		}
+		name, value := strings.ToUpper(part[:eq]), part[eq+1:]
+		var err error
+		switch name {
+		case "FREQ":
+			r.Freq = 0
+			for f := Secondly; f <= Yearly; f++ {
+				if strings.EqualFold(value, f.String()) {
+					r.Freq = f
+				}
+			}
+			if r.Freq == 0 {
+				err = fmt.Errorf("unknown frequency")
+			}
+		case "INTERVAL":
+			r.Interval, err = parseIntInRange(value, 1, 1<<20)
+		case "COUNT":
+			r.Count, err = parseIntInRange(value, 1, 1<<30)
+		case "UNTIL":
+			r.Until, err = parseUntil(value, loc)
+		case "BYMONTH":
+			err = parseIntList(value, 1, 12, func(x int) { r.ByMonth = append(r.ByMonth, time.Month(x)) })
+		case "BYMONTHDAY":
+			err = parseIntList(value, -31, 31, func(x int) { r.ByMonthDay = append(r.ByMonthDay, x) })
+		case "BYSETPOS":
+			err = parseIntList(value, -366, 366, func(x int) { r.BySetPos = append(r.BySetPos, x) })
+		case "BYDAY":
+			for _, s := range strings.Split(value, ",") {
+				var w WeekdayNum
+				if w, err = parseWeekdayNum(s); err != nil {
+					break
+				}
+				r.ByDay = append(r.ByDay, w)
+			}
+		case "WKST":
+			var w WeekdayNum
+			if w, err = parseWeekdayNum(value); err == nil && w.N != 0 {
+				err = fmt.Errorf("number not allowed")
+			}
+			r.WeekStart = w.Weekday
+		default:
+			err = fmt.Errorf("rule part not supported")
+		}
+		if err != nil {
+			return RRule{}, fmt.Errorf("cannot parse %q in recurrence rule: %s", part, err.Error())
+		}
+	}
+	if r.Freq == 0 {
+		return RRule{}, fmt.Errorf("cannot parse %q because FREQ is missing", text)
+	}
+	if r.Count != 0 && !r.Until.IsZero() {
+		return RRule{}, fmt.Errorf("cannot parse %q because COUNT and UNTIL must not both be given", text)
+	}
+	return r, nil
+}
+
+func parseIntInRange(s string, min, max int) (int, error) {
+	x, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if x < min || x > max || x == 0 {
+		return 0, fmt.Errorf("%d out of range", x)
+	}
+	return x, nil
+}
+
+func parseIntList(s string, min, max int, add func(int)) error {
+	for _, p := range strings.Split(s, ",") {
+		x, err := parseIntInRange(p, min, max)
+		if err != nil {
+			return err
+		}
+		add(x)
+	}
+	return nil
+}
+
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+	}
+	wd := strings.ToUpper(s[len(s)-2:])
+	for i, name := range weekdayNames {
+		if name == wd {
+			w := WeekdayNum{Weekday: time.Weekday(i)}
+			if n := s[:len(s)-2]; n != "" {
+				var err error
+				if w.N, err = parseIntInRange(strings.TrimPrefix(n, "+"), -53, 53); err != nil {
+					return WeekdayNum{}, err
+				}
+			}
+			return w, nil
+		}
+	}
+	return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+}
+
+func parseUntil(s string, loc *time.Location) (time.Time, error) {
+	if len(s) == len(RFC5545DateLayout) {
+		t, err := time.ParseInLocation(RFC5545DateLayout, s, loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.AddDate(0, 0, 1).Add(minusOneNano), nil
+	}
+	return parseTimeInLocation(s, loc)
+}
+
+// String returns the rule in RFC5545 format, e.g. "FREQ=MONTHLY;BYDAY=-1FR". UNTIL is
+// expressed as UTC zulu.
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(RFC5545DateTimeZulu))
+	}
+	if len(r.ByMonth) > 0 {
+		parts = append(parts, "BYMONTH="+joinInts(len(r.ByMonth), func(i int) int { return int(r.ByMonth[i]) }))
+	}
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(len(r.ByMonthDay), func(i int) int { return r.ByMonthDay[i] }))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, w := range r.ByDay {
+			days[i] = w.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.BySetPos) > 0 {
+		parts = append(parts, "BYSETPOS="+joinInts(len(r.BySetPos), func(i int) int { return r.BySetPos[i] }))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+func joinInts(n int, get func(int) int) string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = strconv.Itoa(get(i))
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,60 @@
+package timespan
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	cases := []struct {
+		text string
+		exp  RRule
+		str  string
+	}{
+		{"FREQ=WEEKLY;BYDAY=SA", RRule{Freq: Weekly, ByDay: []WeekdayNum{{0, time.Saturday}}, WeekStart: time.Monday}, ""},
+		{"RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,+2MO", RRule{Freq: Monthly, Interval: 2, ByDay: []WeekdayNum{{-1, time.Friday}, {2, time.Monday}}, WeekStart: time.Monday},
+			"FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,2MO"},
+		{"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=3", RRule{Freq: Monthly, Count: 3, BySetPos: []int{-1}, WeekStart: time.Monday,
+			ByDay: []WeekdayNum{{0, time.Monday}, {0, time.Tuesday}, {0, time.Wednesday}, {0, time.Thursday}, {0, time.Friday}}},
+			"FREQ=MONTHLY;COUNT=3;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1"},
+		{"freq=yearly;bymonth=3,10;bymonthday=-1;until=20301231T235959Z;wkst=SU", RRule{Freq: Yearly, ByMonth: []time.Month{3, 10}, ByMonthDay: []int{-1},
+			Until: time.Date(2030, 12, 31, 23, 59, 59, 0, time.UTC), WeekStart: time.Sunday},
+			"FREQ=YEARLY;UNTIL=20301231T235959Z;BYMONTH=3,10;BYMONTHDAY=-1;WKST=SU"},
+		{"FREQ=DAILY;UNTIL=20240131", RRule{Freq: Daily, Until: time.Date(2024, 1, 31, 23, 59, 59, 999999999, time.UTC), WeekStart: time.Monday},
+			"FREQ=DAILY;UNTIL=20240131T235959Z"},
+	}
+	for i, c := range cases {
+		r, err := ParseRRule(c.text)
+		isEq(t, i, err, nil)
+		isEq(t, i, reflect.DeepEqual(r, c.exp), true, r, c.exp)
+		str := c.str
+		if str == "" {
+			str = c.text
+		}
+		isEq(t, i, r.String(), str)
+	}
+}
+
+func TestParseRRuleErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=FORTNIGHTLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=x",
+		"FREQ=DAILY;BYMONTH=13",
+		"FREQ=DAILY;BYMONTHDAY=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=DAILY;BYDAY=1",
+		"FREQ=DAILY;WKST=1MO",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COUNT",
+		"FREQ=DAILY;UNTIL=2024",
+		"FREQ=DAILY;COUNT=3;UNTIL=20240101",
+	}
+	for i, c := range cases {
+		_, err := ParseRRule(c)
+		isEq(t, i, err != nil, true, c)
+	}
+}
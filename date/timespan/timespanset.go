@@ -0,0 +1,251 @@
+package timespan
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeSpanSet is a set of instants described by a list of time spans, e.g. the times at which a
+// website tab is active. A normalised set is sorted and contains neither empty, overlapping nor
+// adjacent spans. All functions and methods returning a TimeSpanSet return normalised sets;
+// the receivers and parameters need not be normalised.
+type TimeSpanSet []TimeSpan
+
+// NewTimeSpanSet creates a normalised set of the given time spans.
+func NewTimeSpanSet(spans ...TimeSpan) TimeSpanSet {
+	return TimeSpanSet(spans).Normalise()
+}
+
+// Normalise sorts the spans, removes empty spans and merges overlapping and adjacent spans.
+// The receiver is not modified.
+func (set TimeSpanSet) Normalise() TimeSpanSet {
+	list := make(TimeSpanSet, 0, len(set))
+	for _, ts := range set {
+		if !ts.IsEmpty() {
+			list = append(list, ts.Normalise())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].mark.Before(list[j].mark)
+	})
+
+	res := list[:0]
+	for _, ts := range list {
+		if n := len(res); n > 0 && !ts.Start().After(res[n-1].End()) {
+			if ts.End().After(res[n-1].End()) {
+				res[n-1] = NewTimeSpan(res[n-1].Start(), ts.End())
+			}
+			continue
+		}
+		res = append(res, ts)
+	}
+	return res
+}
+
+// IsEmpty returns true if the set does not contain any instant.
+func (set TimeSpanSet) IsEmpty() bool {
+	for _, ts := range set {
+		if !ts.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Duration returns the total duration of the time spans in the set.
+func (set TimeSpanSet) Duration() time.Duration {
+	var d time.Duration
+	for _, ts := range set.Normalise() {
+		d += ts.Duration()
+	}
+	return d
+}
+
+// Span returns the smallest time span that contains all spans of the set. The result is the
+// zero TimeSpan if the set is empty.
+func (set TimeSpanSet) Span() TimeSpan {
+	norm := set.Normalise()
+	if len(norm) == 0 {
+		return TimeSpan{}
+	}
+	return NewTimeSpan(norm[0].Start(), norm[len(norm)-1].End())
+}
+
+// Contains tests whether one of the spans contains the time.
+func (set TimeSpanSet) Contains(t time.Time) bool {
+	for _, ts := range set {
+		if ts.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Union returns the set of all instants contained in either set.
+func (set TimeSpanSet) Union(other TimeSpanSet) TimeSpanSet {
+	all := make(TimeSpanSet, 0, len(set)+len(other))
+	all = append(all, set...)
+	return append(all, other...).Normalise()
+}
+
+// Intersect returns the set of all instants contained in both sets.
+func (set TimeSpanSet) Intersect(other TimeSpanSet) TimeSpanSet {
+	a, b := set.Normalise(), other.Normalise()
+	res := TimeSpanSet{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		s := maxTime(a[i].Start(), b[j].Start())
+		e := minTime(a[i].End(), b[j].End())
+		if s.Before(e) {
+			res = append(res, NewTimeSpan(s, e))
+		}
+		if a[i].End().Before(b[j].End()) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+// Subtract returns the set of all instants contained in this set but not in the other set.
+func (set TimeSpanSet) Subtract(other TimeSpanSet) TimeSpanSet {
+	a, b := set.Normalise(), other.Normalise()
+	res := TimeSpanSet{}
+	j := 0
+	for _, ts := range a {
+		s, e := ts.Start(), ts.End()
+		for j < len(b) && !b[j].End().After(s) {
+			j++
+		}
+		for k := j; k < len(b) && b[k].Start().Before(e); k++ {
+			if b[k].Start().After(s) {
+				res = append(res, NewTimeSpan(s, b[k].Start()))
+			}
+			s = maxTime(s, b[k].End())
+		}
+		if s.Before(e) {
+			res = append(res, NewTimeSpan(s, e))
+		}
+	}
+	return res
+}
+
+// Gaps returns the time between the spans of the set, i.e. the instants within Span that are not
+// contained in the set. To find the gaps within a given time span, use Subtract instead.
+func (set TimeSpanSet) Gaps() TimeSpanSet {
+	norm := set.Normalise()
+	res := TimeSpanSet{}
+	for i := 1; i < len(norm); i++ {
+		res = append(res, NewTimeSpan(norm[i-1].End(), norm[i].Start()))
+	}
+	return res
+}
+
+// Overlaps tests whether the two sets have at least one instant in common.
+func (set TimeSpanSet) Overlaps(other TimeSpanSet) bool {
+	return len(set.Intersect(other)) > 0
+}
+
+// In returns the set with all times adjusted to a new location, see TimeSpan.In.
+func (set TimeSpanSet) In(loc *time.Location) TimeSpanSet {
+	res := make(TimeSpanSet, len(set))
+	for i, ts := range set {
+		res[i] = ts.In(loc)
+	}
+	return res
+}
+
+// String describes the set in human-readable form.
+func (set TimeSpanSet) String() string {
+	norm := set.Normalise()
+	parts := make([]string, len(norm))
+	for i, ts := range norm {
+		parts[i] = ts.String()
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// FormatRFC5545 formats the set as a comma-separated list of periods as used by iCalendar
+// (RFC5545) for FREEBUSY and RDATE values, e.g. "20150214T101314Z/20150214T111314Z". The
+// times are expressed as UTC zulu. If useDuration is true, each period consists of the start
+// time and the duration instead of the end time.
+func (set TimeSpanSet) FormatRFC5545(useDuration bool) string {
+	norm := set.Normalise()
+	parts := make([]string, len(norm))
+	for i, ts := range norm {
+		parts[i] = ts.In(time.UTC).FormatRFC5545(useDuration)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseTimeSpanSetRFC5545InLocation parses a comma-separated list of time spans, each in one of
+// the formats accepted by ParseRFC5545InLocation. A blank string results in an empty set.
+func ParseTimeSpanSetRFC5545InLocation(text string, loc *time.Location) (TimeSpanSet, error) {
+	res := TimeSpanSet{}
+	if strings.TrimSpace(text) == "" {
+		return res, nil
+	}
+	for _, part := range strings.Split(text, ",") {
+		ts, err := ParseRFC5545InLocation(strings.TrimSpace(part), loc)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ts)
+	}
+	return res.Normalise(), nil
+}
+
+// MarshalJSON encodes the normalised set as an array of ISO 8601 intervals with start and end
+// time in RFC 3339 format in UTC including fractional seconds, e.g.
+// ["2015-02-14T10:13:14.5Z/2015-02-14T11:13:14Z"], like the date ranges of DateRangeSet.
+func (set TimeSpanSet) MarshalJSON() ([]byte, error) {
+	norm := set.Normalise()
+	list := make([]string, len(norm))
+	for i, ts := range norm {
+		list[i] = ts.In(time.UTC).Format(time.RFC3339Nano, "/", false)
+	}
+	return json.Marshal(list)
+}
+
+// UnmarshalJSON decodes a set encoded by MarshalJSON. The result is normalised.
+func (set *TimeSpanSet) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	res := make(TimeSpanSet, len(list))
+	for i, s := range list {
+		start, end, ok := strings.Cut(s, "/")
+		if !ok {
+			return fmt.Errorf("cannot parse %q because there is no separator '/'", s)
+		}
+		st, err := time.Parse(time.RFC3339Nano, start)
+		if err != nil {
+			return fmt.Errorf("cannot parse start time in %q: %s", s, err.Error())
+		}
+		et, err := time.Parse(time.RFC3339Nano, end)
+		if err != nil {
+			return fmt.Errorf("cannot parse end time in %q: %s", s, err.Error())
+		}
+		res[i] = NewTimeSpan(st, et)
+	}
+	*set = res.Normalise()
+	return nil
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
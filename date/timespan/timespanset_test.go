@@ -0,0 +1,75 @@
+package timespan
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeSpanSetOperations(t *testing.T) {
+	t8 := time.Date(2015, 3, 27, 8, 0, 0, 0, time.UTC)
+	h := time.Hour
+
+	a := NewTimeSpanSet(TimeSpanOf(t8.Add(4*h), 2*h), TimeSpanOf(t8, 2*h), TimeSpanOf(t8.Add(2*h), -h))
+	isEq(t, 0, a.FormatRFC5545(false), "20150327T080000Z/20150327T100000Z,20150327T120000Z/20150327T140000Z")
+
+	b := NewTimeSpanSet(TimeSpanOf(t8.Add(h), 4*h))
+	isEq(t, 1, a.Union(b).FormatRFC5545(true), "20150327T080000Z/PT6H")
+	isEq(t, 2, a.Intersect(b).FormatRFC5545(false), "20150327T090000Z/20150327T100000Z,20150327T120000Z/20150327T130000Z")
+	isEq(t, 3, a.Subtract(b).FormatRFC5545(false), "20150327T080000Z/20150327T090000Z,20150327T130000Z/20150327T140000Z")
+	isEq(t, 4, b.Subtract(a).FormatRFC5545(false), "20150327T100000Z/20150327T120000Z")
+	isEq(t, 5, a.Gaps().FormatRFC5545(false), "20150327T100000Z/20150327T120000Z")
+	isEq(t, 6, a.Overlaps(b), true)
+	isEq(t, 7, a.Overlaps(a.Gaps()), false)
+	isEq(t, 8, a.Duration(), 4*h)
+	isEq(t, 9, a.Span().Equal(TimeSpanOf(t8, 6*h)), true)
+	isEq(t, 10, a.Contains(t8.Add(h)), true)
+	isEq(t, 11, a.Contains(t8.Add(2*h)), false)
+	isEq(t, 12, TimeSpanSet{ZeroTimeSpan(t8)}.IsEmpty(), true)
+	isEq(t, 13, len(TimeSpanSet{ZeroTimeSpan(t8)}.Normalise()), 0)
+}
+
+func TestTimeSpanSetRFC5545(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	s, err := ParseTimeSpanSetRFC5545InLocation("20150327T100000/PT1H, 20150327T080000Z/20150327T083000Z", berlin)
+	isEq(t, 0, err, nil)
+	isEq(t, 0, s.FormatRFC5545(false), "20150327T080000Z/20150327T083000Z,20150327T090000Z/20150327T100000Z")
+
+	s, err = ParseTimeSpanSetRFC5545InLocation(" ", berlin)
+	isEq(t, 1, err, nil)
+	isEq(t, 1, len(s), 0)
+
+	_, err = ParseTimeSpanSetRFC5545InLocation("20150327T100000/PT1H,x", berlin)
+	isEq(t, 2, err != nil, true)
+}
+
+func TestTimeSpanSetJSON(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	t8 := time.Date(2015, 3, 27, 8, 0, 0, 0, berlin)
+
+	s := NewTimeSpanSet(TimeSpanOf(t8, time.Hour))
+	b, err := json.Marshal(s)
+	isEq(t, 0, err, nil)
+	isEq(t, 0, string(b), `["2015-03-27T07:00:00Z/2015-03-27T08:00:00Z"]`)
+
+	var u TimeSpanSet
+	err = json.Unmarshal(b, &u)
+	isEq(t, 1, err, nil)
+	isEq(t, 1, len(u), 1)
+	isEq(t, 1, u[0].Equal(s[0]), true)
+
+	// fractional seconds are kept
+	s = NewTimeSpanSet(TimeSpanOf(t8.Add(1500*time.Microsecond), 250*time.Millisecond))
+	b, err = json.Marshal(s)
+	isEq(t, 2, err, nil)
+	isEq(t, 2, string(b), `["2015-03-27T07:00:00.0015Z/2015-03-27T07:00:00.2515Z"]`)
+	err = json.Unmarshal(b, &u)
+	isEq(t, 2, err, nil)
+	isEq(t, 2, u[0].Equal(s[0]), true)
+
+	for i, x := range []string{`["x"]`, `["2015-03-27T07:00:00Z"]`, `["2015-03-27T07:00:00Z/x"]`, `["20150327T070000Z/20150327T080000Z"]`} {
+		err = json.Unmarshal([]byte(x), &u)
+		isEq(t, 3+i, err != nil, true)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"strings"
 	"time"
+
+	"github.com/raceresult/go-model/date"
 )
 
 const dateFormat = "2006-01-02"
@@ -141,6 +143,9 @@ func (s *DateTime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	}
 	if d, ok := Parse(str); ok {
 		*s = d
+	} else if str != "" {
+		// fractional seconds as written by MarshalXML
+		_ = s.scanString(str)
 	}
 	return nil
 }
@@ -168,7 +173,8 @@ func (s DateTime) ToString() string {
 	return s.Time.Format(dateTimeFormat)
 }
 
-// ToStringWithDateFormat converts the date to string with the given date format
+// ToStringWithDateFormat converts the date to string with the given date format. The format can
+// also be a localised format such as "@de:long", see date.ParseLocaleFormat.
 func (s DateTime) ToStringWithDateFormat(df string) string {
 	if s.IsZero() {
 		return ""
@@ -176,6 +182,12 @@ func (s DateTime) ToStringWithDateFormat(df string) string {
 	if df == "" {
 		return s.ToString()
 	}
+	if tag, pattern, ok := date.ParseLocaleFormat(df); ok {
+		if s.Time.Unix()%86400 != 0 {
+			pattern = date.JoinDateTime(tag, pattern, date.TimePattern(tag, date.FormatMedium))
+		}
+		return date.FormatPattern(s.Time, pattern, tag)
+	}
 	if s.Time.Unix()%86400 == 0 {
 		return s.Time.Format(df)
 	}
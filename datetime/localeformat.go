@@ -0,0 +1,22 @@
+package datetime
+
+import (
+	"github.com/raceresult/go-model/date"
+	"golang.org/x/text/language"
+)
+
+// FormatLocale formats the DateTime with the default patterns of the locale, e.g.
+// "1. Mai 2024, 08:30" for German, date.FormatLong and date.FormatShort. date.FormatNone omits the
+// date or the time. The zero date results in an empty string.
+func (s DateTime) FormatLocale(tag language.Tag, dateStyle, timeStyle date.FormatStyle) string {
+	return s.FormatPattern(date.DateTimePattern(tag, dateStyle, timeStyle), tag)
+}
+
+// FormatPattern formats the DateTime with a CLDR pattern such as "EEEE, d. MMMM y HH:mm", see
+// date.FormatPattern. The zero date results in an empty string.
+func (s DateTime) FormatPattern(pattern string, tag language.Tag) string {
+	if s.IsZero() {
+		return ""
+	}
+	return date.FormatPattern(s.Time, pattern, tag)
+}
@@ -0,0 +1,25 @@
+package datetime
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestDateTime_FormatLocale(t *testing.T) {
+	x := New(2024, 5, 1, 20, 30, 0)
+	assert.Equal(t, "1. Mai 2024 um 20:30", x.FormatLocale(language.German, date.FormatLong, date.FormatShort))
+	assert.Equal(t, "01.05.2024, 20:30", x.FormatLocale(language.German, date.FormatMedium, date.FormatShort))
+	assert.Equal(t, "May 1, 2024, 8:30:00 PM", x.FormatLocale(language.English, date.FormatMedium, date.FormatMedium))
+	assert.Equal(t, "20:30", x.FormatLocale(language.French, date.FormatNone, date.FormatShort))
+	assert.Equal(t, "mercredi 1 mai", x.FormatPattern("EEEE d MMMM", language.French))
+	assert.Equal(t, "", ZeroDate().FormatLocale(language.German, date.FormatLong, date.FormatShort))
+
+	assert.Equal(t, "1. Mai 2024 um 20:30:00", x.ToStringWithDateFormat("@de:long"))
+	assert.Equal(t, "May 1, 2024, 8:30:00 PM", x.ToStringWithDateFormat("@en:medium"))
+	assert.Equal(t, "1 mei 2024 om 20:30:00", x.ToStringWithDateFormat("@nl:long"))
+	assert.Equal(t, "1 mei 2024", New(2024, 5, 1, 0, 0, 0).ToStringWithDateFormat("@nl:long"))
+	assert.Equal(t, "01.05.2024", New(2024, 5, 1, 0, 0, 0).ToStringWithDateFormat("02.01.2006"))
+}
@@ -0,0 +1,86 @@
+package datetime
+
+import (
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// MarshalText converts the DateTime to text in the same format as MarshalJSON, zone-less values
+// without offset. Unlike MarshalJSON, fractional seconds are kept, e.g. "2024-05-01 10:00:00.5".
+// It replaces the method of time.Time which would add an offset to every value.
+func (s DateTime) MarshalText() ([]byte, error) {
+	return []byte(s.textString()), nil
+}
+
+// textString returns ToString, or the full timestamp including fractional seconds if there are any.
+func (s DateTime) textString() string {
+	if s.Time.Nanosecond() == 0 || s.IsZero() {
+		return s.ToString()
+	}
+	if s.hasZone {
+		return s.Time.Format(time.RFC3339Nano)
+	}
+	return s.Time.Format("2006-01-02 15:04:05.999999999")
+}
+
+// UnmarshalText parses a DateTime in one of the formats of Parse or with fractional seconds as
+// written by MarshalText. Empty text results in the VB zero date.
+func (s *DateTime) UnmarshalText(data []byte) error {
+	if err := s.scanString(string(data)); err != nil {
+		return errors.New("date time format not supported")
+	}
+	return nil
+}
+
+// MarshalXML creates an XML element with the DateTime in the same format as MarshalText.
+func (s DateTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(s.textString(), start)
+}
+
+// MarshalXMLAttr creates an XML attribute with the DateTime in the same format as MarshalText.
+func (s DateTime) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: s.textString()}, nil
+}
+
+// UnmarshalXMLAttr parses an XML attribute like UnmarshalText.
+func (s *DateTime) UnmarshalXMLAttr(attr xml.Attr) error {
+	return s.UnmarshalText([]byte(attr.Value))
+}
+
+// MarshalBinary encodes the DateTime as a flag byte for the time zone followed by the binary
+// encoding of time.Time.
+func (s DateTime) MarshalBinary() ([]byte, error) {
+	b, err := s.Time.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var flag byte
+	if s.hasZone {
+		flag = 1
+	}
+	return append([]byte{flag}, b...), nil
+}
+
+// UnmarshalBinary decodes a DateTime encoded by MarshalBinary.
+func (s *DateTime) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 || data[0] > 1 {
+		return errors.New("invalid binary date time")
+	}
+	var t time.Time
+	if err := t.UnmarshalBinary(data[1:]); err != nil {
+		return err
+	}
+	*s = DateTime{Time: t, hasZone: data[0] == 1}
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder like MarshalBinary.
+func (s DateTime) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder like UnmarshalBinary.
+func (s *DateTime) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
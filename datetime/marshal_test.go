@@ -0,0 +1,136 @@
+package datetime
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var berlin = time.FixedZone("CEST", 2*3600)
+
+func TestDateTime_Text(t *testing.T) {
+	tests := []struct {
+		value DateTime
+		text  string
+	}{
+		{New(2024, 5, 1, 10, 30, 0), "2024-05-01 10:30:00"},
+		{New(2024, 5, 1, 0, 0, 0), "2024-05-01"},
+		{FromTime(time.Date(2024, 5, 1, 10, 30, 0, 0, berlin), true), "2024-05-01T10:30:00+02:00"},
+		{New(2024, 5, 1, 10, 30, 0).Add(250 * time.Millisecond), "2024-05-01 10:30:00.25"},
+		{FromTime(time.Date(2024, 5, 1, 10, 30, 0, 5e8, berlin), true), "2024-05-01T10:30:00.5+02:00"},
+		{ZeroDate(), ""},
+	}
+	for _, tt := range tests {
+		b, err := tt.value.MarshalText()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.text, string(b))
+
+		var d DateTime
+		assert.NoError(t, d.UnmarshalText(b))
+		assert.True(t, tt.value.Time.Equal(d.Time), tt.text)
+		assert.Equal(t, tt.value.HasZone(), d.HasZone(), tt.text)
+	}
+
+	var d DateTime
+	assert.Error(t, d.UnmarshalText([]byte("yesterday")))
+}
+
+func TestDateTime_XML(t *testing.T) {
+	type item struct {
+		XMLName xml.Name `xml:"Item"`
+		At      DateTime `xml:"at,attr"`
+		Created DateTime
+		Deleted DateTime
+	}
+	x := item{
+		At:      FromTime(time.Date(2024, 5, 1, 10, 30, 0, 0, berlin), true),
+		Created: New(2024, 5, 1, 10, 30, 0).Add(500 * time.Millisecond),
+		Deleted: ZeroDate(),
+	}
+	b, err := xml.Marshal(x)
+	assert.NoError(t, err)
+	assert.Equal(t, `<Item at="2024-05-01T10:30:00+02:00"><Created>2024-05-01 10:30:00.5</Created><Deleted></Deleted></Item>`, string(b))
+
+	var y item
+	assert.NoError(t, xml.Unmarshal(b, &y))
+	assert.True(t, y.At.HasZone())
+	assert.True(t, x.At.Time.Equal(y.At.Time))
+	assert.False(t, y.Created.HasZone())
+	assert.Equal(t, x.Created, y.Created)
+	assert.True(t, y.Deleted.IsZero())
+}
+
+func TestDateTime_SQL(t *testing.T) {
+	v, err := New(2024, 5, 1, 10, 30, 0).Add(500 * time.Millisecond).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-05-01 10:30:00.5", v)
+
+	zoned := FromTime(time.Date(2024, 5, 1, 10, 30, 0, 0, berlin), true)
+	v, err = zoned.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, zoned.Time, v)
+
+	// values with zone keep the zone flag, except in UTC
+	utc := FromTime(time.Date(2024, 5, 1, 10, 30, 0, 5e8, time.UTC), true)
+	v, err = utc.Value()
+	assert.NoError(t, err)
+	var d DateTime
+	assert.NoError(t, d.Scan(v))
+	assert.Equal(t, FromTime(utc.Time, false), d)
+	for _, x := range []DateTime{zoned, FromTime(time.Date(2024, 5, 1, 10, 30, 0, 5e8, time.FixedZone("", -3600)), true), New(2024, 5, 1, 10, 30, 0)} {
+		v, err = x.Value()
+		assert.NoError(t, err)
+		var d DateTime
+		assert.NoError(t, d.Scan(v), "%v", v)
+		assert.True(t, x.Time.Equal(d.Time), "%v: %v", v, d)
+		assert.Equal(t, x.HasZone(), d.HasZone(), "%v", v)
+	}
+
+	v, err = ZeroDate().Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	v, err = DateTime{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	tests := []struct {
+		value    interface{}
+		expected DateTime
+	}{
+		{"2024-05-01 10:30:00.5", New(2024, 5, 1, 10, 30, 0).Add(500 * time.Millisecond)},
+		{[]byte("2024-05-01 10:30:00"), New(2024, 5, 1, 10, 30, 0)},
+		{"2024-05-01 10:30:00+02", zoned},
+		{"2024-05-01T10:30:00+02:00", zoned},
+		{time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC), New(2024, 5, 1, 10, 30, 0)},
+		{time.Date(2024, 5, 1, 10, 30, 0, 0, berlin), zoned},
+		{time.Date(2024, 5, 1, 10, 30, 0, 0, time.FixedZone("", 7200)), zoned},
+		{nil, ZeroDate()},
+		{"", ZeroDate()},
+	}
+	for _, tt := range tests {
+		var d DateTime
+		assert.NoError(t, d.Scan(tt.value), "%v", tt.value)
+		assert.True(t, tt.expected.Time.Equal(d.Time), "%v: %v", tt.value, d)
+		assert.Equal(t, tt.expected.HasZone(), d.HasZone(), "%v", tt.value)
+	}
+
+	assert.Error(t, d.Scan("tomorrow"))
+	assert.Error(t, d.Scan(42))
+}
+
+func TestDateTime_Gob(t *testing.T) {
+	for _, x := range []DateTime{New(2024, 5, 1, 10, 30, 0), FromTime(time.Date(2024, 5, 1, 10, 30, 0, 0, berlin), true)} {
+		var buf bytes.Buffer
+		assert.NoError(t, gob.NewEncoder(&buf).Encode(x))
+		var y DateTime
+		assert.NoError(t, gob.NewDecoder(&buf).Decode(&y))
+		assert.True(t, x.Time.Equal(y.Time))
+		assert.Equal(t, x.HasZone(), y.HasZone())
+	}
+	var d DateTime
+	assert.Error(t, d.UnmarshalBinary([]byte{2, 1}))
+}
@@ -0,0 +1,543 @@
+package datetime
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/raceresult/go-model/date"
+	"golang.org/x/text/language"
+)
+
+// DateOrder defines the order of day, month and year in numeric dates.
+type DateOrder int
+
+const (
+	// DayFirst reads 01/05/2024 as 1 May 2024.
+	DayFirst DateOrder = iota
+	// MonthFirst reads 01/05/2024 as 5 January 2024 (US format).
+	MonthFirst
+	// YearFirst reads 24/05/01 as 1 May 2024. Dates starting with a 4-digit year are always read year first.
+	YearFirst
+)
+
+// Parser parses dates and times in the many formats found in imported files, e.g. "1.5.2024",
+// "05/01/2024 8:30 PM", "2024-05-01T08:30", "1. Mai 2024" or "Wed, 1 May 2024 08:30:00.25".
+//
+// Numeric dates are read in the configured order, dates which are invalid in this order result in an
+// error unless SwapDayMonth is set. Two-digit years are mapped to the range from 89 years ago to 10
+// years ahead.
+//
+// Month and weekday names are looked up in the languages of the parser, wide and abbreviated names
+// as well as prefixes of at least 3 letters of the wide names are accepted. Weekday names are ignored.
+//
+// Times may have seconds, fractional seconds (separated by . or ,), AM/PM and a time zone (Z or an
+// offset such as +02:00). Values with time zone have HasZone set.
+type Parser struct {
+	Order DateOrder
+	// SwapDayMonth reads numeric dates with day and month swapped if they are invalid in Order but
+	// valid when swapped, e.g. 05/13/2024 with DayFirst. The returned layout shows the order used, e.g.
+	// "01/02/2006", so callers can detect that values of one file were read in different orders.
+	SwapDayMonth bool
+	// Languages for month and weekday names, all supported languages if empty (see date.LocaleNames).
+	Languages []language.Tag
+	// Now is used to map two-digit years, the current time if zero.
+	Now time.Time
+}
+
+// NewParser creates a Parser with the given order and the names of the given languages.
+func NewParser(order DateOrder, languages ...language.Tag) *Parser {
+	return &Parser{Order: order, Languages: languages}
+}
+
+// ParseFlexible parses the string with a day-first Parser for all supported languages which swaps
+// day and month if needed.
+func ParseFlexible(str string) (DateTime, string, error) {
+	return (&Parser{SwapDayMonth: true}).Parse(str)
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenWord
+	tokenOther
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits the string into numbers, words and single other characters. Spaces are
+// collapsed to a single space token.
+func tokenize(str string) []token {
+	var tokens []token
+	rs := []rune(str)
+	for i := 0; i < len(rs); {
+		j := i + 1
+		switch {
+		case rs[i] >= '0' && rs[i] <= '9':
+			for j < len(rs) && rs[j] >= '0' && rs[j] <= '9' {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(rs[i:j])})
+		case unicode.IsLetter(rs[i]):
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || rs[j] == '-' && j+1 < len(rs) && unicode.IsLetter(rs[j+1])) {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, string(rs[i:j])})
+		case unicode.IsSpace(rs[i]):
+			for j < len(rs) && unicode.IsSpace(rs[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokenOther, " "})
+		default:
+			tokens = append(tokens, token{tokenOther, string(rs[i])})
+		}
+		i = j
+	}
+	return tokens
+}
+
+// noiseWords are ignored in dates with month names, e.g. "1st of May" or "1 de mayo de 2024".
+var noiseWords = map[string]bool{"st": true, "nd": true, "rd": true, "th": true, "of": true, "de": true, "del": true}
+
+// errUnknownFormat is returned if the string does not match any supported format.
+var errUnknownFormat = errors.New("date time format not supported")
+
+// Parse parses the string and returns the DateTime and a Go layout describing the matched format,
+// e.g. "2.1.2006 15:04" or "2 January 2006". Localised month and weekday names are reported as
+// the English names of the layout.
+func (s *Parser) Parse(str string) (DateTime, string, error) {
+	tokens := tokenize(strings.TrimSpace(str))
+	if len(tokens) == 0 {
+		return ZeroDate(), "", errors.New("cannot parse a blank string")
+	}
+
+	// split date and time at the first number followed by a colon
+	dateTokens := tokens
+	var timeTokens []token
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].kind == tokenNumber && tokens[i+1].text == ":" && tokens[i+2].kind == tokenNumber {
+			dateTokens, timeTokens = tokens[:i], tokens[i:]
+			break
+		}
+	}
+
+	// separator between date and time
+	var sep string
+	for len(dateTokens) > 0 {
+		t := dateTokens[len(dateTokens)-1]
+		if t.text != " " && t.text != "," && t.text != "T" && !(t.kind == tokenWord && strings.EqualFold(t.text, "um")) {
+			break
+		}
+		sep = t.text + sep
+		dateTokens = dateTokens[:len(dateTokens)-1]
+	}
+	if timeTokens != nil && len(dateTokens) > 0 && sep == "" {
+		return ZeroDate(), "", errUnknownFormat
+	}
+
+	year, month, day, dateLayout, err := s.parseDate(dateTokens)
+	if err != nil {
+		return ZeroDate(), "", err
+	}
+
+	var hour, minute, second, nsec int
+	var loc *time.Location
+	var timeLayout string
+	if timeTokens != nil {
+		hour, minute, second, nsec, loc, timeLayout, err = parseTime(timeTokens)
+		if err != nil {
+			return ZeroDate(), "", err
+		}
+	}
+
+	zone := loc
+	if zone == nil {
+		zone = time.UTC
+	}
+	t := time.Date(year, month, day, hour, minute, second, nsec, zone)
+	if t.Day() != day || t.Month() != month {
+		return ZeroDate(), "", errors.New("invalid date " + str)
+	}
+	layout := dateLayout
+	if timeTokens != nil {
+		layout += sep + timeLayout
+	}
+	return DateTime{Time: t, hasZone: loc != nil}, layout, nil
+}
+
+// names returns the month and weekday names of the languages of the parser.
+func (s *Parser) names() []date.Names {
+	if len(s.Languages) == 0 {
+		return date.AllLocaleNames()
+	}
+	var r []date.Names
+	for _, tag := range s.Languages {
+		if n, ok := date.LocaleNames(tag); ok {
+			r = append(r, n)
+		}
+	}
+	return r
+}
+
+// lookupMonth finds a month by name, returns the month and if the name was abbreviated.
+func lookupMonth(names []date.Names, word string) (time.Month, bool, bool) {
+	w := strings.ToLower(word)
+	for _, n := range names {
+		if m, ok := n.LookupMonth(w); ok {
+			return m, !strings.EqualFold(n.Month(m), w) && !strings.EqualFold(n.MonthStandalone(m), w), true
+		}
+	}
+	if len([]rune(w)) >= 3 {
+		for _, n := range names {
+			for m := time.January; m <= time.December; m++ {
+				if strings.HasPrefix(strings.ToLower(n.Month(m)), w) {
+					return m, true, true
+				}
+			}
+		}
+	}
+	return 0, false, false
+}
+
+// lookupWeekday checks if the word is a weekday, returns if the name was abbreviated.
+func lookupWeekday(names []date.Names, word string) (bool, bool) {
+	for _, n := range names {
+		if d, ok := n.LookupWeekday(word); ok {
+			return !strings.EqualFold(n.Weekday(d), word), true
+		}
+	}
+	return false, false
+}
+
+// parseDate parses the date part and returns the date and its layout.
+func (s *Parser) parseDate(tokens []token) (int, time.Month, int, string, error) {
+	var words, numbers []int
+	for i, t := range tokens {
+		switch t.kind {
+		case tokenWord:
+			words = append(words, i)
+		case tokenNumber:
+			numbers = append(numbers, i)
+		}
+	}
+	if len(words) > 0 {
+		return s.parseNamedDate(tokens, words, numbers)
+	}
+
+	// compact ISO date, e.g. 20240501
+	if len(numbers) == 1 && len(tokens) == 1 && len(tokens[0].text) == 8 {
+		y, _ := strconv.Atoi(tokens[0].text[:4])
+		m, _ := strconv.Atoi(tokens[0].text[4:6])
+		d, _ := strconv.Atoi(tokens[0].text[6:])
+		return y, time.Month(m), d, "20060102", nil
+	}
+
+	// numeric date with the same separator, e.g. 1.5.2024 or 2024-05-01, optionally with trailing dot
+	if len(tokens) == 6 && tokens[5].text == "." {
+		tokens = tokens[:5]
+	}
+	if len(tokens) != 5 || len(numbers) != 3 || tokens[1].kind != tokenOther || tokens[1].text != tokens[3].text ||
+		!strings.Contains("./- ", tokens[1].text) {
+		return 0, 0, 0, "", errUnknownFormat
+	}
+	a, b, c := tokens[0].text, tokens[2].text, tokens[4].text
+	sep := tokens[1].text
+
+	order := s.Order
+	switch {
+	case len(a) == 4:
+		order = YearFirst
+	case order == YearFirst && len(c) == 4:
+		order = DayFirst
+	}
+
+	y, m, d, layout, ok := s.numericDate(order, a, b, c, sep)
+	if !ok && order != YearFirst && s.SwapDayMonth {
+		// day and month swapped, e.g. 05/13/2024 in a day-first parser
+		swapped := MonthFirst
+		if order == MonthFirst {
+			swapped = DayFirst
+		}
+		y, m, d, layout, ok = s.numericDate(swapped, a, b, c, sep)
+	}
+	if !ok {
+		return 0, 0, 0, "", errUnknownFormat
+	}
+	return y, m, d, layout, nil
+}
+
+// numericDate reads the three numbers in the given order and checks the ranges.
+func (s *Parser) numericDate(order DateOrder, a, b, c, sep string) (int, time.Month, int, string, bool) {
+	ys, ms, ds := c, b, a
+	switch order {
+	case MonthFirst:
+		ms, ds = a, b
+	case YearFirst:
+		ys, ds = a, c
+	}
+	if len(ys) != 2 && len(ys) != 4 || len(ms) > 2 || len(ds) > 2 {
+		return 0, 0, 0, "", false
+	}
+	y := s.year(ys)
+	m, _ := strconv.Atoi(ms)
+	d, _ := strconv.Atoi(ds)
+	if m < 1 || m > 12 || d < 1 || d > 31 {
+		return 0, 0, 0, "", false
+	}
+
+	yl, ml, dl := "2006", "1", "2"
+	if len(ys) == 2 {
+		yl = "06"
+	}
+	if len(ms) == 2 {
+		ml = "01"
+	}
+	if len(ds) == 2 {
+		dl = "02"
+	}
+	var layout string
+	switch order {
+	case DayFirst:
+		layout = dl + sep + ml + sep + yl
+	case MonthFirst:
+		layout = ml + sep + dl + sep + yl
+	default:
+		layout = yl + sep + ml + sep + dl
+	}
+	return y, time.Month(m), d, layout, true
+}
+
+// year converts a 2- or 4-digit year. Two-digit years are mapped to the range from 89 years ago
+// to 10 years ahead.
+func (s *Parser) year(str string) int {
+	y, _ := strconv.Atoi(str)
+	if len(str) != 2 {
+		return y
+	}
+	now := s.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	century := now.Year() / 100 * 100
+	y += century
+	if y > now.Year()+10 {
+		y -= 100
+	}
+	return y
+}
+
+// parseNamedDate parses a date with month name, e.g. "1. Mai 2024", "May 1st, 2024" or "Wed 1 May 2024".
+func (s *Parser) parseNamedDate(tokens []token, words, numbers []int) (int, time.Month, int, string, error) {
+	names := s.names()
+
+	// find the month, ignore weekdays and noise words
+	layouts := make([]string, len(tokens))
+	for i := range tokens {
+		layouts[i] = tokens[i].text
+	}
+	var candidates []int
+	months := map[int]time.Month{}
+	for _, i := range words {
+		w := tokens[i].text
+		if noiseWords[strings.ToLower(w)] {
+			continue
+		}
+		m, abbr, isMonth := lookupMonth(names, w)
+		weekdayAbbr, isWeekday := lookupWeekday(names, w)
+		if !isMonth && !isWeekday {
+			return 0, 0, 0, "", errUnknownFormat
+		}
+		if isWeekday {
+			layouts[i] = "Monday"
+			if weekdayAbbr {
+				layouts[i] = "Mon"
+			}
+		}
+		if isMonth {
+			candidates = append(candidates, i)
+			months[i] = m
+			if !isWeekday {
+				layouts[i] = "January"
+				if abbr {
+					layouts[i] = "Jan"
+				}
+			}
+		}
+	}
+	if len(candidates) > 1 {
+		// names like "mar" can be a weekday or a month, the other month name is used then
+		var filtered []int
+		for _, i := range candidates {
+			if layouts[i] != "Monday" && layouts[i] != "Mon" {
+				filtered = append(filtered, i)
+			}
+		}
+		candidates = filtered
+	}
+	if len(candidates) != 1 || len(numbers) != 2 {
+		return 0, 0, 0, "", errUnknownFormat
+	}
+	month := months[candidates[0]]
+	if _, abbr, _ := lookupMonth(names, tokens[candidates[0]].text); abbr {
+		layouts[candidates[0]] = "Jan"
+	} else {
+		layouts[candidates[0]] = "January"
+	}
+
+	// day and year, the year has 4 digits or comes last
+	di, yi := numbers[0], numbers[1]
+	if len(tokens[di].text) == 4 || len(tokens[yi].text) != 4 && len(tokens[di].text) > 2 {
+		di, yi = yi, di
+	}
+	if len(tokens[di].text) > 2 || len(tokens[yi].text) != 4 && len(tokens[yi].text) != 2 {
+		return 0, 0, 0, "", errUnknownFormat
+	}
+	d, _ := strconv.Atoi(tokens[di].text)
+	y := s.year(tokens[yi].text)
+	layouts[di] = "2"
+	if len(tokens[di].text) == 2 {
+		layouts[di] = "02"
+	}
+	layouts[yi] = "2006"
+	if len(tokens[yi].text) == 2 {
+		layouts[yi] = "06"
+	}
+
+	// only spaces, dots, commas and dashes between the parts
+	for _, t := range tokens {
+		if t.kind == tokenOther && !strings.Contains(" .,-/", t.text) {
+			return 0, 0, 0, "", errUnknownFormat
+		}
+	}
+	if d < 1 || d > 31 {
+		return 0, 0, 0, "", errUnknownFormat
+	}
+	return y, month, d, strings.Join(layouts, ""), nil
+}
+
+// parseTime parses the time part, e.g. "8:30", "20:30:15.25", "8:30 PM" or "08:30:00+02:00".
+func parseTime(tokens []token) (int, int, int, int, *time.Location, string, error) {
+	var hour, minute, second, nsec int
+	var loc *time.Location
+	var layout strings.Builder
+
+	i := 0
+	next := func(kind tokenKind, text string) (string, bool) {
+		if i < len(tokens) && tokens[i].kind == kind && (text == "" || tokens[i].text == text) {
+			i++
+			return tokens[i-1].text, true
+		}
+		return "", false
+	}
+
+	h, _ := next(tokenNumber, "")
+	next(tokenOther, ":")
+	m, _ := next(tokenNumber, "")
+	if len(h) > 2 || len(m) != 2 {
+		return 0, 0, 0, 0, nil, "", errUnknownFormat
+	}
+	hour, _ = strconv.Atoi(h)
+	minute, _ = strconv.Atoi(m)
+	hourLayout := "15"
+	if len(h) == 1 {
+		hourLayout = "3" // replaced below for 24h clock
+	}
+	layout.WriteString(":04")
+
+	if _, ok := next(tokenOther, ":"); ok {
+		sec, ok := next(tokenNumber, "")
+		if !ok || len(sec) != 2 {
+			return 0, 0, 0, 0, nil, "", errUnknownFormat
+		}
+		second, _ = strconv.Atoi(sec)
+		layout.WriteString(":05")
+
+		if i+1 < len(tokens) && (tokens[i].text == "." || tokens[i].text == ",") && tokens[i+1].kind == tokenNumber {
+			frac := tokens[i+1].text
+			if len(frac) > 9 {
+				return 0, 0, 0, 0, nil, "", errUnknownFormat
+			}
+			layout.WriteString(tokens[i].text + strings.Repeat("0", len(frac)))
+			nsec, _ = strconv.Atoi(frac + strings.Repeat("0", 9-len(frac)))
+			i += 2
+		}
+	}
+
+	// AM/PM
+	j := i
+	space, _ := next(tokenOther, " ")
+	pm := false
+	if w, ok := next(tokenWord, ""); ok && (strings.EqualFold(w, "am") || strings.EqualFold(w, "pm")) {
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, 0, nil, "", errUnknownFormat
+		}
+		pm = strings.EqualFold(w, "pm")
+		if pm && hour < 12 {
+			hour += 12
+		} else if !pm && hour == 12 {
+			hour = 0
+		}
+		if len(h) == 2 {
+			hourLayout = "03"
+		} else {
+			hourLayout = "3"
+		}
+		layout.WriteString(space + "PM")
+	} else {
+		i = j
+		if hourLayout == "3" {
+			hourLayout = "15"
+		}
+	}
+
+	// time zone
+	j = i
+	space, _ = next(tokenOther, " ")
+	switch {
+	case i < len(tokens) && tokens[i].text == "Z":
+		i++
+		loc = time.UTC
+		layout.WriteString(space + "Z07:00")
+	case i+1 < len(tokens) && (tokens[i].text == "+" || tokens[i].text == "-") && tokens[i+1].kind == tokenNumber:
+		sign := tokens[i].text
+		i++
+		oh, _ := next(tokenNumber, "")
+		om := "00"
+		zoneLayout := "-07"
+		switch {
+		case len(oh) == 4:
+			oh, om = oh[:2], oh[2:]
+			zoneLayout = "-0700"
+		case len(oh) != 2:
+			return 0, 0, 0, 0, nil, "", errUnknownFormat
+		default:
+			if _, ok := next(tokenOther, ":"); ok {
+				if om, ok = next(tokenNumber, ""); !ok || len(om) != 2 {
+					return 0, 0, 0, 0, nil, "", errUnknownFormat
+				}
+				zoneLayout = "-07:00"
+			}
+		}
+		x, _ := strconv.Atoi(oh)
+		y, _ := strconv.Atoi(om)
+		offset := x*3600 + y*60
+		if sign == "-" {
+			offset = -offset
+		}
+		loc = time.FixedZone("", offset)
+		layout.WriteString(space + zoneLayout)
+	default:
+		i = j
+	}
+
+	if i != len(tokens) || hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, 0, nil, "", errUnknownFormat
+	}
+	return hour, minute, second, nsec, loc, hourLayout + layout.String(), nil
+}
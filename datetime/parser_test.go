@@ -0,0 +1,86 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestParser_Parse(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	dayFirst := &Parser{Now: now}
+	monthFirst := &Parser{Order: MonthFirst, Now: now}
+	swapDayFirst := &Parser{SwapDayMonth: true, Now: now}
+	swapMonthFirst := &Parser{Order: MonthFirst, SwapDayMonth: true, Now: now}
+	plus2 := time.FixedZone("", 2*3600)
+
+	tests := []struct {
+		parser   *Parser
+		str      string
+		expected time.Time
+		layout   string
+		hasZone  bool
+	}{
+		{dayFirst, "1.5.2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2.1.2006", false},
+		{dayFirst, "01.05.2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "02.01.2006", false},
+		{dayFirst, "01/05/2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "02/01/2006", false},
+		{monthFirst, "01/05/2024", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), "01/02/2006", false},
+		{swapDayFirst, "05/13/2024", time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), "01/02/2006", false},
+		{swapMonthFirst, "13/05/2024", time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), "02/01/2006", false},
+		{dayFirst, "1.5.85", time.Date(1985, 5, 1, 0, 0, 0, 0, time.UTC), "2.1.06", false},
+		{dayFirst, "1.5.05", time.Date(2005, 5, 1, 0, 0, 0, 0, time.UTC), "2.1.06", false},
+		{&Parser{Order: YearFirst, Now: now}, "24/05/01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "06/01/02", false},
+		{dayFirst, "2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2006-01-02", false},
+		{dayFirst, "20240501", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "20060102", false},
+		{dayFirst, "2024-05-01T08:30", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC), "2006-01-02T15:04", false},
+		{dayFirst, "2024-05-01 08:30:15,25", time.Date(2024, 5, 1, 8, 30, 15, 250000000, time.UTC), "2006-01-02 15:04:05,00", false},
+		{dayFirst, "1.5.2024 8:30:15.123", time.Date(2024, 5, 1, 8, 30, 15, 123000000, time.UTC), "2.1.2006 15:04:05.000", false},
+		{dayFirst, "2024-05-01T08:30:00Z", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC), "2006-01-02T15:04:05Z07:00", true},
+		{dayFirst, "2024-05-01T08:30:00+02:00", time.Date(2024, 5, 1, 8, 30, 0, 0, plus2), "2006-01-02T15:04:05-07:00", true},
+		{dayFirst, "2024-05-01 08:30 +0200", time.Date(2024, 5, 1, 8, 30, 0, 0, plus2), "2006-01-02 15:04 -0700", true},
+		{monthFirst, "5/1/2024 8:30 PM", time.Date(2024, 5, 1, 20, 30, 0, 0, time.UTC), "1/2/2006 3:04 PM", false},
+		{monthFirst, "5/1/2024 12:05am", time.Date(2024, 5, 1, 0, 5, 0, 0, time.UTC), "1/2/2006 03:04PM", false},
+		{dayFirst, "1 May 2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2 January 2006", false},
+		{dayFirst, "1. Mai 2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2. January 2006", false},
+		{dayFirst, "May 1st, 2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "January 2st, 2006", false},
+		{dayFirst, "Wed, 1 May 2024 08:30:00", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC), "Mon, 2 January 2006 15:04:05", false},
+		{dayFirst, "Mittwoch, 1. Mai 2024 um 08:30", time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC), "Monday, 2. January 2006 um 15:04", false},
+		{dayFirst, "1 de mayo de 2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2 de January de 2006", false},
+		{dayFirst, "1 maja 2024", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2 January 2006", false},
+		{dayFirst, "3. Sept. 2024", time.Date(2024, 9, 3, 0, 0, 0, 0, time.UTC), "2. Jan. 2006", false},
+		{dayFirst, "01-mar-2024", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "02-Jan-2006", false},
+		{dayFirst, "mar. 5 mars 2024", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "Mon. 2 January 2006", false},
+		{dayFirst, "2024 May 1", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "2006 January 2", false},
+	}
+	for _, tt := range tests {
+		d, layout, err := tt.parser.Parse(tt.str)
+		if assert.NoError(t, err, tt.str) {
+			assert.True(t, tt.expected.Equal(d.Time), "%s: %v", tt.str, d.Time)
+			assert.Equal(t, tt.layout, layout, tt.str)
+			assert.Equal(t, tt.hasZone, d.HasZone(), tt.str)
+		}
+	}
+
+	for _, str := range []string{"", "tomorrow", "31.02.2024", "13/13/2024", "1.5-2024", "1 May", "May 2024", "1.5.2024 25:00", "1.5.2024 8:61", "1.5.2024 13:00 PM", "1.5.202", "1 Foo 2024", "8:30"} {
+		_, _, err := dayFirst.Parse(str)
+		assert.Error(t, err, str)
+	}
+
+	// day and month are only swapped if enabled
+	_, _, err := dayFirst.Parse("05/13/2024")
+	assert.Error(t, err)
+	_, _, err = monthFirst.Parse("13/05/2024")
+	assert.Error(t, err)
+	_, layout, err := ParseFlexible("05/13/2024")
+	assert.NoError(t, err)
+	assert.Equal(t, "01/02/2006", layout)
+
+	// languages restrict the month names
+	_, _, err = NewParser(DayFirst, language.English).Parse("1. Mai 2024")
+	assert.Error(t, err)
+	d, _, err := NewParser(DayFirst, language.German).Parse("1. Mai 2024")
+	assert.NoError(t, err)
+	assert.Equal(t, New(2024, 5, 1, 0, 0, 0), d)
+}
@@ -0,0 +1,80 @@
+package datetime
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// scanLayouts contains the additional formats of database values, fractional seconds are optional.
+var scanLayouts = []struct {
+	layout  string
+	hasZone bool
+}{
+	{"2006-01-02 15:04:05.999999999", false},
+	{"2006-01-02T15:04:05.999999999", false},
+	{"2006-01-02 15:04:05.999999999Z07:00", true},
+	{"2006-01-02T15:04:05.999999999Z07:00", true},
+	{"2006-01-02 15:04:05.999999999Z07", true},
+}
+
+// Scan reads a DateTime from a database value, it implements sql.Scanner. NULL and empty strings
+// result in the VB zero date. Values of type time.Time in UTC are read as zone-less DATETIME values
+// with their wall clock time, values in any other location or with an offset keep it and have a zone.
+// Drivers should therefore return zone-less columns in UTC. Strings are parsed with Parse and
+// additionally accept fractional seconds and offsets as written by Value or by databases, e.g.
+// "2024-05-01 10:00:00.5" or "2024-05-01 10:00:00+02". Only strings with offset result in values with zone.
+func (s *DateTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = ZeroDate()
+		return nil
+	case time.Time:
+		if v.Location() != time.UTC {
+			*s = DateTime{Time: v, hasZone: true}
+			return nil
+		}
+		*s = DateTime{Time: v}
+		return nil
+	case []byte:
+		return s.scanString(string(v))
+	case string:
+		return s.scanString(v)
+	default:
+		return fmt.Errorf("%T %+v is not a meaningful date time", value, value)
+	}
+}
+
+func (s *DateTime) scanString(str string) error {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		*s = ZeroDate()
+		return nil
+	}
+	if d, ok := Parse(str); ok {
+		*s = d
+		return nil
+	}
+	for _, l := range scanLayouts {
+		if t, err := time.ParseInLocation(l.layout, str, time.UTC); err == nil {
+			*s = DateTime{Time: t, hasZone: l.hasZone}
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a meaningful date time", str)
+}
+
+// Value converts the DateTime to a database value, it implements driver.Valuer. Zone-less values
+// are returned as naive DATETIME strings such as "2024-05-01 10:00:00", values with zone as time.Time
+// for TIMESTAMP WITH TIME ZONE columns. Scan reads values with zone in UTC back as zone-less values,
+// see Scan. The VB zero date is stored as NULL.
+func (s DateTime) Value() (driver.Value, error) {
+	if s.IsZero() {
+		return nil, nil
+	}
+	if s.hasZone {
+		return s.Time, nil
+	}
+	return s.Time.Format("2006-01-02 15:04:05.999999999"), nil
+}
@@ -0,0 +1,75 @@
+package decimal
+
+import (
+	"math"
+	"math/bits"
+)
+
+// AddChecked adds another Decimal and reports whether the result is in the range of Decimal.
+func (s Decimal) AddChecked(d Decimal) (Decimal, bool) {
+	r := s + d
+	// overflow if both operands have the same sign and the result has a different one
+	if (s >= 0) == (d >= 0) && (r >= 0) != (s >= 0) {
+		return 0, false
+	}
+	return r, true
+}
+
+// SubChecked subtracts another Decimal and reports whether the result is in the range of Decimal.
+func (s Decimal) SubChecked(d Decimal) (Decimal, bool) {
+	r := s - d
+	if (s >= 0) != (d >= 0) && (r >= 0) != (s >= 0) {
+		return 0, false
+	}
+	return r, true
+}
+
+// MulChecked multiplies with another Decimal like Mult and reports whether the result is in the range of Decimal.
+func (s Decimal) MulChecked(d Decimal) (Decimal, bool) {
+	return mulDiv(int64(s), int64(d), Decimals, false)
+}
+
+// DivChecked divides by another Decimal like DivDecimal and reports whether the divisor is not zero
+// and the result is in the range of Decimal.
+func (s Decimal) DivChecked(d Decimal) (Decimal, bool) {
+	if d == 0 {
+		return 0, false
+	}
+	return mulDiv(int64(s), Decimals, int64(d), true)
+}
+
+// mulDiv calculates a * b / c with a 128 bit intermediate product, truncated towards zero. If roundUp
+// is true, positive results are rounded up if the next digit is 5 or more, as done by DivDecimal.
+// The bool is false if the result does not fit into a Decimal, the Decimal then contains the
+// wrapped-around lower 64 bits. Panics if c is zero.
+func mulDiv(a, b, c int64, roundUp bool) (Decimal, bool) {
+	neg := (a < 0) != (b < 0) != (c < 0)
+	hi, lo := bits.Mul64(uabs(a), uabs(b))
+	uc := uabs(c)
+
+	// divide in two steps, bits.Div64 panics if the quotient does not fit into 64 bits
+	qhi := hi / uc
+	q, rem := bits.Div64(hi%uc, lo, uc)
+	ok := qhi == 0
+	if roundUp && !neg && rem >= uc-rem { // 10*rem/uc >= 5
+		q++
+		ok = ok && q != 0
+	}
+
+	r := int64(q)
+	if neg {
+		r = -r
+		ok = ok && q <= 1<<63
+	} else {
+		ok = ok && q <= math.MaxInt64
+	}
+	return Decimal(r), ok
+}
+
+// uabs returns the absolute value as uint64, which also works for math.MinInt64.
+func uabs(x int64) uint64 {
+	if x < 0 {
+		return uint64(-x)
+	}
+	return uint64(x)
+}
@@ -0,0 +1,135 @@
+package decimal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var edgeValues = []Decimal{0, 1, -1, Decimals, -Decimals, 5000, -5000, 3, Max, Min, Max - 1, Min + 1, Max / Decimals, Min / Decimals}
+
+// bigResult checks if the big.Int result fits into a Decimal and returns it.
+func bigResult(r *big.Int) (Decimal, bool) {
+	if !r.IsInt64() {
+		return 0, false
+	}
+	return Decimal(r.Int64()), true
+}
+
+// bigMul calculates s * d / Decimals truncated towards zero.
+func bigMul(s, d Decimal) (Decimal, bool) {
+	r := new(big.Int).Mul(big.NewInt(int64(s)), big.NewInt(int64(d)))
+	return bigResult(r.Quo(r, big.NewInt(Decimals)))
+}
+
+// bigDiv calculates s * Decimals / d, positive results rounded half up, negative results truncated.
+func bigDiv(s, d Decimal) (Decimal, bool) {
+	if d == 0 {
+		return 0, false
+	}
+	n := new(big.Int).Mul(big.NewInt(int64(s)), big.NewInt(Decimals))
+	bd := big.NewInt(int64(d))
+	q, m := new(big.Int).QuoRem(n, bd, new(big.Int))
+	if n.Sign()*bd.Sign() > 0 && new(big.Int).Lsh(m, 1).CmpAbs(bd) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return bigResult(q)
+}
+
+func bigAdd(s, d Decimal) (Decimal, bool) {
+	return bigResult(new(big.Int).Add(big.NewInt(int64(s)), big.NewInt(int64(d))))
+}
+
+func bigSub(s, d Decimal) (Decimal, bool) {
+	return bigResult(new(big.Int).Sub(big.NewInt(int64(s)), big.NewInt(int64(d))))
+}
+
+func TestDecimal_Checked(t *testing.T) {
+	_, ok := Max.AddChecked(1)
+	assert.False(t, ok)
+	_, ok = Min.SubChecked(1)
+	assert.False(t, ok)
+	r, ok := Min.AddChecked(Max)
+	assert.True(t, ok)
+	assert.Equal(t, Decimal(-1), r)
+
+	r, ok = Decimal(25000).MulChecked(Decimal(15000))
+	assert.True(t, ok)
+	assert.Equal(t, Decimal(37500), r)
+	_, ok = Max.MulChecked(Decimals + 1)
+	assert.False(t, ok)
+	r, ok = Min.MulChecked(Decimals)
+	assert.True(t, ok)
+	assert.Equal(t, Min, r)
+	_, ok = Min.MulChecked(-Decimals)
+	assert.False(t, ok)
+
+	r, ok = FromInt(2).DivChecked(FromInt(3))
+	assert.True(t, ok)
+	assert.Equal(t, Decimal(6667), r)
+	r, ok = FromInt(-2).DivChecked(FromInt(3))
+	assert.True(t, ok)
+	assert.Equal(t, Decimal(-6666), r)
+	_, ok = FromInt(1).DivChecked(0)
+	assert.False(t, ok)
+	_, ok = Min.DivChecked(-Decimals)
+	assert.False(t, ok)
+	r, ok = Max.DivChecked(Max)
+	assert.True(t, ok)
+	assert.Equal(t, Decimal(Decimals), r)
+}
+
+func TestDecimal_MultDivLarge(t *testing.T) {
+	// intermediate results exceed 64 bits
+	x := FromInt(1000000000000)
+	assert.Equal(t, FromInt(1500000000000), x.Mult(FromFloat(1.5)))
+	assert.Equal(t, FromInt(500000000000), x.DivDecimal(FromInt(2)))
+	assert.Equal(t, FromInt(-1000000000000), x.DivDecimal(FromInt(-1)))
+	assert.Panics(t, func() { x.DivDecimal(0) })
+}
+
+func TestDecimal_CheckedEdges(t *testing.T) {
+	for _, a := range edgeValues {
+		for _, b := range edgeValues {
+			checkAgainstBig(t, a, b)
+		}
+	}
+}
+
+func checkAgainstBig(t *testing.T, a, b Decimal) {
+	ops := []struct {
+		name    string
+		checked func(a, b Decimal) (Decimal, bool)
+		big     func(a, b Decimal) (Decimal, bool)
+	}{
+		{"Add", Decimal.AddChecked, bigAdd},
+		{"Sub", Decimal.SubChecked, bigSub},
+		{"Mul", Decimal.MulChecked, bigMul},
+		{"Div", Decimal.DivChecked, bigDiv},
+	}
+	for _, op := range ops {
+		want, wantOK := op.big(a, b)
+		got, gotOK := op.checked(a, b)
+		if gotOK != wantOK || (wantOK && got != want) {
+			t.Fatalf("%s(%d, %d): got %d, %v, want %d, %v", op.name, a, b, got, gotOK, want, wantOK)
+		}
+	}
+	if want, ok := bigMul(a, b); ok && a.Mult(b) != want {
+		t.Fatalf("Mult(%d, %d): got %d, want %d", a, b, a.Mult(b), want)
+	}
+	if want, ok := bigDiv(a, b); ok && a.DivDecimal(b) != want {
+		t.Fatalf("DivDecimal(%d, %d): got %d, want %d", a, b, a.DivDecimal(b), want)
+	}
+}
+
+func FuzzDecimal_Checked(f *testing.F) {
+	for _, a := range edgeValues {
+		for _, b := range edgeValues {
+			f.Add(int64(a), int64(b))
+		}
+	}
+	f.Fuzz(func(t *testing.T, a, b int64) {
+		checkAgainstBig(t, Decimal(a), Decimal(b))
+	})
+}
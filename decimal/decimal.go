@@ -153,10 +153,9 @@ func (s Decimal) RoundUp(numberOfDecimals int) Decimal {
 
 // Mult multiplies the Decimal with another Decimal
 func (s Decimal) Mult(d Decimal) Decimal {
-	if d.IsInt() { // to avoid overflow for large numbers
-		return s.MultInt(d.ToInt())
-	}
-	return s * d / Decimals
+	// 128 bit intermediate result to avoid overflow for large numbers
+	r, _ := mulDiv(int64(s), int64(d), Decimals, false)
+	return r
 }
 
 // MultInt multiplies the decimal with an int and returns a new decimal
@@ -180,12 +179,8 @@ func (s Decimal) Div(f float64) float64 {
 // DivDecimal divides the Decimal by another Decimal and returns a Decimal
 func (s Decimal) DivDecimal(d Decimal) Decimal {
 	// todo: rounding for exact results as old SES, may be removed later again
-	x := (10 * s * Decimals / d) % 10
-	if x >= 5 {
-		return s*Decimals/d + 1
-	}
-
-	return s * Decimals / d
+	r, _ := mulDiv(int64(s), Decimals, int64(d), true)
+	return r
 }
 
 // EqualInt checks if the number is equal to the given int
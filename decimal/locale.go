@@ -0,0 +1,424 @@
+package decimal
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// FormatStyle defines how FormatLocale formats a number.
+type FormatStyle int
+
+const (
+	// StyleDecimal formats a plain number, e.g. 1.234,5 in German.
+	StyleDecimal FormatStyle = iota
+	// StylePercent multiplies the number by 100 and adds the percent sign, e.g. 50 % for 0.5 in German.
+	StylePercent
+	// StyleCurrency adds the currency symbol, e.g. 12,50 € in German or €12.50 in English.
+	StyleCurrency
+)
+
+// AutoDecimals can be used as FormatOptions.Decimals to show only the decimals needed.
+const AutoDecimals = -1
+
+// FormatOptions contains the options for FormatLocale.
+type FormatOptions struct {
+	Style FormatStyle
+	// Decimals is the number of decimals, the number is rounded half up. AutoDecimals shows
+	// up to 4 decimals without trailing zeros.
+	Decimals int
+	// Currency is the ISO 4217 code of the currency for StyleCurrency.
+	Currency string
+	// NoGrouping omits the group separators.
+	NoGrouping bool
+}
+
+// localeSymbols contains the number symbols of a locale.
+type localeSymbols struct {
+	decimal        string
+	group          string
+	primaryGroup   int
+	secondaryGroup int
+	minus          string
+	percentPrefix  string
+	percentSuffix  string
+	currencyAfter  bool
+	currency       string // symbol of the currency of the region, e.g. zł in Poland
+}
+
+var symbolCache sync.Map
+
+// currencyAfterLanguages lists the languages which put the currency symbol after the number.
+var currencyAfterLanguages = map[string]bool{
+	"be": true, "bg": true, "ca": true, "cs": true, "da": true, "de": true, "el": true, "es": true,
+	"et": true, "fi": true, "fr": true, "hr": true, "hu": true, "is": true, "it": true, "lt": true,
+	"lv": true, "nb": true, "no": true, "pl": true, "pt": true, "ro": true, "ru": true, "sk": true,
+	"sl": true, "sr": true, "sv": true, "uk": true,
+}
+
+// currencyBeforeRegions lists the exceptions of currencyAfterLanguages which put the currency
+// symbol in front of the number.
+var currencyBeforeRegions = map[string]bool{
+	"de-AT": true, "de-CH": true, "de-LI": true, "it-CH": true, "pt-BR": true,
+}
+
+// symbols returns the number symbols of the locale. They are derived from the CLDR data of
+// golang.org/x/text by formatting sample numbers with latin digits.
+func symbols(tag language.Tag) *localeSymbols {
+	if v, ok := symbolCache.Load(tag); ok {
+		return v.(*localeSymbols)
+	}
+
+	t, err := tag.SetTypeForKey("nu", "latn")
+	if err != nil {
+		t = tag
+	}
+	p := message.NewPrinter(t)
+	sy := &localeSymbols{decimal: ".", primaryGroup: 3, secondaryGroup: 3}
+
+	// 1234567.5, e.g. "1.234.567,5" or "12,34,567.5"
+	s := p.Sprint(number.Decimal(1234567.5, number.Scale(1)))
+	if i := strings.LastIndex(s, "5"); i > 0 {
+		intPart := s[:strings.LastIndex(s[:i], "7")+1]
+		sy.decimal = s[len(intPart):i]
+		groups := strings.FieldsFunc(intPart, func(r rune) bool { return r < '0' || r > '9' })
+		if len(groups) > 1 {
+			sep := intPart[len(groups[0]):]
+			sy.group = sep[:strings.Index(sep, groups[1])]
+			sy.primaryGroup = len(groups[len(groups)-1])
+			sy.secondaryGroup = len(groups[len(groups)-2])
+		}
+	}
+
+	s = p.Sprint(number.Decimal(-1))
+	sy.minus = strings.TrimSuffix(s, "1")
+
+	s = p.Sprint(number.Percent(0.5))
+	if i := strings.Index(s, "50"); i >= 0 {
+		sy.percentPrefix, sy.percentSuffix = s[:i], s[i+2:]
+	} else {
+		sy.percentSuffix = "%"
+	}
+
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	sy.currencyAfter = currencyAfterLanguages[base.String()] && !currencyBeforeRegions[base.String()+"-"+region.String()]
+	if u, conf := currency.FromTag(tag); conf != language.No {
+		sy.currency = currencySymbol(tag, u.String())
+	}
+
+	symbolCache.Store(tag, sy)
+	return sy
+}
+
+// currencySymbol returns the symbol of the currency in the locale, e.g. € or CHF. Unknown
+// currencies are returned as they are.
+func currencySymbol(tag language.Tag, code string) string {
+	u, err := currency.ParseISO(code)
+	if err != nil {
+		return code
+	}
+	s := message.NewPrinter(tag).Sprint(currency.Symbol(u))
+	return strings.TrimSpace(s)
+}
+
+// FormatLocale formats the number according to the conventions of the locale: decimal and group
+// separators, grouping (e.g. 12,34,567 in India), minus sign, percent and currency format. The
+// currency symbol is placed after the number for most European languages, otherwise in front of it.
+func FormatLocale(d Decimal, tag language.Tag, opts FormatOptions) string {
+	sy := symbols(tag)
+
+	if opts.Style == StylePercent {
+		x, ok := d.MulChecked(FromInt(100))
+		if !ok {
+			x = Max
+			if d < 0 {
+				x = Min
+			}
+		}
+		d = x
+	}
+	if opts.Decimals >= 0 {
+		d = d.RoundScale(opts.Decimals, HalfUp)
+	}
+
+	// digits of the absolute value
+	u := uabs(int64(d))
+	intPart := strconv.FormatUint(u/Decimals, 10)
+	frac := strconv.FormatUint(u%Decimals+Decimals, 10)[1:]
+	if opts.Decimals >= 0 {
+		if opts.Decimals <= len(frac) {
+			frac = frac[:opts.Decimals]
+		} else {
+			frac += strings.Repeat("0", opts.Decimals-len(frac))
+		}
+	} else {
+		frac = strings.TrimRight(frac, "0")
+	}
+
+	var sb strings.Builder
+	if d < 0 {
+		sb.WriteString(sy.minus)
+	}
+	var symbol string
+	if opts.Style == StyleCurrency {
+		symbol = currencySymbol(tag, opts.Currency)
+		if !sy.currencyAfter {
+			sb.WriteString(symbol)
+			if r, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(r) || sy.decimal == "," {
+				sb.WriteString("\u00a0")
+			}
+		}
+	}
+	if opts.Style == StylePercent {
+		sb.WriteString(sy.percentPrefix)
+	}
+
+	if opts.NoGrouping || sy.group == "" || len(intPart) <= sy.primaryGroup {
+		sb.WriteString(intPart)
+	} else {
+		first := len(intPart) - sy.primaryGroup
+		var groups []string
+		groups = append(groups, intPart[first:])
+		for first > sy.secondaryGroup {
+			groups = append(groups, intPart[first-sy.secondaryGroup:first])
+			first -= sy.secondaryGroup
+		}
+		groups = append(groups, intPart[:first])
+		for i := len(groups) - 1; i >= 0; i-- {
+			sb.WriteString(groups[i])
+			if i > 0 {
+				sb.WriteString(sy.group)
+			}
+		}
+	}
+	if frac != "" {
+		sb.WriteString(sy.decimal)
+		sb.WriteString(frac)
+	}
+
+	switch {
+	case opts.Style == StylePercent:
+		sb.WriteString(sy.percentSuffix)
+	case opts.Style == StyleCurrency && sy.currencyAfter:
+		sb.WriteString("\u00a0")
+		sb.WriteString(symbol)
+	}
+	return sb.String()
+}
+
+// ParseLocale parses a number formatted according to the conventions of the locale, e.g. "1.234,56"
+// in German or "1’234.56" in Swiss German. It accepts group separators, a leading or trailing minus
+// or plus sign, negative numbers in parentheses, scientific notation such as "1,5E3", a percent sign
+// (the number is divided by 100) and a currency symbol or ISO code in front of or after the number.
+// Currency symbols are symbols such as € or $ and the symbol of the currency of the locale, e.g. zł
+// in Polish. Other text results in an error.
+// Group separators must sit every three digits counted from the decimal separator or at the grouping
+// sizes of the locale, e.g. "12,34,567" in India. The result is rounded half up to 4 decimals.
+func ParseLocale(s string, tag language.Tag) (Decimal, error) {
+	sy := symbols(tag)
+	errInvalid := errors.New("not a valid number: " + s)
+
+	s = strings.TrimSpace(s)
+	var neg, percent bool
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg = true
+		s = s[1 : len(s)-1]
+	}
+
+	var mantissa, exponent strings.Builder
+	var hasSign, hasDecimal, hasDigits, inExponent, afterNumber, hasCurrency bool
+	var groups []int // number of digits between the group separators
+	groupDigits := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rest := s[i:]
+		i += size
+
+		switch {
+		case r >= '0' && r <= '9':
+			if afterNumber {
+				return 0, errInvalid
+			}
+			if inExponent {
+				exponent.WriteRune(r)
+			} else {
+				mantissa.WriteRune(r)
+				hasDigits = true
+				if !hasDecimal {
+					groupDigits++
+				}
+			}
+		case strings.HasPrefix(rest, sy.decimal) && !inExponent:
+			if hasDecimal || afterNumber {
+				return 0, errInvalid
+			}
+			hasDecimal = true
+			mantissa.WriteByte('.')
+			i += len(sy.decimal) - size
+		case groupSeparatorLen(rest, sy.group) > 0 && startsWithDigit(rest[groupSeparatorLen(rest, sy.group):]):
+			if !hasDigits || hasDecimal || inExponent || afterNumber {
+				return 0, errInvalid
+			}
+			i += groupSeparatorLen(rest, sy.group) - size
+			groups = append(groups, groupDigits)
+			groupDigits = 0
+		case r == '-' || r == '+' || r == '\u2212':
+			if inExponent && exponent.Len() == 0 {
+				if r != '+' {
+					exponent.WriteByte('-')
+				}
+				continue
+			}
+			if hasSign {
+				return 0, errInvalid
+			}
+			hasSign = true
+			if hasDigits {
+				afterNumber = true
+			}
+			neg = neg != (r != '+')
+		case (r == 'e' || r == 'E') && hasDigits && !inExponent && !afterNumber && isExponentStart(rest[1:]):
+			inExponent = true
+		case r == '%' || r == '\u066a':
+			if percent {
+				return 0, errInvalid
+			}
+			percent = true
+			if hasDigits {
+				afterNumber = true
+			}
+		case unicode.IsSpace(r) || r == '\u200e' || r == '\u200f' || r == '\u061c':
+			if hasDigits {
+				afterNumber = true
+			}
+		case unicode.IsLetter(r) || unicode.Is(unicode.Sc, r) || r == '.':
+			// currency symbol or code in front of or after the number
+			start := i - size
+			for i < len(s) {
+				r, size := utf8.DecodeRuneInString(s[i:])
+				if !unicode.IsLetter(r) && !unicode.Is(unicode.Sc, r) && r != '.' {
+					break
+				}
+				i += size
+			}
+			if hasCurrency || !sy.isCurrency(s[start:i]) {
+				return 0, errInvalid
+			}
+			hasCurrency = true
+			if hasDigits {
+				afterNumber = true
+			}
+		default:
+			return 0, errInvalid
+		}
+	}
+	if !hasDigits || (inExponent && strings.Trim(exponent.String(), "-") == "") {
+		return 0, errInvalid
+	}
+	if groups != nil {
+		groups = append(groups, groupDigits)
+		if !validGrouping(groups, 3, 3) && !validGrouping(groups, sy.primaryGroup, sy.secondaryGroup) {
+			return 0, errInvalid
+		}
+	}
+
+	str := mantissa.String()
+	if inExponent {
+		str += "e" + exponent.String()
+	}
+	x, ok := new(big.Rat).SetString(str)
+	if !ok {
+		return 0, errInvalid
+	}
+	if percent {
+		x.Quo(x, big.NewRat(100, 1))
+	}
+	if neg {
+		x.Neg(x)
+	}
+
+	// round half up to 4 decimals
+	x.Mul(x, big.NewRat(Decimals, 1))
+	q, m := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if new(big.Int).Lsh(m, 1).CmpAbs(x.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(x.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, errors.New("number out of range: " + s)
+	}
+	return Decimal(q.Int64()), nil
+}
+
+// isCurrency checks if the text is an ISO 4217 code, consists of currency symbols such as € or $
+// or is the symbol of the currency of the locale.
+func (s *localeSymbols) isCurrency(text string) bool {
+	if text == s.currency {
+		return true
+	}
+	if len(text) == 3 && strings.ToUpper(text) == text {
+		if _, err := currency.ParseISO(text); err == nil {
+			return true
+		}
+	}
+	for _, r := range text {
+		if !unicode.Is(unicode.Sc, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// groupSeparatorLen returns the length of the group separator of the locale at the start of the
+// string, or 0. Any kind of space is accepted for space separators and an apostrophe for ’.
+func groupSeparatorLen(s, group string) int {
+	if group == "" {
+		return 0
+	}
+	if strings.HasPrefix(s, group) {
+		return len(group)
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	g, _ := utf8.DecodeRuneInString(group)
+	if unicode.IsSpace(g) && unicode.IsSpace(r) || g == '’' && r == '\'' {
+		return size
+	}
+	return 0
+}
+
+// validGrouping checks if the number of digits between the group separators of the integer part
+// matches the grouping sizes: the last group has primary digits, the groups in between secondary
+// digits and the first group between 1 and secondary digits.
+func validGrouping(groups []int, primary, secondary int) bool {
+	last := len(groups) - 1
+	if groups[last] != primary || groups[0] < 1 || groups[0] > secondary {
+		return false
+	}
+	for _, n := range groups[1:last] {
+		if n != secondary {
+			return false
+		}
+	}
+	return true
+}
+
+// isExponentStart checks if the string is the exponent of a number in scientific notation.
+func isExponentStart(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	return startsWithDigit(s)
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
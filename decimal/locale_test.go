@@ -0,0 +1,112 @@
+package decimal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestFormatLocale(t *testing.T) {
+	x := FromFloat(1234567.456)
+	tests := []struct {
+		tag      string
+		opts     FormatOptions
+		value    Decimal
+		expected string
+	}{
+		{"en", FormatOptions{Decimals: 2}, x, "1,234,567.46"},
+		{"de", FormatOptions{Decimals: 2}, x, "1.234.567,46"},
+		{"de-CH", FormatOptions{Decimals: 2}, x, "1’234’567.46"},
+		{"fr", FormatOptions{Decimals: 1}, x, "1 234 567,5"},
+		{"en-IN", FormatOptions{Decimals: 0}, x, "12,34,567"},
+		{"de", FormatOptions{Decimals: AutoDecimals}, -x, "-1.234.567,456"},
+		{"de", FormatOptions{Decimals: 2, NoGrouping: true}, x, "1234567,46"},
+		{"sv", FormatOptions{Decimals: AutoDecimals}, FromInt(-5), "−5"},
+		{"de", FormatOptions{Decimals: 6}, FromFloat(0.5), "0,500000"},
+		{"en", FormatOptions{Decimals: 2}, FromFloat(-0.001), "0.00"},
+		{"en", FormatOptions{Style: StylePercent}, FromFloat(0.125), "13%"},
+		{"de", FormatOptions{Style: StylePercent, Decimals: 1}, FromFloat(0.125), "12,5 %"},
+		{"en", FormatOptions{Style: StyleCurrency, Currency: "EUR", Decimals: 2}, x, "€1,234,567.46"},
+		{"en", FormatOptions{Style: StyleCurrency, Currency: "USD", Decimals: 2}, -x, "-$1,234,567.46"},
+		{"de", FormatOptions{Style: StyleCurrency, Currency: "EUR", Decimals: 2}, x, "1.234.567,46 €"},
+		{"de-CH", FormatOptions{Style: StyleCurrency, Currency: "CHF", Decimals: 2}, x, "CHF 1’234’567.46"},
+		{"nl", FormatOptions{Style: StyleCurrency, Currency: "EUR", Decimals: 2}, FromInt(12), "€ 12,00"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FormatLocale(tt.value, language.MustParse(tt.tag), tt.opts), "%s %+v", tt.tag, tt.opts)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		tag      string
+		value    string
+		expected Decimal
+	}{
+		{"de", "1.234,56", FromFloat(1234.56)},
+		{"de", "1234,56", FromFloat(1234.56)},
+		{"de", "-1.234", FromInt(-1234)},
+		{"de", "1.234,56-", FromFloat(-1234.56)},
+		{"de", "1.234,56 €", FromFloat(1234.56)},
+		{"de", "EUR -12,5", FromFloat(-12.5)},
+		{"de", "12 USD", FromInt(12)},
+		{"pl", "12,50 zł", FromFloat(12.5)},
+		{"en", "-$12", FromInt(-12)},
+		{"de", "(12,50 €)", FromFloat(-12.5)},
+		{"de", "12,5 %", FromFloat(0.125)},
+		{"de", "1,5E3", FromInt(1500)},
+		{"de", "1,5e-2", FromFloat(0.015)},
+		{"de", "0,00005", 1},
+		{"de", "-0,00005", -1},
+		{"de-CH", "1’234.56", FromFloat(1234.56)},
+		{"de-CH", "1'234.56", FromFloat(1234.56)},
+		{"de-CH", "CHF 1'234.50", FromFloat(1234.5)},
+		{"fr", "1 234,5", FromFloat(1234.5)},
+		{"fr", "1 234,5 €", FromFloat(1234.5)},
+		{"sv", "−5", FromInt(-5)},
+		{"en", "1,234.56", FromFloat(1234.56)},
+		{"en", "$1,234.56", FromFloat(1234.56)},
+		{"en", "+12", FromInt(12)},
+		{"en", "1.5E+3", FromInt(1500)},
+		{"en-IN", "12,34,567", FromInt(1234567)},
+		{"en-IN", "12,34,567.5", FromFloat(1234567.5)},
+		{"en-IN", "1,234,567", FromInt(1234567)},
+		{"en", "12,345,678.9", FromFloat(12345678.9)},
+	}
+	for _, tt := range tests {
+		x, err := ParseLocale(tt.value, language.MustParse(tt.tag))
+		if assert.NoError(t, err, "%s %q", tt.tag, tt.value) {
+			assert.Equal(t, tt.expected, x, "%s %q", tt.tag, tt.value)
+		}
+	}
+
+	for _, s := range []string{"", "abc", "1,2,3", "1.234,5,6", "12 34", "--1", ",5.000", "99999999999999999",
+		"12abc", "1e5x", "abc 12", "12 Euro", "EUR 12 €", "12 usd", "12 ABC", "€ 12 5"} {
+		_, err := ParseLocale(s, language.German)
+		assert.Error(t, err, s)
+	}
+
+	// misplaced group separators
+	for _, s := range []string{"1,5", "1,23,4.5", "1234,567", "12,34,567", "1,2345"} {
+		_, err := ParseLocale(s, language.English)
+		assert.Error(t, err, s)
+	}
+	_, err := ParseLocale("1.23,5", language.German)
+	assert.Error(t, err)
+
+	// round trip
+	for _, tag := range []string{"en", "de", "de-CH", "fr", "sv", "en-IN", "ar"} {
+		for _, x := range []Decimal{0, FromFloat(1234567.8912), FromFloat(-0.5), Max, Min + 1} {
+			for _, style := range []FormatStyle{StyleDecimal, StylePercent, StyleCurrency} {
+				if style == StylePercent && (x == Max || x == Min+1) {
+					continue
+				}
+				s := FormatLocale(x, language.MustParse(tag), FormatOptions{Style: style, Decimals: AutoDecimals, Currency: "EUR"})
+				y, err := ParseLocale(s, language.MustParse(tag))
+				assert.NoError(t, err, s)
+				assert.Equal(t, x, y, "%s %q", tag, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package decimal
+
+// RoundingMode defines how a Decimal is rounded by RoundTo and RoundScale.
+type RoundingMode int
+
+const (
+	// HalfUp rounds to the nearest value, halves are rounded away from zero.
+	HalfUp RoundingMode = iota
+	// HalfEven rounds to the nearest value, halves are rounded to the even multiple (banker's rounding).
+	HalfEven
+	// Down rounds towards zero (truncates).
+	Down
+	// Up rounds away from zero.
+	Up
+	// Floor rounds towards negative infinity.
+	Floor
+	// Ceiling rounds towards positive infinity.
+	Ceiling
+)
+
+// maxScaleStep is the largest power of ten that fits into a Decimal, used for scale -14.
+const maxScaleStep = Decimal(1e18)
+
+// RoundTo rounds the Decimal to a multiple of step, e.g. to 0.05 with step 500. The sign of step
+// is ignored, a step of 0 returns the Decimal unchanged. If the rounded value is out of the range
+// of Decimal, the next multiple towards zero is returned.
+func (s Decimal) RoundTo(step Decimal, mode RoundingMode) Decimal {
+	if step < 0 {
+		step = -step
+	}
+	if step <= 0 { // 0 or Min
+		return s
+	}
+
+	q := s / step
+	r := s % step
+	if r == 0 {
+		return s
+	}
+	if r < 0 {
+		r = -r
+	}
+
+	var away bool
+	switch mode {
+	case HalfUp:
+		away = r >= step-r
+	case HalfEven:
+		away = r > step-r || (r == step-r && q%2 != 0)
+	case Up:
+		away = true
+	case Floor:
+		away = s < 0
+	case Ceiling:
+		away = s > 0
+	}
+
+	t := q * step
+	if !away {
+		return t
+	}
+	if s < 0 {
+		step = -step
+	}
+	if x, ok := t.AddChecked(step); ok {
+		return x
+	}
+	return t
+}
+
+// RoundScale rounds the Decimal to the given number of decimals. Negative scales round to tens,
+// hundreds etc., e.g. -1 rounds to tens. Scales of 4 or more return the Decimal unchanged, scales
+// below -14 are treated as -14.
+func (s Decimal) RoundScale(scale int, mode RoundingMode) Decimal {
+	if scale >= 4 {
+		return s
+	}
+	step := Decimal(1)
+	for i := scale; i < 4; i++ {
+		if step == maxScaleStep {
+			break
+		}
+		step *= 10
+	}
+	return s.RoundTo(step, mode)
+}
@@ -0,0 +1,57 @@
+package decimal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimal_RoundTo(t *testing.T) {
+	// value, step, expected for HalfUp, HalfEven, Down, Up, Floor, Ceiling
+	tests := []struct {
+		value    string
+		step     string
+		expected [6]string
+	}{
+		{"1.25", "0.1", [6]string{"1.3", "1.2", "1.2", "1.3", "1.2", "1.3"}},
+		{"-1.25", "0.1", [6]string{"-1.3", "-1.2", "-1.2", "-1.3", "-1.3", "-1.2"}},
+		{"1.35", "0.1", [6]string{"1.4", "1.4", "1.3", "1.4", "1.3", "1.4"}},
+		{"1.34", "0.1", [6]string{"1.3", "1.3", "1.3", "1.4", "1.3", "1.4"}},
+		{"-1.36", "0.1", [6]string{"-1.4", "-1.4", "-1.3", "-1.4", "-1.4", "-1.3"}},
+		{"12.37", "0.05", [6]string{"12.35", "12.35", "12.35", "12.4", "12.35", "12.4"}},
+		{"12.375", "0.05", [6]string{"12.4", "12.4", "12.35", "12.4", "12.35", "12.4"}},
+		{"12.325", "0.05", [6]string{"12.35", "12.3", "12.3", "12.35", "12.3", "12.35"}},
+		{"1.2", "0.1", [6]string{"1.2", "1.2", "1.2", "1.2", "1.2", "1.2"}},
+		{"0.0001", "1", [6]string{"0", "0", "0", "1", "0", "1"}},
+		{"-0.0001", "1", [6]string{"0", "0", "0", "-1", "-1", "0"}},
+	}
+	for _, tt := range tests {
+		v, _ := FromString(tt.value)
+		step, _ := FromString(tt.step)
+		for mode, e := range tt.expected {
+			want, _ := FromString(e)
+			assert.Equal(t, want, v.RoundTo(step, RoundingMode(mode)), "%s to %s, mode %d", tt.value, tt.step, mode)
+		}
+	}
+
+	assert.Equal(t, Decimal(12345), Decimal(12345).RoundTo(0, Up))
+	assert.Equal(t, FromFloat(1.3), FromFloat(1.25).RoundTo(-1000, HalfUp))
+	assert.Equal(t, Max-Max%10, Max.RoundTo(10, Up))
+	assert.Equal(t, Min-Min%10, Min.RoundTo(10, Floor))
+	assert.Equal(t, Min, Min.RoundTo(Min, HalfUp))
+}
+
+func TestDecimal_RoundScale(t *testing.T) {
+	x := FromFloat(1234.5678)
+	assert.Equal(t, FromFloat(1234.568), x.RoundScale(3, HalfUp))
+	assert.Equal(t, FromFloat(1234.5), x.RoundScale(1, Down))
+	assert.Equal(t, FromInt(1235), x.RoundScale(0, HalfEven))
+	assert.Equal(t, FromInt(1230), x.RoundScale(-1, HalfUp))
+	assert.Equal(t, FromInt(1300), x.RoundScale(-2, Ceiling))
+	assert.Equal(t, FromInt(-1300), (-x).RoundScale(-2, Floor))
+	assert.Equal(t, FromInt(0), x.RoundScale(-4, HalfUp))
+	assert.Equal(t, FromInt(10000), x.RoundScale(-4, Up))
+	assert.Equal(t, x, x.RoundScale(4, Up))
+	assert.Equal(t, Decimal(0), x.RoundScale(-20, HalfUp))
+	assert.Equal(t, Decimal(1e18), x.RoundScale(-20, Up))
+}
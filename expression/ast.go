@@ -0,0 +1,164 @@
+package expression
+
+import (
+	"strings"
+
+	"github.com/raceresult/go-model/variant"
+)
+
+// Operator defines a unary or binary operator.
+type Operator int
+
+// Constants for the different operators.
+const (
+	OpAdd Operator = iota + 1
+	OpSub
+	OpMult
+	OpDiv
+	OpDivInt
+	OpMod
+	OpExp
+	OpConcat
+	OpEqual
+	OpNotEqual
+	OpLess
+	OpLessOrEqual
+	OpGreater
+	OpGreaterOrEqual
+	OpAnd
+	OpOr
+	OpXor
+	OpNot
+	OpNeg
+)
+
+var operatorNames = map[Operator]string{
+	OpAdd:            "+",
+	OpSub:            "-",
+	OpMult:           "*",
+	OpDiv:            "/",
+	OpDivInt:         "\\",
+	OpMod:            "Mod",
+	OpExp:            "^",
+	OpConcat:         "&",
+	OpEqual:          "=",
+	OpNotEqual:       "<>",
+	OpLess:           "<",
+	OpLessOrEqual:    "<=",
+	OpGreater:        ">",
+	OpGreaterOrEqual: ">=",
+	OpAnd:            "And",
+	OpOr:             "Or",
+	OpXor:            "Xor",
+	OpNot:            "Not",
+	OpNeg:            "-",
+}
+
+// String returns the operator as written in a formula.
+func (s Operator) String() string {
+	return operatorNames[s]
+}
+
+// Node is a node of the syntax tree of a parsed formula.
+type Node interface {
+	// String returns the node as formula.
+	String() string
+
+	// eval evaluates the node for a single record.
+	eval(env *Env) (variant.Variant, error)
+
+	// evalList evaluates the node for all records at once.
+	evalList(env *ListEnv) (variant.RList, error)
+}
+
+// Literal is a constant value.
+type Literal struct {
+	Value variant.Variant
+}
+
+// Field is a reference to a field, e.g. [Bib] or Contest.Name.
+type Field struct {
+	Name string
+}
+
+// UnaryExpr is an operation with one operand, e.g. -X or Not X.
+type UnaryExpr struct {
+	Op Operator
+	X  Node
+}
+
+// BinaryExpr is an operation with two operands, e.g. X + Y.
+type BinaryExpr struct {
+	Op Operator
+	X  Node
+	Y  Node
+}
+
+// Call is a function call, e.g. Left([LastName];3).
+type Call struct {
+	Name string
+	Args []Node
+}
+
+// String returns the literal as formula.
+func (s *Literal) String() string {
+	switch variant.GetType(s.Value) {
+	case variant.TypeRString:
+		return "\"" + strings.ReplaceAll(variant.ToString(s.Value), "\"", "\"\"") + "\""
+	case variant.TypeRDate, variant.TypeRDateTime:
+		return "#" + variant.ToString(s.Value) + "#"
+	case variant.TypeRBool:
+		if variant.ToBool(s.Value) {
+			return "True"
+		}
+		return "False"
+	default:
+		return variant.ToString(s.Value)
+	}
+}
+
+// String returns the field reference as formula.
+func (s *Field) String() string {
+	return "[" + s.Name + "]"
+}
+
+// String returns the unary expression as formula.
+func (s *UnaryExpr) String() string {
+	if s.Op == OpNot {
+		return "Not " + s.X.String()
+	}
+	return s.Op.String() + s.X.String()
+}
+
+// String returns the binary expression as formula.
+func (s *BinaryExpr) String() string {
+	return "(" + s.X.String() + " " + s.Op.String() + " " + s.Y.String() + ")"
+}
+
+// String returns the function call as formula.
+func (s *Call) String() string {
+	args := make([]string, len(s.Args))
+	for i, arg := range s.Args {
+		args[i] = arg.String()
+	}
+	return s.Name + "(" + strings.Join(args, ";") + ")"
+}
+
+// Walk calls fn for the node and all its descendants in depth-first order.
+func Walk(node Node, fn func(Node)) {
+	if node == nil {
+		return
+	}
+	fn(node)
+	switch n := node.(type) {
+	case *UnaryExpr:
+		Walk(n.X, fn)
+	case *BinaryExpr:
+		Walk(n.X, fn)
+		Walk(n.Y, fn)
+	case *Call:
+		for _, arg := range n.Args {
+			Walk(arg, fn)
+		}
+	}
+}
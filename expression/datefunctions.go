@@ -0,0 +1,245 @@
+package expression
+
+import (
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/variant"
+)
+
+// Date functions with VB semantics. Arguments are converted to datetimes, empty values result in empty values.
+
+func init() {
+	registerDatePart("Year", func(t time.Time) int { return t.Year() })
+	registerDatePart("Month", func(t time.Time) int { return int(t.Month()) })
+	registerDatePart("Day", func(t time.Time) int { return t.Day() })
+	registerDatePart("Weekday", func(t time.Time) int { return int(t.Weekday()) + 1 })
+	registerDatePart("Hour", func(t time.Time) int { return t.Hour() })
+	registerDatePart("Minute", func(t time.Time) int { return t.Minute() })
+	registerDatePart("Second", func(t time.Time) int { return t.Second() })
+	RegisterFunction("DateAdd", Function{MinArgs: 3, MaxArgs: 3,
+		Call: func(args []variant.Variant) variant.Variant {
+			return vbDateAdd(variant.ToString(args[0]), variant.ToInt(args[1]), args[2])
+		},
+		CallList: dateAddList,
+	})
+	RegisterFunction("DateDiff", Function{MinArgs: 3, MaxArgs: 3,
+		Call: func(args []variant.Variant) variant.Variant {
+			return vbDateDiff(variant.ToString(args[0]), args[1], args[2])
+		},
+		CallList: dateDiffList,
+	})
+}
+
+// registerDatePart registers a function returning a part of a date as int.
+func registerDatePart(name string, fn func(time.Time) int) {
+	call := func(args []variant.Variant) variant.Variant {
+		if args[0] == nil {
+			return nil
+		}
+		return variant.RInt(fn(variant.ToDateTime(args[0]).Time))
+	}
+	RegisterFunction(name, Function{MinArgs: 1, MaxArgs: 1,
+		Call: call,
+		CallList: func(args []variant.RList, n int) variant.RList {
+			switch l := args[0].(type) {
+			case variant.DateList:
+				r := variant.NewIntList(n)
+				for i, d := range l {
+					r[i] = fn(d.In(time.UTC))
+				}
+				return r
+			case variant.DateTimeList:
+				r := variant.NewIntList(n)
+				for i, d := range l {
+					r[i] = fn(d.Time)
+				}
+				return r
+			}
+			return callEach(call, args, n)
+		},
+	})
+}
+
+// vbDateAdd adds n intervals to the date. Intervals of days or more added to a date result in a date,
+// all others in a datetime. Adding months keeps the day unless the month is shorter.
+func vbDateAdd(interval string, n int, v variant.Variant) variant.Variant {
+	if v == nil {
+		return nil
+	}
+	dt := variant.ToDateTime(v)
+	t, timeInterval, ok := addInterval(dt.Time, interval, n)
+	if !ok {
+		return nil
+	}
+	if variant.IsDate(v) && !timeInterval {
+		return variant.RDate(date.NewAt(t))
+	}
+	return variant.RDateTime(datetime.FromTime(t, dt.HasZone()))
+}
+
+// dateAddList is the list implementation of DateAdd. Dates and datetimes are processed without
+// conversion if the interval is the same for all records.
+func dateAddList(args []variant.RList, n int) variant.RList {
+	interval, uniform := sameString(args[0].ToString())
+	_, timeInterval, ok := addInterval(time.Time{}, interval, 0)
+	if uniform && ok {
+		counts := args[1].ToInt()
+		switch l := args[2].(type) {
+		case variant.DateList:
+			if !timeInterval {
+				r := variant.NewDateList(n)
+				for i, d := range l {
+					t, _, _ := addInterval(d.In(time.UTC), interval, counts[i])
+					r[i] = date.NewAt(t)
+				}
+				return r
+			}
+		case variant.DateTimeList:
+			r := variant.NewDateTimeList(n)
+			for i, d := range l {
+				t, _, _ := addInterval(d.Time, interval, counts[i])
+				r[i] = datetime.FromTime(t, d.HasZone())
+			}
+			return r
+		}
+	}
+	return callEach(func(args []variant.Variant) variant.Variant {
+		return vbDateAdd(variant.ToString(args[0]), variant.ToInt(args[1]), args[2])
+	}, args, n)
+}
+
+// addInterval adds n intervals to t. timeInterval is true for hours, minutes and seconds, ok is
+// false for unknown intervals.
+func addInterval(t time.Time, interval string, n int) (result time.Time, timeInterval bool, ok bool) {
+	switch strings.ToLower(interval) {
+	case "yyyy":
+		return addMonths(t, 12*n), false, true
+	case "q":
+		return addMonths(t, 3*n), false, true
+	case "m":
+		return addMonths(t, n), false, true
+	case "y", "d", "w":
+		return t.AddDate(0, 0, n), false, true
+	case "ww":
+		return t.AddDate(0, 0, 7*n), false, true
+	case "h":
+		return t.Add(time.Duration(n) * time.Hour), true, true
+	case "n":
+		return t.Add(time.Duration(n) * time.Minute), true, true
+	case "s":
+		return t.Add(time.Duration(n) * time.Second), true, true
+	default:
+		return t, false, false
+	}
+}
+
+// addMonths adds months to t. The day is reduced to the last day of the month if necessary.
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// vbDateDiff returns the number of interval boundaries between two dates, e.g. DateDiff("yyyy", #2024-12-31#, #2025-01-01#) = 1.
+// Weeks ("ww") count Sundays, "w" counts full weeks.
+func vbDateDiff(interval string, v1, v2 variant.Variant) variant.Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	d, ok := dateDiff(interval, variant.ToDateTime(v1).Time, variant.ToDateTime(v2).Time)
+	if !ok {
+		return nil
+	}
+	return variant.RInt(d)
+}
+
+// dateDiffList is the list implementation of DateDiff. Dates and datetimes are processed without
+// conversion if the interval is the same for all records.
+func dateDiffList(args []variant.RList, n int) variant.RList {
+	interval, uniform := sameString(args[0].ToString())
+	_, valid := dateDiff(interval, time.Time{}, time.Time{})
+	t1, ok1 := timeList(args[1])
+	t2, ok2 := timeList(args[2])
+	if uniform && valid && ok1 && ok2 {
+		r := variant.NewIntList(n)
+		for i := range r {
+			r[i], _ = dateDiff(interval, t1[i], t2[i])
+		}
+		return r
+	}
+	return callEach(func(args []variant.Variant) variant.Variant {
+		return vbDateDiff(variant.ToString(args[0]), args[1], args[2])
+	}, args, n)
+}
+
+// dateDiff returns the number of interval boundaries between the wall clock times of t1 and t2,
+// ok is false for unknown intervals.
+func dateDiff(interval string, t1, t2 time.Time) (int, bool) {
+	t1, t2 = wallClock(t1), wallClock(t2)
+	days := func(t time.Time) int64 {
+		return floorDiv(t.Unix(), 86400)
+	}
+	switch strings.ToLower(interval) {
+	case "yyyy":
+		return t2.Year() - t1.Year(), true
+	case "q":
+		return 4*(t2.Year()-t1.Year()) + (int(t2.Month())-1)/3 - (int(t1.Month())-1)/3, true
+	case "m":
+		return 12*(t2.Year()-t1.Year()) + int(t2.Month()) - int(t1.Month()), true
+	case "y", "d":
+		return int(days(t2) - days(t1)), true
+	case "w":
+		return int((days(t2) - days(t1)) / 7), true
+	case "ww":
+		// 1970-01-01 was a Thursday, shift so that weeks start on Sunday
+		return int(floorDiv(days(t2)+4, 7) - floorDiv(days(t1)+4, 7)), true
+	case "h":
+		return int(floorDiv(t2.Unix(), 3600) - floorDiv(t1.Unix(), 3600)), true
+	case "n":
+		return int(floorDiv(t2.Unix(), 60) - floorDiv(t1.Unix(), 60)), true
+	case "s":
+		return int(t2.Unix() - t1.Unix()), true
+	default:
+		return 0, false
+	}
+}
+
+// timeList returns the times of a DateList or DateTimeList, ok is false for other lists.
+func timeList(l variant.RList) ([]time.Time, bool) {
+	switch v := l.(type) {
+	case variant.DateList:
+		r := make([]time.Time, len(v))
+		for i, d := range v {
+			r[i] = d.In(time.UTC)
+		}
+		return r, true
+	case variant.DateTimeList:
+		r := make([]time.Time, len(v))
+		for i, d := range v {
+			r[i] = d.Time
+		}
+		return r, true
+	default:
+		return nil, false
+	}
+}
+
+// wallClock returns the date and time of t in UTC ignoring the time zone.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// floorDiv divides rounding towards negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
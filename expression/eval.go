@@ -0,0 +1,139 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raceresult/go-model/variant"
+	"golang.org/x/text/collate"
+)
+
+// Env holds the values and settings used to evaluate an expression for a single record.
+type Env struct {
+	// Values contains the field values. Field names are looked up case-insensitively.
+	Values variant.VariantMap
+
+	// CaseSensitive defines if string comparisons are case-sensitive.
+	CaseSensitive bool
+
+	// Collator is used for string comparisons, may be nil.
+	Collator *collate.Collator
+}
+
+func (s *Literal) eval(_ *Env) (variant.Variant, error) {
+	return s.Value, nil
+}
+
+func (s *Field) eval(env *Env) (variant.Variant, error) {
+	v, _ := env.Values.GetItem(s.Name)
+	return v, nil
+}
+
+func (s *UnaryExpr) eval(env *Env) (variant.Variant, error) {
+	x, err := s.X.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch s.Op {
+	case OpNeg:
+		return variant.Negate(x), nil
+	case OpNot:
+		return variant.RBool(!variant.ToBool(x)), nil
+	default:
+		return nil, fmt.Errorf("invalid unary operator %v", s.Op)
+	}
+}
+
+func (s *BinaryExpr) eval(env *Env) (variant.Variant, error) {
+	x, err := s.X.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// logical operators do not evaluate the second operand if not necessary
+	switch s.Op {
+	case OpAnd:
+		if !variant.ToBool(x) {
+			return variant.RBool(false), nil
+		}
+	case OpOr:
+		if variant.ToBool(x) {
+			return variant.RBool(true), nil
+		}
+	}
+
+	y, err := s.Y.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return evalBinary(s.Op, x, y, env)
+}
+
+// evalBinary applies a binary operator to two values.
+func evalBinary(op Operator, x, y variant.Variant, env *Env) (variant.Variant, error) {
+	switch op {
+	case OpAdd:
+		return variant.Plus(x, y), nil
+	case OpSub:
+		return variant.Minus(x, y), nil
+	case OpMult:
+		return variant.Mult(x, y), nil
+	case OpDiv:
+		return variant.Div(x, y), nil
+	case OpDivInt:
+		return variant.DivInt(x, y), nil
+	case OpMod:
+		return variant.Mod(x, y), nil
+	case OpExp:
+		return variant.Exp(x, y), nil
+	case OpConcat:
+		return variant.RString(variant.ToString(x) + variant.ToString(y)), nil
+	case OpEqual:
+		return variant.RBool(variant.Equals(x, y, env.CaseSensitive)), nil
+	case OpNotEqual:
+		return variant.RBool(variant.NotEquals(x, y, env.CaseSensitive)), nil
+	case OpLess:
+		return variant.RBool(variant.Less(x, y, env.Collator)), nil
+	case OpLessOrEqual:
+		return variant.RBool(variant.LessOrEquals(x, y, env.Collator)), nil
+	case OpGreater:
+		return variant.RBool(variant.Greater(x, y, env.Collator)), nil
+	case OpGreaterOrEqual:
+		return variant.RBool(variant.GreaterOrEquals(x, y, env.Collator)), nil
+	case OpAnd:
+		return variant.RBool(variant.ToBool(x) && variant.ToBool(y)), nil
+	case OpOr:
+		return variant.RBool(variant.ToBool(x) || variant.ToBool(y)), nil
+	case OpXor:
+		return variant.RBool(variant.ToBool(x) != variant.ToBool(y)), nil
+	default:
+		return nil, fmt.Errorf("invalid binary operator %v", op)
+	}
+}
+
+func (s *Call) eval(env *Env) (variant.Variant, error) {
+	f, err := lookupFunction(s.Name, len(s.Args))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]variant.Variant, len(s.Args))
+	for i, arg := range s.Args {
+		args[i], err = arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f.Call(args), nil
+}
+
+// lookupFunction returns the function with the given name and checks the number of arguments.
+func lookupFunction(name string, argCount int) (*Function, error) {
+	f, ok := functions[strings.ToUpper(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+	if argCount < f.MinArgs || (f.MaxArgs >= 0 && argCount > f.MaxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments for function %s", name)
+	}
+	return f, nil
+}
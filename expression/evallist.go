@@ -0,0 +1,375 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raceresult/go-model/variant"
+	"golang.org/x/text/collate"
+)
+
+// ListEnv holds the columns and settings used to evaluate an expression for many records at once.
+type ListEnv struct {
+	// Columns contains the field values, one list per field. All lists must have the length Len.
+	// Field names are looked up case-insensitively.
+	Columns map[string]variant.RList
+
+	// Len is the number of records.
+	Len int
+
+	// CaseSensitive defines if string comparisons are case-sensitive.
+	CaseSensitive bool
+
+	// Collator is used for string comparisons, may be nil.
+	Collator *collate.Collator
+}
+
+// column returns the list of the field with the given name using case-insensitive search.
+func (s *ListEnv) column(name string) (variant.RList, bool) {
+	if l, ok := s.Columns[name]; ok {
+		return l, true
+	}
+	for k, l := range s.Columns {
+		if strings.EqualFold(k, name) {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// EvalList evaluates the expression for all records at once. columns contains one list per field,
+// n is the number of records.
+func (s *Expression) EvalList(columns map[string]variant.RList, n int) (variant.RList, error) {
+	return s.EvalListEnv(&ListEnv{Columns: columns, Len: n})
+}
+
+// EvalListEnv evaluates the expression for all records of the given environment.
+func (s *Expression) EvalListEnv(env *ListEnv) (variant.RList, error) {
+	for name, l := range env.Columns {
+		if l.Len() != env.Len {
+			return nil, fmt.Errorf("column %s has %d values, expected %d", name, l.Len(), env.Len)
+		}
+	}
+	return s.root.evalList(env)
+}
+
+// FilterList evaluates the expression as filter for all records and returns the result as mask.
+// An empty formula matches all records.
+func (s *Expression) FilterList(columns map[string]variant.RList, n int) (variant.BoolList, error) {
+	if l, ok := s.root.(*Literal); ok && l.Value == nil {
+		return broadcast(variant.RBool(true), n).ToBool(), nil
+	}
+	l, err := s.EvalList(columns, n)
+	if err != nil {
+		return nil, err
+	}
+	return l.ToBool(), nil
+}
+
+func (s *Literal) evalList(env *ListEnv) (variant.RList, error) {
+	return broadcast(s.Value, env.Len), nil
+}
+
+func (s *Field) evalList(env *ListEnv) (variant.RList, error) {
+	l, ok := env.column(s.Name)
+	if !ok {
+		return variant.NewVariantList(env.Len), nil
+	}
+	// the arithmetic functions of the typed lists work in place, so the column must not be passed on directly
+	return variant.CloneList(l), nil
+}
+
+func (s *UnaryExpr) evalList(env *ListEnv) (variant.RList, error) {
+	x, err := s.X.evalList(env)
+	if err != nil {
+		return nil, err
+	}
+	switch s.Op {
+	case OpNeg:
+		return x.Mult(broadcast(variant.RInt(-1), env.Len)), nil
+	case OpNot:
+		b := x.ToBool()
+		r := variant.NewBoolList(len(b))
+		for i := range b {
+			r[i] = !b[i]
+		}
+		return r, nil
+	default:
+		return nil, fmt.Errorf("invalid unary operator %v", s.Op)
+	}
+}
+
+func (s *BinaryExpr) evalList(env *ListEnv) (variant.RList, error) {
+	x, err := s.X.evalList(env)
+	if err != nil {
+		return nil, err
+	}
+	y, err := s.Y.evalList(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.Op {
+	case OpAdd:
+		return x.Plus(y), nil
+	case OpSub:
+		return x.Minus(y), nil
+	case OpMult:
+		return x.Mult(y), nil
+	case OpDiv:
+		return x.Div(y), nil
+	case OpDivInt:
+		return x.DivInt(y), nil
+	case OpMod:
+		return x.Mod(y), nil
+	case OpExp:
+		return x.Exp(y), nil
+	case OpConcat:
+		xs, ys := x.ToString(), y.ToString()
+		r := variant.NewStringList(env.Len)
+		for i := range r {
+			r[i] = xs[i] + ys[i]
+		}
+		return r, nil
+	case OpEqual, OpNotEqual, OpLess, OpLessOrEqual, OpGreater, OpGreaterOrEqual:
+		return compareLists(s.Op, x, y, env), nil
+	case OpAnd, OpOr, OpXor:
+		xb, yb := x.ToBool(), y.ToBool()
+		r := variant.NewBoolList(env.Len)
+		for i := range r {
+			switch s.Op {
+			case OpAnd:
+				r[i] = xb[i] && yb[i]
+			case OpOr:
+				r[i] = xb[i] || yb[i]
+			default:
+				r[i] = xb[i] != yb[i]
+			}
+		}
+		return r, nil
+	default:
+		return nil, fmt.Errorf("invalid binary operator %v", s.Op)
+	}
+}
+
+func (s *Call) evalList(env *ListEnv) (variant.RList, error) {
+	f, err := lookupFunction(s.Name, len(s.Args))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]variant.RList, len(s.Args))
+	for i, arg := range s.Args {
+		args[i], err = arg.evalList(env)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if f.CallList != nil {
+		return f.CallList(args, env.Len), nil
+	}
+	return callEach(f.Call, args, env.Len), nil
+}
+
+// callEach calls the scalar implementation of a function for each record.
+func callEach(call func(args []variant.Variant) variant.Variant, args []variant.RList, n int) variant.VariantList {
+	r := variant.NewVariantList(n)
+	values := make([]variant.Variant, len(args))
+	for i := range r {
+		for j, arg := range args {
+			values[j] = arg.Item(i)
+		}
+		r[i] = call(values)
+	}
+	return r
+}
+
+// sameString returns the value of the list if all items are equal, e.g. because the argument of a
+// function is a literal.
+func sameString(l variant.StringList) (string, bool) {
+	if len(l) == 0 {
+		return "", false
+	}
+	for _, x := range l[1:] {
+		if x != l[0] {
+			return "", false
+		}
+	}
+	return l[0], true
+}
+
+// broadcast creates a list of length n where all items have the value v.
+// The type of the list matches the type of v.
+func broadcast(v variant.Variant, n int) variant.RList {
+	switch variant.GetType(v) {
+	case variant.TypeRBool:
+		r := variant.NewBoolList(n)
+		x := variant.ToBool(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	case variant.TypeRString:
+		r := variant.NewStringList(n)
+		x := variant.ToString(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	case variant.TypeRInt:
+		r := variant.NewIntList(n)
+		x := variant.ToInt(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	case variant.TypeRFloat:
+		r := variant.NewFloat64List(n)
+		x := variant.ToFloat64(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	case variant.TypeRDecimal:
+		r := variant.NewDecimalList(n)
+		x := variant.ToDecimal(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	case variant.TypeRDate:
+		r := variant.NewDateList(n)
+		x := variant.ToDate(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	case variant.TypeRDateTime:
+		r := variant.NewDateTimeList(n)
+		x := variant.ToDateTime(v)
+		for i := range r {
+			r[i] = x
+		}
+		return r
+	default:
+		return variant.NewVariantList(n)
+	}
+}
+
+// compareLists compares two lists item by item. Specialised implementations are used
+// for lists of the same number or string type, all other combinations use the Variant comparison.
+func compareLists(op Operator, x, y variant.RList, env *ListEnv) variant.BoolList {
+	r := variant.NewBoolList(env.Len)
+
+	// int and decimal lists can be compared as decimals
+	if xi, ok := x.(variant.IntList); ok {
+		if _, ok := y.(variant.DecimalList); ok {
+			x = xi.ToDecimal()
+		}
+	}
+	if yi, ok := y.(variant.IntList); ok {
+		if _, ok := x.(variant.DecimalList); ok {
+			y = yi.ToDecimal()
+		}
+	}
+
+	switch xv := x.(type) {
+	case variant.IntList:
+		if yv, ok := y.(variant.IntList); ok {
+			for i := range r {
+				r[i] = compareResult(op, compareOrdered(xv[i] < yv[i], xv[i] > yv[i]))
+			}
+			return r
+		}
+	case variant.DecimalList:
+		if yv, ok := y.(variant.DecimalList); ok {
+			for i := range r {
+				r[i] = compareResult(op, compareOrdered(xv[i] < yv[i], xv[i] > yv[i]))
+			}
+			return r
+		}
+	case variant.Float64List:
+		if yv, ok := y.(variant.Float64List); ok {
+			for i := range r {
+				r[i] = compareResult(op, compareOrdered(xv[i] < yv[i], xv[i] > yv[i]))
+			}
+			return r
+		}
+	case variant.StringList:
+		if yv, ok := y.(variant.StringList); ok {
+			for i := range r {
+				r[i] = compareResult(op, compareStrings(xv[i], yv[i], env))
+			}
+			return r
+		}
+	}
+
+	for i := range r {
+		a, b := x.Item(i), y.Item(i)
+		switch op {
+		case OpEqual:
+			r[i] = variant.Equals(a, b, env.CaseSensitive)
+		case OpNotEqual:
+			r[i] = variant.NotEquals(a, b, env.CaseSensitive)
+		case OpLess:
+			r[i] = variant.Less(a, b, env.Collator)
+		case OpLessOrEqual:
+			r[i] = variant.LessOrEquals(a, b, env.Collator)
+		case OpGreater:
+			r[i] = variant.Greater(a, b, env.Collator)
+		case OpGreaterOrEqual:
+			r[i] = variant.GreaterOrEquals(a, b, env.Collator)
+		}
+	}
+	return r
+}
+
+// comparison is the result of a comparison of two values.
+type comparison struct {
+	less    bool
+	greater bool
+	equal   bool
+}
+
+func compareOrdered(less, greater bool) comparison {
+	return comparison{less: less, greater: greater, equal: !less && !greater}
+}
+
+// compareStrings compares two strings the same way as the Variant comparison of two strings.
+func compareStrings(a, b string, env *ListEnv) comparison {
+	c := comparison{}
+	if env.CaseSensitive {
+		c.equal = a == b
+	} else {
+		c.equal = strings.EqualFold(a, b)
+	}
+	if env.Collator != nil {
+		if r := env.Collator.CompareString(a, b); r != 0 {
+			c.less = r < 0
+			c.greater = r > 0
+			return c
+		}
+	}
+	c.less = a < b
+	c.greater = a > b
+	return c
+}
+
+func compareResult(op Operator, c comparison) bool {
+	switch op {
+	case OpEqual:
+		return c.equal
+	case OpNotEqual:
+		return !c.equal
+	case OpLess:
+		return c.less
+	case OpLessOrEqual:
+		return !c.greater
+	case OpGreater:
+		return c.greater
+	case OpGreaterOrEqual:
+		return !c.less
+	default:
+		return false
+	}
+}
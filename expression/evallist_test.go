@@ -0,0 +1,110 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/raceresult/go-model/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testColumns = map[string]variant.RList{
+	"Bib":         variant.IntList{1, 123, 500},
+	"LastName":    variant.StringList{"Miller", "smith", "Zahn"},
+	"Contest":     variant.IntList{1, 2, 2},
+	"Time":        variant.DecimalList{decimal.FromFloat(3600.5), 0, decimal.FromInt(-12)},
+	"DateOfBirth": variant.DateList{date.New(1980, 5, 1), date.New(1995, 1, 1), date.New(2001, 12, 31)},
+	"Club":        variant.VariantList{variant.RString("LG"), nil, variant.RInt(3)},
+}
+
+func rowValues(i int) variant.VariantMap {
+	m := variant.VariantMap{}
+	for k, l := range testColumns {
+		if v := l.Item(i); v != nil {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+func TestExpression_EvalList(t *testing.T) {
+	for _, formula := range []string{
+		"[Bib] + 1",
+		"[Bib] * [Contest] - 2",
+		"-[Time]",
+		"[Time] * 2 + [Bib]",
+		"[Bib] / [Contest]",
+		"[Bib] \\ 7 + [Bib] mod 7",
+		"[LastName] & \"-\" & [Bib]",
+		"[LastName] = \"SMITH\"",
+		"[LastName] < \"n\"",
+		"[Bib] >= 123 And [Contest] = 2",
+		"[Bib] = 1 Or Not [Contest] <> 1",
+		"[Time] > [Bib]",
+		"[DateOfBirth] < #1990-01-01# Xor [Bib] = 500",
+		"[DateOfBirth] + 1",
+		"[Club] = \"lg\"",
+		"[Club] & [Unknown]",
+		"Abs([Time])",
+		"",
+	} {
+		t.Run(formula, func(t *testing.T) {
+			e := MustParse(formula)
+			got, err := e.EvalList(testColumns, 3)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, 3, got.Len())
+			for i := 0; i < 3; i++ {
+				want, err := e.Eval(rowValues(i))
+				assert.NoError(t, err)
+				assert.True(t, variant.Equals(want, got.Item(i), true), "row %d: want %v, got %v", i, want, got.Item(i))
+			}
+		})
+	}
+}
+
+func TestExpression_EvalListTypes(t *testing.T) {
+	got, err := MustParse("[Bib] * 2 + 1").EvalList(testColumns, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.IntList{3, 247, 1001}, got)
+
+	got, err = MustParse("[Time] + 1").EvalList(testColumns, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.DecimalList{decimal.FromFloat(3601.5), decimal.FromInt(1), decimal.FromInt(-11)}, got)
+
+	// columns must not be modified
+	assert.Equal(t, variant.IntList{1, 123, 500}, testColumns["Bib"])
+}
+
+func TestExpression_FilterList(t *testing.T) {
+	mask, err := MustParse("[contest] = 2 And [Bib] < 200").FilterList(testColumns, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.BoolList{false, true, false}, mask)
+
+	mask, err = MustParse("").FilterList(testColumns, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, variant.BoolList{true, true, true}, mask)
+
+	_, err = MustParse("[Bib]").FilterList(testColumns, 2)
+	assert.Error(t, err)
+}
+
+func BenchmarkExpression_FilterList(b *testing.B) {
+	const n = 50000
+	bib := variant.NewIntList(n)
+	contest := variant.NewIntList(n)
+	for i := range bib {
+		bib[i] = i + 1
+		contest[i] = i % 3
+	}
+	columns := map[string]variant.RList{"Bib": bib, "Contest": contest}
+	e := MustParse("[Contest] = 1 And [Bib] Mod 2 = 0")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = e.FilterList(columns, n)
+	}
+}
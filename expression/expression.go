@@ -0,0 +1,89 @@
+// Package expression parses and evaluates formulas as used in Result.Formula, Ranking.Filter,
+// Exporter.Filter and similar settings.
+//
+// The formula language supports
+//   - field references in square brackets ([Bib], [Contest.Name]) or as plain identifiers,
+//   - number literals (1, 2.5), string literals ("abc", quotes escaped by doubling them),
+//     date literals (#2024-05-01#, #2024-05-01 10:30:00#) and True/False,
+//   - the operators ^, unary -, * /, \ (integer division), Mod, + -, & (concatenation),
+//     = <> < <= > >=, Not, And, Xor, Or (in descending order of precedence),
+//   - function calls with arguments separated by semicolons or commas, e.g. Left([LastName];3).
+//
+// Arithmetic and comparisons use the semantics of the variant package. Built-in functions follow
+// their VB counterparts: Left, Right, Mid, Len, InStr, Trim, LTrim, RTrim, UCase, LCase, Replace,
+// Format, Year, Month, Day, Weekday, Hour, Minute, Second, DateAdd, DateDiff, IIf, Switch, Choose,
+// Abs, Val and IsEmpty. Further functions can be added with RegisterFunction.
+package expression
+
+import (
+	"github.com/raceresult/go-model/variant"
+)
+
+// Expression is a parsed formula.
+type Expression struct {
+	root Node
+}
+
+// Parse parses a formula.
+func Parse(formula string) (*Expression, error) {
+	root, err := parseFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{root: root}, nil
+}
+
+// MustParse parses a formula and panics on error.
+func MustParse(formula string) *Expression {
+	e, err := Parse(formula)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Root returns the root node of the syntax tree.
+func (s *Expression) Root() Node {
+	return s.root
+}
+
+// String returns the formula with explicit parentheses.
+func (s *Expression) String() string {
+	return s.root.String()
+}
+
+// Fields returns the names of all fields referenced by the expression, without duplicates.
+func (s *Expression) Fields() []string {
+	var fields []string
+	seen := make(map[string]bool)
+	Walk(s.root, func(n Node) {
+		if f, ok := n.(*Field); ok && !seen[f.Name] {
+			seen[f.Name] = true
+			fields = append(fields, f.Name)
+		}
+	})
+	return fields
+}
+
+// Eval evaluates the expression with the given values.
+func (s *Expression) Eval(values variant.VariantMap) (variant.Variant, error) {
+	return s.EvalEnv(&Env{Values: values})
+}
+
+// EvalEnv evaluates the expression in the given environment.
+func (s *Expression) EvalEnv(env *Env) (variant.Variant, error) {
+	return s.root.eval(env)
+}
+
+// Match evaluates the expression as filter and returns the result as bool.
+// An empty formula matches all records.
+func (s *Expression) Match(values variant.VariantMap) (bool, error) {
+	if l, ok := s.root.(*Literal); ok && l.Value == nil {
+		return true, nil
+	}
+	v, err := s.Eval(values)
+	if err != nil {
+		return false, err
+	}
+	return variant.ToBool(v), nil
+}
@@ -0,0 +1,110 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/raceresult/go-model/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+var testValues = variant.VariantMap{
+	"Bib":         variant.RInt(123),
+	"LastName":    variant.RString("Miller"),
+	"Contest":     variant.RInt(2),
+	"Time":        variant.RDecimal(decimal.FromFloat(3600.5)),
+	"DateOfBirth": variant.RDate(date.New(1980, 5, 1)),
+	"Club":        variant.RString(""),
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, formula := range []string{
+		"1 +",
+		"(1 + 2",
+		"\"abc",
+		"[Bib",
+		"#tomorrow#",
+		"Left([LastName] 3)",
+		"1 2",
+		"[]",
+		"1 ? 2",
+	} {
+		_, err := Parse(formula)
+		assert.Error(t, err, formula)
+	}
+}
+
+func TestExpression_Eval(t *testing.T) {
+	tests := []struct {
+		formula string
+		want    variant.Variant
+	}{
+		{"1 + 2 * 3", variant.RInt(7)},
+		{"(1 + 2) * 3", variant.RInt(9)},
+		{"-2 ^ 2", variant.RInt(-4)},
+		{"2^-1", variant.RFloat(0.5)},
+		{"2 ^ -(1 + 1) * 4", variant.RFloat(1)},
+		{"7 \\ 2", variant.RInt(3)},
+		{"7 mod 4 + 1", variant.RInt(4)},
+		{"[Bib] + 1", variant.RInt(124)},
+		{"bib * 2", variant.RInt(246)},
+		{"[Time] + 0.5", variant.RDecimal(decimal.FromInt(3601))},
+		{"\"Mr. \" & [LastName]", variant.RString("Mr. Miller")},
+		{"\"say \"\"hi\"\"\"", variant.RString("say \"hi\"")},
+		{"[LastName] = \"MILLER\"", variant.RBool(true)},
+		{"[Contest] = \"2\"", variant.RBool(true)},
+		{"[Contest] <> 2", variant.RBool(false)},
+		{"[Bib] > 100 And [Bib] <= 123", variant.RBool(true)},
+		{"[Bib] < 100 Or Not [Contest] = 1", variant.RBool(true)},
+		{"True Xor True", variant.RBool(false)},
+		{"[DateOfBirth] < #1990-01-01#", variant.RBool(true)},
+		{"[DateOfBirth] + 1", variant.RDate(date.New(1980, 5, 2))},
+		{"[Unknown] + 1", nil},
+		{"1 / 0", nil},
+		{"Abs(-3)", variant.RInt(3)},
+		{"val(\"12abc\")", variant.RInt(12)},
+		{"IsEmpty([Club]) And IsEmpty([Unknown])", variant.RBool(true)},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formula, func(t *testing.T) {
+			e, err := Parse(tt.formula)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := e.Eval(testValues)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpression_EvalErrors(t *testing.T) {
+	_, err := MustParse("NoSuchFunction(1)").Eval(testValues)
+	assert.Error(t, err)
+
+	_, err = MustParse("Abs(1; 2)").Eval(testValues)
+	assert.Error(t, err)
+}
+
+func TestExpression_Match(t *testing.T) {
+	ok, err := MustParse("").Match(testValues)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = MustParse("[Contest]=1").Match(testValues)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestExpression_Fields(t *testing.T) {
+	e := MustParse("[Bib] > 10 And Left([LastName]; 1) = \"M\" Or [bib] = [Contest.Name]")
+	assert.Equal(t, []string{"Bib", "LastName", "bib", "Contest.Name"}, e.Fields())
+}
+
+func TestExpression_String(t *testing.T) {
+	e := MustParse("1+2*[Bib] & \"x\"")
+	assert.Equal(t, "((1 + (2 * [Bib])) & \"x\")", e.String())
+	assert.Equal(t, e.String(), MustParse(e.String()).String())
+}
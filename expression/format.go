@@ -0,0 +1,333 @@
+package expression
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/variant"
+)
+
+// namedFormats contains the predefined VB number formats.
+var namedFormats = map[string]string{
+	"fixed":    "0.00",
+	"standard": "#,##0.00",
+	"percent":  "0.00%",
+}
+
+func init() {
+	RegisterFunction("Format", Function{MinArgs: 1, MaxArgs: 2,
+		Call: func(args []variant.Variant) variant.Variant {
+			f := ""
+			if len(args) == 2 {
+				f = variant.ToString(args[1])
+			}
+			return variant.RString(vbFormat(args[0], f))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			if len(args) == 1 {
+				return formatList(args[0], "")
+			}
+			formats := args[1].ToString()
+			if f, ok := sameString(formats); ok {
+				return formatList(args[0], f)
+			}
+			r := variant.NewStringList(n)
+			for i := range r {
+				r[i] = vbFormat(args[0].Item(i), formats[i])
+			}
+			return r
+		},
+	})
+}
+
+// formatList formats all values of the list with the same format like vbFormat. Dates, datetimes,
+// ints and decimals are formatted without conversion to Variant.
+func formatList(l variant.RList, f string) variant.StringList {
+	if x, ok := namedFormats[strings.ToLower(f)]; ok {
+		f = x
+	}
+	switch v := l.(type) {
+	case variant.DateList:
+		if f != "" {
+			r := variant.NewStringList(len(v))
+			for i, d := range v {
+				r[i] = formatVBDate(d.In(time.UTC), f)
+			}
+			return r
+		}
+	case variant.DateTimeList:
+		if f != "" {
+			r := variant.NewStringList(len(v))
+			for i, d := range v {
+				r[i] = formatVBDate(d.Time, f)
+			}
+			return r
+		}
+	case variant.IntList, variant.DecimalList:
+		if f == "" {
+			return l.ToString()
+		}
+		if strings.ContainsAny(f, "0#") {
+			r := l.ToString()
+			for i, num := range r {
+				r[i] = formatVBNumber(strings.HasPrefix(num, "-"), strings.TrimPrefix(num, "-"), f)
+			}
+			return r
+		}
+	}
+	r := variant.NewStringList(l.Len())
+	for i := range r {
+		r[i] = vbFormat(l.Item(i), f)
+	}
+	return r
+}
+
+// vbFormat formats a value like the VB Format function. Numbers are formatted with the placeholders
+// 0 and #, the decimal point is always ".". Dates and numbers formatted with a date format such as
+// "dd.mm.yyyy hh:nn:ss" use English month and weekday names. Other values are converted to string.
+func vbFormat(v variant.Variant, f string) string {
+	if v == nil {
+		return ""
+	}
+	if x, ok := namedFormats[strings.ToLower(f)]; ok {
+		f = x
+	}
+	if f == "" {
+		return variant.ToString(v)
+	}
+	if variant.IsDate(v) || variant.IsDateTime(v) {
+		return formatVBDate(variant.ToDateTime(v).Time, f)
+	}
+
+	if variant.IsString(v) {
+		n, err := variant.ParseNumber(variant.ToString(v))
+		if err != nil {
+			return variant.ToString(v)
+		}
+		v = n
+	}
+	var num string
+	switch variant.GetType(v) {
+	case variant.TypeRInt, variant.TypeRDecimal:
+		num = variant.ToString(v)
+	case variant.TypeRFloat:
+		num = strconv.FormatFloat(variant.ToFloat64(v), 'f', -1, 64)
+	default:
+		return variant.ToString(v)
+	}
+	if !strings.ContainsAny(f, "0#") {
+		// date format applied to a date serial number
+		return formatVBDate(variant.ToDateTime(v).Time, f)
+	}
+	neg := strings.HasPrefix(num, "-")
+	return formatVBNumber(neg, strings.TrimPrefix(num, "-"), f)
+}
+
+// formatVBNumber formats the absolute value num given as decimal string with a VB number format.
+func formatVBNumber(neg bool, num string, f string) string {
+	start := strings.IndexAny(f, "0#.")
+	end := strings.LastIndexAny(f, "0#.")
+	prefix, pattern, suffix := f[:start], f[start:end+1], f[end+1:]
+
+	intPart, fracPart := num, ""
+	if p := strings.IndexByte(num, '.'); p >= 0 {
+		intPart, fracPart = num[:p], num[p+1:]
+	}
+	if strings.Contains(f, "%") {
+		fracPart += "00"
+		intPart, fracPart = intPart+fracPart[:2], fracPart[2:]
+	}
+
+	intPat, fracPat := pattern, ""
+	hasPoint := false
+	if p := strings.IndexByte(pattern, '.'); p >= 0 {
+		intPat, fracPat, hasPoint = pattern[:p], pattern[p+1:], true
+	}
+	minInt := strings.Count(intPat, "0")
+	minFrac := strings.Count(fracPat, "0")
+	decimals := minFrac + strings.Count(fracPat, "#")
+
+	intPart, fracPart = roundDigits(intPart, fracPart, decimals)
+	intPart = strings.TrimLeft(intPart, "0")
+	if len(intPart) < minInt {
+		intPart = strings.Repeat("0", minInt-len(intPart)) + intPart
+	}
+	for len(fracPart) > minFrac && fracPart[len(fracPart)-1] == '0' {
+		fracPart = fracPart[:len(fracPart)-1]
+	}
+	isZero := strings.Trim(intPart+fracPart, "0") == ""
+	if strings.Contains(intPat, ",") {
+		intPart = groupThousands(intPart)
+	}
+
+	var sb strings.Builder
+	if neg && !isZero {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(prefix)
+	sb.WriteString(intPart)
+	if hasPoint {
+		sb.WriteByte('.')
+		sb.WriteString(fracPart)
+	}
+	sb.WriteString(suffix)
+	return sb.String()
+}
+
+// roundDigits rounds the number given by its integer and fractional digits to the given number of
+// decimals, halves are rounded up.
+func roundDigits(intPart, fracPart string, decimals int) (string, string) {
+	if len(fracPart) <= decimals {
+		return intPart, fracPart + strings.Repeat("0", decimals-len(fracPart))
+	}
+	roundUp := fracPart[decimals] >= '5'
+	digits := []byte(intPart + fracPart[:decimals])
+	if roundUp {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	n := len(digits) - decimals
+	return string(digits[:n]), string(digits[n:])
+}
+
+// groupThousands inserts commas as thousands separators.
+func groupThousands(s string) string {
+	if len(s) <= 3 {
+		return s
+	}
+	var sb strings.Builder
+	first := len(s) % 3
+	if first == 0 {
+		first = 3
+	}
+	sb.WriteString(s[:first])
+	for i := first; i < len(s); i += 3 {
+		sb.WriteByte(',')
+		sb.WriteString(s[i : i+3])
+	}
+	return sb.String()
+}
+
+// formatVBDate formats a time with a VB date format, e.g. "dd.mm.yyyy hh:nn:ss". Text in double
+// quotes and characters after a backslash are copied. m and mm denote minutes after h or before s.
+func formatVBDate(t time.Time, f string) string {
+	var sb strings.Builder
+	lastHour := false
+	for i := 0; i < len(f); {
+		c := f[i]
+		switch c {
+		case '"':
+			p := strings.IndexByte(f[i+1:], '"')
+			if p < 0 {
+				p = len(f) - i - 1
+			}
+			sb.WriteString(f[i+1 : i+1+p])
+			i += p + 2
+			continue
+		case '\\':
+			if i+1 < len(f) {
+				sb.WriteByte(f[i+1])
+			}
+			i += 2
+			continue
+		}
+
+		lc := c | 0x20 // lower case for letters
+		if !strings.ContainsRune("dmyhnsqw", rune(lc)) {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		n := 1
+		for i+n < len(f) && f[i+n]|0x20 == lc {
+			n++
+		}
+		if lc == 'm' && n <= 2 && (lastHour || nextDateToken(f[i+n:]) == 's') {
+			lc = 'n'
+		}
+		switch lc {
+		case 'd':
+			switch n {
+			case 1:
+				sb.WriteString(strconv.Itoa(t.Day()))
+			case 2:
+				write2(&sb, t.Day())
+			case 3:
+				sb.WriteString(t.Weekday().String()[:3])
+			default:
+				sb.WriteString(t.Weekday().String())
+			}
+		case 'm':
+			switch n {
+			case 1:
+				sb.WriteString(strconv.Itoa(int(t.Month())))
+			case 2:
+				write2(&sb, int(t.Month()))
+			case 3:
+				sb.WriteString(t.Month().String()[:3])
+			default:
+				sb.WriteString(t.Month().String())
+			}
+		case 'y':
+			switch n {
+			case 1:
+				sb.WriteString(strconv.Itoa(t.YearDay()))
+			case 2:
+				write2(&sb, t.Year()%100)
+			default:
+				sb.WriteString(strconv.Itoa(t.Year()))
+			}
+		case 'h':
+			writeNumber(&sb, t.Hour(), n)
+		case 'n':
+			writeNumber(&sb, t.Minute(), n)
+		case 's':
+			writeNumber(&sb, t.Second(), n)
+		case 'q':
+			sb.WriteString(strconv.Itoa((int(t.Month())-1)/3 + 1))
+		case 'w':
+			if n == 1 {
+				sb.WriteString(strconv.Itoa(int(t.Weekday()) + 1))
+			} else {
+				jan1 := time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+				sb.WriteString(strconv.Itoa((t.YearDay()-1+int(jan1.Weekday()))/7 + 1))
+			}
+		}
+		lastHour = lc == 'h'
+		i += n
+	}
+	return sb.String()
+}
+
+// nextDateToken returns the next date placeholder letter in lower case, or 0 if there is none.
+func nextDateToken(f string) byte {
+	for i := 0; i < len(f); i++ {
+		if lc := f[i] | 0x20; strings.IndexByte("dmyhnsqw", lc) >= 0 {
+			return lc
+		}
+	}
+	return 0
+}
+
+func writeNumber(sb *strings.Builder, x int, n int) {
+	if n == 1 {
+		sb.WriteString(strconv.Itoa(x))
+		return
+	}
+	write2(sb, x)
+}
+
+func write2(sb *strings.Builder, x int) {
+	if x < 10 {
+		sb.WriteByte('0')
+	}
+	sb.WriteString(strconv.Itoa(x))
+}
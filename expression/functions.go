@@ -0,0 +1,46 @@
+package expression
+
+import (
+	"strings"
+
+	"github.com/raceresult/go-model/variant"
+)
+
+// Function defines a function that can be called from a formula.
+type Function struct {
+	// MinArgs is the minimum number of arguments.
+	MinArgs int
+
+	// MaxArgs is the maximum number of arguments, -1 if unlimited.
+	MaxArgs int
+
+	// Call calculates the result for a single record.
+	Call func(args []variant.Variant) variant.Variant
+
+	// CallList calculates the results for n records at once, args contains one list of length n per
+	// argument. The argument lists must not be modified. If CallList is nil, Call is used for each record.
+	CallList func(args []variant.RList, n int) variant.RList
+}
+
+// functions contains all registered functions with upper case names.
+var functions = map[string]*Function{}
+
+// RegisterFunction registers a function so that it can be used in formulas.
+// Function names are case-insensitive. An existing function with the same name is replaced.
+// RegisterFunction is not safe for concurrent use with the evaluation of expressions
+// and should therefore be called during initialization.
+func RegisterFunction(name string, f Function) {
+	functions[strings.ToUpper(name)] = &f
+}
+
+func init() {
+	RegisterFunction("Abs", Function{MinArgs: 1, MaxArgs: 1, Call: func(args []variant.Variant) variant.Variant {
+		return variant.Abs(args[0])
+	}})
+	RegisterFunction("Val", Function{MinArgs: 1, MaxArgs: 1, Call: func(args []variant.Variant) variant.Variant {
+		return variant.Val(args[0])
+	}})
+	RegisterFunction("IsEmpty", Function{MinArgs: 1, MaxArgs: 1, Call: func(args []variant.Variant) variant.Variant {
+		return variant.RBool(variant.IsEmpty(args[0]) || variant.Equals(args[0], variant.RString(""), true))
+	}})
+}
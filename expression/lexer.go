@@ -0,0 +1,150 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokNumber
+	tokString
+	tokDate
+	tokField
+	tokIdent
+	tokOperator
+	tokLParen
+	tokRParen
+	tokSeparator
+)
+
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+// SyntaxError is returned by Parse if the formula is not valid.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (s *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", s.Pos, s.Msg)
+}
+
+// lex splits the formula into tokens.
+func lex(s string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{typ: tokLParen, val: "(", pos: i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{typ: tokRParen, val: ")", pos: i})
+			i++
+
+		case c == ';' || c == ',':
+			tokens = append(tokens, token{typ: tokSeparator, val: string(c), pos: i})
+			i++
+
+		case c == '"':
+			// strings are enclosed in double quotes, a double quote inside the string is escaped by another one
+			sb := strings.Builder{}
+			j := i + 1
+			for {
+				if j >= len(s) {
+					return nil, &SyntaxError{Pos: i, Msg: "unterminated string"}
+				}
+				if s[j] == '"' {
+					if j+1 < len(s) && s[j+1] == '"' {
+						sb.WriteByte('"')
+						j += 2
+						continue
+					}
+					break
+				}
+				sb.WriteByte(s[j])
+				j++
+			}
+			tokens = append(tokens, token{typ: tokString, val: sb.String(), pos: i})
+			i = j + 1
+
+		case c == '[':
+			j := strings.IndexByte(s[i+1:], ']')
+			if j < 0 {
+				return nil, &SyntaxError{Pos: i, Msg: "unterminated field reference"}
+			}
+			name := strings.TrimSpace(s[i+1 : i+1+j])
+			if name == "" {
+				return nil, &SyntaxError{Pos: i, Msg: "empty field reference"}
+			}
+			tokens = append(tokens, token{typ: tokField, val: name, pos: i})
+			i += j + 2
+
+		case c == '#':
+			j := strings.IndexByte(s[i+1:], '#')
+			if j < 0 {
+				return nil, &SyntaxError{Pos: i, Msg: "unterminated date literal"}
+			}
+			tokens = append(tokens, token{typ: tokDate, val: s[i : i+j+2], pos: i})
+			i += j + 2
+
+		case isDigit(c) || (c == '.' && i+1 < len(s) && isDigit(s[i+1])):
+			j := i
+			for j < len(s) && (isDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{typ: tokNumber, val: s[i:j], pos: i})
+			i = j
+
+		case isIdentStart(c):
+			j := i
+			for j < len(s) && isIdentPart(s[j]) {
+				j++
+			}
+			tokens = append(tokens, token{typ: tokIdent, val: s[i:j], pos: i})
+			i = j
+
+		case c == '<' || c == '>':
+			if i+1 < len(s) && (s[i+1] == '=' || (c == '<' && s[i+1] == '>')) {
+				tokens = append(tokens, token{typ: tokOperator, val: s[i : i+2], pos: i})
+				i += 2
+			} else {
+				tokens = append(tokens, token{typ: tokOperator, val: string(c), pos: i})
+				i++
+			}
+
+		case strings.IndexByte("+-*/\\^&=", c) >= 0:
+			tokens = append(tokens, token{typ: tokOperator, val: string(c), pos: i})
+			i++
+
+		default:
+			return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	tokens = append(tokens, token{typ: tokEOF, pos: len(s)})
+	return tokens, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c) || c == '.'
+}
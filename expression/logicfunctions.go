@@ -0,0 +1,183 @@
+package expression
+
+import (
+	"github.com/raceresult/go-model/variant"
+)
+
+// Logical functions with VB semantics. In contrast to the And and Or operators, all arguments are evaluated.
+
+func init() {
+	RegisterFunction("IIf", Function{MinArgs: 3, MaxArgs: 3,
+		Call: func(args []variant.Variant) variant.Variant {
+			if variant.ToBool(args[0]) {
+				return args[1]
+			}
+			return args[2]
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			return iifList(args[0].ToBool(), args[1], args[2])
+		},
+	})
+	RegisterFunction("Switch", Function{MinArgs: 2, MaxArgs: -1,
+		Call: func(args []variant.Variant) variant.Variant {
+			for i := 0; i+1 < len(args); i += 2 {
+				if variant.ToBool(args[i]) {
+					return args[i+1]
+				}
+			}
+			return nil
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			pick := make([]int, n)
+			for i := range pick {
+				pick[i] = -1
+			}
+			values := make([]variant.RList, 0, len(args)/2)
+			for j := 0; j+1 < len(args); j += 2 {
+				for i, c := range args[j].ToBool() {
+					if c && pick[i] < 0 {
+						pick[i] = len(values)
+					}
+				}
+				values = append(values, args[j+1])
+			}
+			return pickList(pick, values)
+		},
+	})
+	RegisterFunction("Choose", Function{MinArgs: 2, MaxArgs: -1,
+		Call: func(args []variant.Variant) variant.Variant {
+			i := variant.ToInt(args[0])
+			if i < 1 || i >= len(args) {
+				return nil
+			}
+			return args[i]
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			pick := make([]int, n)
+			for i, x := range args[0].ToInt() {
+				pick[i] = -1
+				if x >= 1 && x < len(args) {
+					pick[i] = x - 1
+				}
+			}
+			return pickList(pick, args[1:])
+		},
+	})
+}
+
+// pickList returns the item of lists[pick[i]] for each record i, or an empty value if pick[i] is
+// negative. The result is a typed list if all lists have the same type and no value is empty.
+func pickList(pick []int, lists []variant.RList) variant.RList {
+	typed := len(lists) > 0
+	for _, p := range pick {
+		if p < 0 {
+			typed = false
+			break
+		}
+	}
+	if typed {
+		switch lists[0].(type) {
+		case variant.IntList:
+			src := make([]variant.IntList, len(lists))
+			for j, l := range lists {
+				if src[j], typed = l.(variant.IntList); !typed {
+					break
+				}
+			}
+			if typed {
+				r := variant.NewIntList(len(pick))
+				for i, p := range pick {
+					r[i] = src[p][i]
+				}
+				return r
+			}
+		case variant.DecimalList:
+			src := make([]variant.DecimalList, len(lists))
+			for j, l := range lists {
+				if src[j], typed = l.(variant.DecimalList); !typed {
+					break
+				}
+			}
+			if typed {
+				r := variant.NewDecimalList(len(pick))
+				for i, p := range pick {
+					r[i] = src[p][i]
+				}
+				return r
+			}
+		case variant.StringList:
+			src := make([]variant.StringList, len(lists))
+			for j, l := range lists {
+				if src[j], typed = l.(variant.StringList); !typed {
+					break
+				}
+			}
+			if typed {
+				r := variant.NewStringList(len(pick))
+				for i, p := range pick {
+					r[i] = src[p][i]
+				}
+				return r
+			}
+		}
+	}
+	r := variant.NewVariantList(len(pick))
+	for i, p := range pick {
+		if p >= 0 {
+			r[i] = lists[p].Item(i)
+		}
+	}
+	return r
+}
+
+// iifList picks the values from x where cond is true and from y otherwise.
+// The result is a typed list if x and y have the same type.
+func iifList(cond variant.BoolList, x, y variant.RList) variant.RList {
+	switch xl := x.(type) {
+	case variant.IntList:
+		if yl, ok := y.(variant.IntList); ok {
+			r := variant.NewIntList(len(cond))
+			for i, c := range cond {
+				if c {
+					r[i] = xl[i]
+				} else {
+					r[i] = yl[i]
+				}
+			}
+			return r
+		}
+	case variant.DecimalList:
+		if yl, ok := y.(variant.DecimalList); ok {
+			r := variant.NewDecimalList(len(cond))
+			for i, c := range cond {
+				if c {
+					r[i] = xl[i]
+				} else {
+					r[i] = yl[i]
+				}
+			}
+			return r
+		}
+	case variant.StringList:
+		if yl, ok := y.(variant.StringList); ok {
+			r := variant.NewStringList(len(cond))
+			for i, c := range cond {
+				if c {
+					r[i] = xl[i]
+				} else {
+					r[i] = yl[i]
+				}
+			}
+			return r
+		}
+	}
+	r := variant.NewVariantList(len(cond))
+	for i, c := range cond {
+		if c {
+			r[i] = x.Item(i)
+		} else {
+			r[i] = y.Item(i)
+		}
+	}
+	return r
+}
@@ -0,0 +1,373 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raceresult/go-model/variant"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parseFormula parses the formula into a syntax tree.
+func parseFormula(s string) (Node, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{tokens: tokens}
+	if p.peek().typ == tokEOF {
+		return &Literal{}, nil
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword checks if the next token is the given keyword and consumes it if so.
+func (p *parser) keyword(kw string) bool {
+	t := p.peek()
+	if t.typ == tokIdent && strings.EqualFold(t.val, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// operator checks if the next token is one of the given operators and consumes it if so.
+func (p *parser) operator(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.typ != tokOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.val == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) parseOr() (Node, error) {
+	x, err := p.parseXor()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		y, err := p.parseXor()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpOr, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseXor() (Node, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("xor") {
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpXor, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		y, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpAnd, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseNot() (Node, error) {
+	if p.keyword("not") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Op: OpNot, X: x}, nil
+	}
+	return p.parseComparison()
+}
+
+var comparisonOperators = map[string]Operator{
+	"=":  OpEqual,
+	"<>": OpNotEqual,
+	"<":  OpLess,
+	"<=": OpLessOrEqual,
+	">":  OpGreater,
+	">=": OpGreaterOrEqual,
+}
+
+func (p *parser) parseComparison() (Node, error) {
+	x, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator("=", "<>", "<", "<=", ">", ">=")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: comparisonOperators[op], X: x, Y: y}
+	}
+}
+
+func (p *parser) parseConcat() (Node, error) {
+	x, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.operator("&"); !ok {
+			return x, nil
+		}
+		y, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpConcat, X: x, Y: y}
+	}
+}
+
+func (p *parser) parseAdditive() (Node, error) {
+	x, err := p.parseMod()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator("+", "-")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseMod()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			x = &BinaryExpr{Op: OpAdd, X: x, Y: y}
+		} else {
+			x = &BinaryExpr{Op: OpSub, X: x, Y: y}
+		}
+	}
+}
+
+func (p *parser) parseMod() (Node, error) {
+	x, err := p.parseDivInt()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("mod") {
+		y, err := p.parseDivInt()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpMod, X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) parseDivInt() (Node, error) {
+	x, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.operator("\\"); !ok {
+			return x, nil
+		}
+		y, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpDivInt, X: x, Y: y}
+	}
+}
+
+func (p *parser) parseMultiplicative() (Node, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.operator("*", "/")
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "*" {
+			x = &BinaryExpr{Op: OpMult, X: x, Y: y}
+		} else {
+			x = &BinaryExpr{Op: OpDiv, X: x, Y: y}
+		}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if op, ok := p.operator("-", "+"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return x, nil
+		}
+		return &UnaryExpr{Op: OpNeg, X: x}, nil
+	}
+	return p.parseExp()
+}
+
+func (p *parser) parseExp() (Node, error) {
+	x, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.operator("^"); !ok {
+			return x, nil
+		}
+		y, err := p.parseExpOperand()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{Op: OpExp, X: x, Y: y}
+	}
+}
+
+// parseExpOperand parses the right-hand side of "^", which may have a sign as in VB: 2^-1 is 0.5.
+func (p *parser) parseExpOperand() (Node, error) {
+	if op, ok := p.operator("-", "+"); ok {
+		x, err := p.parseExpOperand()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return x, nil
+		}
+		return &UnaryExpr{Op: OpNeg, X: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.typ {
+	case tokNumber:
+		v, err := variant.ParseNumber(t.val)
+		if err != nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid number %q", t.val)}
+		}
+		return &Literal{Value: v}, nil
+
+	case tokString:
+		return &Literal{Value: variant.RString(t.val)}, nil
+
+	case tokDate:
+		v := variant.ToVariant2(t.val, true)
+		if !variant.IsDate(v) && !variant.IsDateTime(v) {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid date %q", t.val)}
+		}
+		return &Literal{Value: v}, nil
+
+	case tokField:
+		return &Field{Name: t.val}, nil
+
+	case tokIdent:
+		if p.peek().typ == tokLParen {
+			p.next()
+			return p.parseCall(t.val)
+		}
+		switch strings.ToLower(t.val) {
+		case "true":
+			return &Literal{Value: variant.RBool(true)}, nil
+		case "false":
+			return &Literal{Value: variant.RBool(false)}, nil
+		case "and", "or", "xor", "not", "mod":
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+		}
+		return &Field{Name: t.val}, nil
+
+	case tokLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t2 := p.next(); t2.typ != tokRParen {
+			return nil, &SyntaxError{Pos: t2.pos, Msg: "missing closing parenthesis"}
+		}
+		return x, nil
+
+	case tokEOF:
+		return nil, &SyntaxError{Pos: t.pos, Msg: "unexpected end of formula"}
+
+	default:
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+	}
+}
+
+func (p *parser) parseCall(name string) (Node, error) {
+	call := &Call{Name: name}
+	if p.peek().typ == tokRParen {
+		p.next()
+		return call, nil
+	}
+	for {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+
+		t := p.next()
+		switch t.typ {
+		case tokSeparator:
+			continue
+		case tokRParen:
+			return call, nil
+		default:
+			return nil, &SyntaxError{Pos: t.pos, Msg: "missing closing parenthesis"}
+		}
+	}
+}
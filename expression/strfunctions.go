@@ -0,0 +1,210 @@
+package expression
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/raceresult/go-model/variant"
+)
+
+// String functions with VB semantics. Positions and lengths count characters, not bytes, and start
+// at 1. Empty values are treated as empty strings. Invalid positions or lengths, which raise a
+// runtime error in VB, result in an empty string.
+
+func init() {
+	RegisterFunction("Left", Function{MinArgs: 2, MaxArgs: 2,
+		Call: func(args []variant.Variant) variant.Variant {
+			return variant.RString(vbLeft(variant.ToString(args[0]), variant.ToInt(args[1])))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			return mapStringInt(args[0], args[1], n, vbLeft)
+		},
+	})
+	RegisterFunction("Right", Function{MinArgs: 2, MaxArgs: 2,
+		Call: func(args []variant.Variant) variant.Variant {
+			return variant.RString(vbRight(variant.ToString(args[0]), variant.ToInt(args[1])))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			return mapStringInt(args[0], args[1], n, vbRight)
+		},
+	})
+	RegisterFunction("Mid", Function{MinArgs: 2, MaxArgs: 3,
+		Call: func(args []variant.Variant) variant.Variant {
+			length := -1
+			if len(args) == 3 {
+				length = variant.ToInt(args[2])
+			}
+			return variant.RString(vbMid(variant.ToString(args[0]), variant.ToInt(args[1]), length))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			s := args[0].ToString()
+			start := args[1].ToInt()
+			var length variant.IntList
+			if len(args) == 3 {
+				length = args[2].ToInt()
+			}
+			r := variant.NewStringList(n)
+			for i := range r {
+				l := -1
+				if length != nil {
+					l = length[i]
+				}
+				r[i] = vbMid(s[i], start[i], l)
+			}
+			return r
+		},
+	})
+	RegisterFunction("Len", Function{MinArgs: 1, MaxArgs: 1,
+		Call: func(args []variant.Variant) variant.Variant {
+			return variant.RInt(utf8.RuneCountInString(variant.ToString(args[0])))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			s := args[0].ToString()
+			r := variant.NewIntList(n)
+			for i := range r {
+				r[i] = utf8.RuneCountInString(s[i])
+			}
+			return r
+		},
+	})
+	RegisterFunction("InStr", Function{MinArgs: 2, MaxArgs: 3,
+		Call: func(args []variant.Variant) variant.Variant {
+			if len(args) == 2 {
+				return variant.RInt(vbInStr(1, variant.ToString(args[0]), variant.ToString(args[1])))
+			}
+			return variant.RInt(vbInStr(variant.ToInt(args[0]), variant.ToString(args[1]), variant.ToString(args[2])))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			var start variant.IntList
+			if len(args) == 3 {
+				start = args[0].ToInt()
+				args = args[1:]
+			}
+			s1 := args[0].ToString()
+			s2 := args[1].ToString()
+			r := variant.NewIntList(n)
+			for i := range r {
+				st := 1
+				if start != nil {
+					st = start[i]
+				}
+				r[i] = vbInStr(st, s1[i], s2[i])
+			}
+			return r
+		},
+	})
+	registerStringFunction("Trim", func(s string) string { return strings.Trim(s, " ") })
+	registerStringFunction("LTrim", func(s string) string { return strings.TrimLeft(s, " ") })
+	registerStringFunction("RTrim", func(s string) string { return strings.TrimRight(s, " ") })
+	registerStringFunction("UCase", strings.ToUpper)
+	registerStringFunction("LCase", strings.ToLower)
+	RegisterFunction("Replace", Function{MinArgs: 3, MaxArgs: 3,
+		Call: func(args []variant.Variant) variant.Variant {
+			return variant.RString(vbReplace(variant.ToString(args[0]), variant.ToString(args[1]), variant.ToString(args[2])))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			s := args[0].ToString()
+			find := args[1].ToString()
+			repl := args[2].ToString()
+			r := variant.NewStringList(n)
+			for i := range r {
+				r[i] = vbReplace(s[i], find[i], repl[i])
+			}
+			return r
+		},
+	})
+}
+
+// registerStringFunction registers a function with a single string argument.
+func registerStringFunction(name string, fn func(string) string) {
+	RegisterFunction(name, Function{MinArgs: 1, MaxArgs: 1,
+		Call: func(args []variant.Variant) variant.Variant {
+			return variant.RString(fn(variant.ToString(args[0])))
+		},
+		CallList: func(args []variant.RList, n int) variant.RList {
+			s := args[0].ToString()
+			r := variant.NewStringList(n)
+			for i := range r {
+				r[i] = fn(s[i])
+			}
+			return r
+		},
+	})
+}
+
+// mapStringInt calls fn for each record with a string and an int argument.
+func mapStringInt(l1, l2 variant.RList, n int, fn func(string, int) string) variant.StringList {
+	s := l1.ToString()
+	x := l2.ToInt()
+	r := variant.NewStringList(n)
+	for i := range r {
+		r[i] = fn(s[i], x[i])
+	}
+	return r
+}
+
+// vbLeft returns the first n characters of s.
+func vbLeft(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// vbRight returns the last n characters of s.
+func vbRight(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	i := len(s)
+	for i > 0 && n > 0 {
+		_, size := utf8.DecodeLastRuneInString(s[:i])
+		i -= size
+		n--
+	}
+	return s[i:]
+}
+
+// vbMid returns length characters of s starting at the 1-based position start,
+// or all remaining characters if length is negative.
+func vbMid(s string, start, length int) string {
+	if start < 1 {
+		return ""
+	}
+	s = vbRight(s, utf8.RuneCountInString(s)-start+1)
+	if length < 0 {
+		return s
+	}
+	return vbLeft(s, length)
+}
+
+// vbInStr returns the 1-based position of the first occurrence of s2 in s1 starting the search
+// at the 1-based position start, or 0 if s2 is not found.
+func vbInStr(start int, s1, s2 string) int {
+	switch {
+	case start < 1 || s1 == "":
+		return 0
+	case s2 == "":
+		return start
+	}
+	rest := vbMid(s1, start, -1)
+	p := strings.Index(rest, s2)
+	if p < 0 {
+		return 0
+	}
+	return start + utf8.RuneCountInString(rest[:p])
+}
+
+// vbReplace replaces all occurrences of find in s. An empty find string leaves s unchanged.
+func vbReplace(s, find, repl string) string {
+	if find == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, find, repl)
+}
@@ -0,0 +1,189 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/raceresult/go-model/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctions_Eval(t *testing.T) {
+	tests := []struct {
+		formula string
+		want    variant.Variant
+	}{
+		// strings
+		{"Left(\"Müller\", 2)", variant.RString("Mü")},
+		{"Left(\"abc\", 10)", variant.RString("abc")},
+		{"Left(\"abc\", -1)", variant.RString("")},
+		{"Right(\"Müller\", 5)", variant.RString("üller")},
+		{"Mid(\"Müller\", 2, 3)", variant.RString("üll")},
+		{"Mid(\"Müller\", 3)", variant.RString("ller")},
+		{"Mid(\"abc\", 5)", variant.RString("")},
+		{"Mid(\"abc\", 0)", variant.RString("")},
+		{"Len(\"Müller\")", variant.RInt(6)},
+		{"Len([Unknown])", variant.RInt(0)},
+		{"InStr(\"Müller\", \"l\")", variant.RInt(3)},
+		{"InStr(4, \"Müller\", \"l\")", variant.RInt(4)},
+		{"InStr(\"abc\", \"x\")", variant.RInt(0)},
+		{"InStr(\"abc\", \"\")", variant.RInt(1)},
+		{"InStr(\"\", \"a\")", variant.RInt(0)},
+		{"Trim(\"  a b  \")", variant.RString("a b")},
+		{"LTrim(\"  a \")", variant.RString("a ")},
+		{"RTrim(\"  a \")", variant.RString("  a")},
+		{"UCase(\"müller\")", variant.RString("MÜLLER")},
+		{"LCase([LastName])", variant.RString("miller")},
+		{"Replace(\"a-b-c\", \"-\", \"+\")", variant.RString("a+b+c")},
+		{"Replace(\"abc\", \"\", \"+\")", variant.RString("abc")},
+
+		// dates
+		{"Year([DateOfBirth])", variant.RInt(1980)},
+		{"Month([DateOfBirth])", variant.RInt(5)},
+		{"Day(#2024-02-29#)", variant.RInt(29)},
+		{"Weekday(#2024-05-05#)", variant.RInt(1)},
+		{"Year([Unknown])", nil},
+		{"DateAdd(\"m\", 1, #2024-01-31#)", variant.RDate(date.New(2024, 2, 29))},
+		{"DateAdd(\"yyyy\", -1, #2024-02-29#)", variant.RDate(date.New(2023, 2, 28))},
+		{"DateAdd(\"q\", 1, #2024-05-01#)", variant.RDate(date.New(2024, 8, 1))},
+		{"DateAdd(\"d\", 10, [DateOfBirth])", variant.RDate(date.New(1980, 5, 11))},
+		{"DateAdd(\"ww\", 1, #2024-05-01#)", variant.RDate(date.New(2024, 5, 8))},
+		{"DateAdd(\"h\", 25, #2024-05-01#)", variant.RDateTime(datetime.New(2024, 5, 2, 1, 0, 0))},
+		{"DateAdd(\"x\", 1, #2024-05-01#)", nil},
+		{"DateDiff(\"yyyy\", #2024-12-31#, #2025-01-01#)", variant.RInt(1)},
+		{"DateDiff(\"q\", #2024-03-31#, #2024-04-01#)", variant.RInt(1)},
+		{"DateDiff(\"m\", #2024-05-31#, #2024-03-01#)", variant.RInt(-2)},
+		{"DateDiff(\"d\", #2024-01-01#, #2024-12-31#)", variant.RInt(365)},
+		{"DateDiff(\"w\", #2024-05-01#, #2024-05-14#)", variant.RInt(1)},
+		{"DateDiff(\"ww\", #2024-05-04#, #2024-05-05#)", variant.RInt(1)},
+		{"DateDiff(\"h\", #2024-05-01 10:59:59#, #2024-05-01 11:00:00#)", variant.RInt(1)},
+		{"DateDiff(\"n\", #2024-05-01#, #2024-05-02#)", variant.RInt(1440)},
+		{"DateDiff(\"s\", #2024-05-01 10:00:00#, #2024-05-01 09:59:00#)", variant.RInt(-60)},
+
+		// logic
+		{"IIf([Bib] > 100, \"big\", \"small\")", variant.RString("big")},
+		{"IIf([Club], 1, 2)", variant.RInt(2)},
+		{"Switch([Bib] < 10, \"a\", [Bib] < 200, \"b\", True, \"c\")", variant.RString("b")},
+		{"Switch(False, 1)", nil},
+		{"Choose(2, \"a\", \"b\", \"c\")", variant.RString("b")},
+		{"Choose(4, \"a\", \"b\", \"c\")", nil},
+
+		// format
+		{"Format(1234.5, \"#,##0.00\")", variant.RString("1,234.50")},
+		{"Format(0.5, \"#.##\")", variant.RString(".5")},
+		{"Format(2.345, \"0.0\")", variant.RString("2.3")},
+		{"Format(2.35, \"0.0\")", variant.RString("2.4")},
+		{"Format(-9.99, \"0.0\")", variant.RString("-10.0")},
+		{"Format(-0.01, \"0.0\")", variant.RString("0.0")},
+		{"Format(7, \"000\")", variant.RString("007")},
+		{"Format(0.256, \"Percent\")", variant.RString("25.60%")},
+		{"Format(1234567, \"Standard\")", variant.RString("1,234,567.00")},
+		{"Format([Time], \"0\")", variant.RString("3601")},
+		{"Format(\"12.5\", \"0.00 km\")", variant.RString("12.50 km")},
+		{"Format(\"abc\", \"0.00\")", variant.RString("abc")},
+		{"Format(12)", variant.RString("12")},
+		{"Format([DateOfBirth], \"dd.mm.yyyy\")", variant.RString("01.05.1980")},
+		{"Format([DateOfBirth], \"d mmmm yy, dddd\")", variant.RString("1 May 80, Thursday")},
+		{"Format(#2024-05-01 08:05:09#, \"hh:mm:ss\")", variant.RString("08:05:09")},
+		{"Format(#2024-05-01 08:05:09#, \"h:n \"\"Uhr\"\"\")", variant.RString("8:5 Uhr")},
+		{"Format(0.5, \"hh:nn\")", variant.RString("12:00")},
+		{"Format([Unknown], \"0\")", variant.RString("")},
+	}
+	for _, tt := range tests {
+		got, err := MustParse(tt.formula).Eval(testValues)
+		if assert.NoError(t, err, tt.formula) {
+			assert.Equal(t, tt.want, got, tt.formula)
+		}
+	}
+}
+
+func TestFunctions_EvalList(t *testing.T) {
+	columns := map[string]variant.RList{
+		"Name":    variant.StringList{"Müller", "  smith ", ""},
+		"Bib":     variant.IntList{3, 12, -1},
+		"Time":    variant.DecimalList{decimal.FromFloat(3600.5), 0, decimal.FromInt(-12)},
+		"Date":    variant.DateList{date.New(1980, 5, 1), date.New(2024, 2, 29), date.ZeroDateVB},
+		"Start":   variant.DateTimeList{datetime.New(2024, 5, 1, 9, 0, 0), datetime.New(2024, 5, 1, 23, 59, 59), datetime.ZeroDate()},
+		"Variant": variant.VariantList{variant.RString("x"), nil, variant.RDate(date.New(2000, 1, 1))},
+	}
+	for _, formula := range []string{
+		"Left([Name], [Bib])",
+		"Right([Name], 2)",
+		"Mid([Name], 2, [Bib])",
+		"Mid([Name], [Bib])",
+		"Len([Name])",
+		"InStr([Name], \"l\")",
+		"InStr([Bib], [Name], \"m\")",
+		"Trim([Name]) & \"|\"",
+		"UCase(LTrim(RTrim([Name])))",
+		"Replace([Name], \"l\", \"L\")",
+		"Year([Date]) + Month([Date]) + Day([Date]) + Weekday([Date])",
+		"Hour([Start]) * 60 + Minute([Start]) + Second([Start])",
+		"Year([Variant])",
+		"DateAdd(\"m\", [Bib], [Date])",
+		"DateAdd(\"h\", [Bib], [Date])",
+		"DateAdd(\"n\", [Bib], [Start])",
+		"DateAdd(IIf([Bib] > 5, \"d\", \"h\"), 1, [Date])",
+		"DateAdd(\"d\", 1, [Variant])",
+		"DateDiff(\"d\", [Date], [Start])",
+		"DateDiff(\"ww\", [Start], [Date])",
+		"DateDiff(\"s\", [Variant], [Start])",
+		"DateDiff(\"x\", [Date], [Start])",
+		"IIf([Bib] > 5, [Name], \"none\")",
+		"IIf([Bib] > 5, [Bib], [Time])",
+		"IIf([Bib] > 5, [Time], [Time] * 2)",
+		"Switch([Bib] = 3, 1, [Bib] = 12, 2)",
+		"Switch([Bib] > 5, [Name], True, \"none\")",
+		"Switch([Bib] > 5, [Bib], True, [Time])",
+		"Choose([Bib], \"a\", \"b\", \"c\")",
+		"Choose([Bib] + 2, [Bib], 1, 2)",
+		"Format([Time], \"0.0\")",
+		"Format([Bib], \"000\")",
+		"Format([Bib])",
+		"Format([Time], \"Standard\")",
+		"Format([Date], \"dd.mm.yyyy\")",
+		"Format([Start], \"hh:nn:ss\")",
+		"Format([Date])",
+		"Format([Variant], \"yyyy\")",
+		"Format([Bib], IIf([Bib] > 5, \"0.0\", \"000\"))",
+	} {
+		t.Run(formula, func(t *testing.T) {
+			e := MustParse(formula)
+			got, err := e.EvalList(columns, 3)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for i := 0; i < 3; i++ {
+				row := variant.VariantMap{}
+				for k, l := range columns {
+					if v := l.Item(i); v != nil {
+						row[k] = v
+					}
+				}
+				want, err := e.Eval(row)
+				assert.NoError(t, err)
+				assert.True(t, variant.Equals(want, got.Item(i), true), "row %d: want %v, got %v", i, want, got.Item(i))
+			}
+		})
+	}
+
+	// typed arguments result in typed lists, the zero dates in the last record are checked above
+	for formula, want := range map[string]variant.RList{
+		"Len([Name])":                                 variant.IntList{6, 8},
+		"DateAdd(\"d\", 1, [Date])":                   variant.DateList{date.New(1980, 5, 2), date.New(2024, 3, 1)},
+		"DateAdd(\"h\", 1, [Start])":                  variant.DateTimeList{datetime.New(2024, 5, 1, 10, 0, 0), datetime.New(2024, 5, 2, 0, 59, 59)},
+		"DateDiff(\"yyyy\", [Date], [Start])":         variant.IntList{44, 0},
+		"Switch([Bib] > 5, [Bib], True, [Bib] * 2)":   variant.IntList{6, 12},
+		"Choose(IIf([Bib] > 5, 2, 1), [Name], \"x\")": variant.StringList{"Müller", "x"},
+		"Format([Bib], \"00\")":                       variant.StringList{"03", "12"},
+		"Format([Date], \"yyyy\")":                    variant.StringList{"1980", "2024"},
+	} {
+		got, err := MustParse(formula).EvalList(columns, 3)
+		if assert.NoError(t, err, formula) {
+			assert.IsType(t, want, got, formula)
+			assert.Equal(t, want, variant.Take(got, []int{0, 1}), formula)
+		}
+	}
+}
@@ -1,11 +1,18 @@
 module github.com/raceresult/go-model
 
-go 1.16
+go 1.18
 
 require (
 	github.com/onsi/gomega v1.33.1
 	github.com/rickb777/plural v1.4.2
 	github.com/stretchr/testify v1.8.4
-	golang.org/x/net v0.27.0 // indirect
 	golang.org/x/text v0.16.0
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.27.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,286 @@
+// Package ical creates iCalendar documents (RFC5545) with events, e.g. the start times of the
+// contests and the registration deadlines of an event, so that participants can subscribe to them.
+package ical
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/date/timespan"
+)
+
+// DefaultProdID is used if Calendar.ProdID is empty.
+const DefaultProdID = "-//raceresult//go-model//EN"
+
+// Calendar is an iCalendar object (VCALENDAR).
+type Calendar struct {
+	// ProdID identifies the product that created the calendar, DefaultProdID if empty.
+	ProdID string
+	// Name is the display name of the calendar (X-WR-CALNAME), optional.
+	Name string
+	// Method is the iTIP method, e.g. "PUBLISH", optional.
+	Method string
+	// Domain is used for the UIDs of events without UID, see NewUID.
+	Domain string
+	// Stamp is the DTSTAMP of the events; the current time is used if zero.
+	Stamp  time.Time
+	Events []Event
+}
+
+// Event is an event (VEVENT) of a calendar.
+type Event struct {
+	// UID identifies the event; if empty, it is generated with NewUID from Key, so that calendar
+	// subscriptions recognise the event when the calendar is created again.
+	UID string
+	// Key is a stable identifier of the event within the calendar, e.g. "contest/3". It should not
+	// change when the event is renamed or moved. If empty, Summary and Start are used instead, so
+	// the UID changes with them.
+	Key         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Categories  []string
+	// Start is the start time; its location is written as TZID with a VTIMEZONE block unless it is
+	// UTC, so it should be a location loaded by name such as "Europe/Berlin" rather than time.Local.
+	Start time.Time
+	// Duration is the length of the event; zero for events at a point in time such as deadlines.
+	Duration time.Duration
+	// AllDay events only use the dates of Start and Start plus Duration (exclusive), see NewAllDayEvent.
+	AllDay bool
+	// Rule optionally makes the event recurring, ExDates are excluded occurrences.
+	Rule    timespan.RRule
+	ExDates []time.Time
+}
+
+// NewEvent creates an event for the time span.
+func NewEvent(summary string, ts timespan.TimeSpan) Event {
+	return Event{Summary: summary, Start: ts.Start(), Duration: ts.Duration()}
+}
+
+// NewAllDayEvent creates an all-day event for the date range.
+func NewAllDayEvent(summary string, dr timespan.DateRange) Event {
+	dr = dr.Normalise()
+	return Event{Summary: summary, Start: dr.StartUTC(), Duration: dr.Duration(), AllDay: true}
+}
+
+// NewUID creates a UID of the form "<hash>@<domain>" from the given parts. The same parts always
+// result in the same UID, so the parts should be stable keys such as IDs rather than names or times.
+func NewUID(domain string, parts ...interface{}) string {
+	h := sha1.New()
+	for _, p := range parts {
+		if t, ok := p.(time.Time); ok {
+			p = t.UTC().Format(timespan.RFC5545DateTimeZulu)
+		}
+		fmt.Fprintf(h, "%v\x00", p)
+	}
+	if domain == "" {
+		domain = "go-model"
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12]) + "@" + domain
+}
+
+// String returns the calendar as iCalendar document.
+func (c Calendar) String() string {
+	var buf bytes.Buffer
+	c.WriteTo(&buf)
+	return buf.String()
+}
+
+// WriteTo writes the calendar as iCalendar document with CRLF line endings and lines folded
+// after 75 octets. It implements io.WriterTo.
+func (c Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &contentWriter{w: w}
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = DefaultProdID
+	}
+	stamp := c.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + Escape(prodID))
+	cw.line("CALSCALE:GREGORIAN")
+	if c.Method != "" {
+		cw.line("METHOD:" + c.Method)
+	}
+	if c.Name != "" {
+		cw.line("X-WR-CALNAME:" + Escape(c.Name))
+	}
+	for _, tz := range c.timezones() {
+		tz.write(cw)
+	}
+	for _, e := range c.Events {
+		e.write(cw, c.Domain, stamp)
+	}
+	cw.line("END:VCALENDAR")
+	return cw.n, cw.err
+}
+
+func (e Event) write(cw *contentWriter, domain string, stamp time.Time) {
+	uid := e.UID
+	switch {
+	case uid != "":
+	case e.Key != "":
+		uid = NewUID(domain, e.Key)
+	default:
+		uid = NewUID(domain, e.Summary, e.Start)
+	}
+	cw.line("BEGIN:VEVENT")
+	cw.line("UID:" + Escape(uid))
+	cw.line("DTSTAMP:" + stamp.UTC().Format(timespan.RFC5545DateTimeZulu))
+	if e.AllDay {
+		start := e.Start.Format(timespan.RFC5545DateLayout)
+		cw.line("DTSTART;VALUE=DATE:" + start)
+		if end := e.Start.Add(e.Duration).Format(timespan.RFC5545DateLayout); end != start {
+			cw.line("DTEND;VALUE=DATE:" + end)
+		}
+		if e.Rule.Freq != 0 {
+			cw.line("RRULE:" + e.Rule.String())
+		}
+		if len(e.ExDates) > 0 {
+			dates := make([]string, len(e.ExDates))
+			for i, ex := range e.ExDates {
+				dates[i] = ex.Format(timespan.RFC5545DateLayout)
+			}
+			cw.line("EXDATE;VALUE=DATE:" + strings.Join(dates, ","))
+		}
+	} else {
+		r := timespan.Recurrence{Start: e.Start, Duration: e.Duration, Rule: e.Rule, ExDates: e.ExDates}
+		for _, l := range strings.Split(r.FormatRFC5545(), "\r\n") {
+			cw.line(l)
+		}
+	}
+	cw.line("SUMMARY:" + Escape(e.Summary))
+	if e.Description != "" {
+		cw.line("DESCRIPTION:" + Escape(e.Description))
+	}
+	if e.Location != "" {
+		cw.line("LOCATION:" + Escape(e.Location))
+	}
+	if e.URL != "" {
+		cw.line("URL:" + e.URL)
+	}
+	if len(e.Categories) > 0 {
+		cats := make([]string, len(e.Categories))
+		for i, s := range e.Categories {
+			cats[i] = Escape(s)
+		}
+		cw.line("CATEGORIES:" + strings.Join(cats, ","))
+	}
+	cw.line("END:VEVENT")
+}
+
+// maxListedYears is the number of years after the start of a recurring event in which the end of
+// the rule is searched.
+const maxListedYears = 10
+
+// timezones returns the time zones of the events that need a VTIMEZONE block, sorted by name.
+// The blocks cover the years up to the last occurrence of the events; time zones of events with
+// unbounded rules or rules ending after maxListedYears end with recurring observances, see timezone.
+func (c Calendar) timezones() []timezone {
+	byName := map[string]*timezone{}
+	for _, e := range c.Events {
+		loc := e.Start.Location()
+		if e.AllDay || loc == time.UTC {
+			continue
+		}
+		tz, ok := byName[loc.String()]
+		if !ok {
+			tz = &timezone{loc: loc, from: e.Start.Year(), to: e.Start.Year()}
+			byName[loc.String()] = tz
+		}
+		end := e.Start.Year()
+		r := timespan.Recurrence{Start: e.Start, Rule: e.Rule}
+		if last, ok := r.Last(e.Start.AddDate(maxListedYears, 0, 0)); ok {
+			end = last.Year()
+		} else {
+			tz.recurring = true
+		}
+		for _, ex := range e.ExDates {
+			if ex.Year() > end {
+				end = ex.Year()
+			}
+		}
+		if e.Start.Year() < tz.from {
+			tz.from = e.Start.Year()
+		}
+		if end > tz.to {
+			tz.to = end
+		}
+	}
+	res := make([]timezone, 0, len(byName))
+	for _, tz := range byName {
+		res = append(res, *tz)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].loc.String() < res[j].loc.String() })
+	return res
+}
+
+// Escape escapes a TEXT value: backslashes, semicolons, commas and line breaks.
+func Escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+	return r.Replace(s)
+}
+
+// Unescape reverses Escape.
+func Unescape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				sb.WriteByte('\n')
+			default:
+				sb.WriteByte(s[i])
+			}
+			continue
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+// maxLineLength is the maximum length of a content line in octets, excluding the line break.
+const maxLineLength = 75
+
+// contentWriter writes content lines, folding them after maxLineLength octets without splitting
+// UTF-8 sequences. The first error is kept and stops all further writing.
+type contentWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *contentWriter) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	var buf strings.Builder
+	limit := maxLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		buf.WriteString(s[:cut])
+		buf.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineLength - 1
+	}
+	buf.WriteString(s)
+	buf.WriteString("\r\n")
+	n, err := io.WriteString(cw.w, buf.String())
+	cw.n += int64(n)
+	cw.err = err
+}
@@ -0,0 +1,266 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sesbase "github.com/raceresult/go-model"
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/date/timespan"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/raceresult/go-model/registration"
+	"github.com/raceresult/go-model/website"
+	"github.com/stretchr/testify/assert"
+)
+
+var stamp = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func lines(s string) []string {
+	return strings.Split(strings.TrimSuffix(s, "\r\n"), "\r\n")
+}
+
+func TestCalendarUTC(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	e := NewEvent("10 km; Run, Walk", timespan.TimeSpanOf(start, 90*time.Minute))
+	e.UID = "1@example.com"
+	e.Description = "Line 1\nLine 2 \\ end"
+	e.Location = "Marktplatz"
+	e.URL = "https://example.com/event"
+	e.Categories = []string{"Run", "10,5 km"}
+	c := Calendar{Name: "City Run", Method: "PUBLISH", Stamp: stamp, Events: []Event{e}}
+
+	assert.Equal(t, []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//raceresult//go-model//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:City Run",
+		"BEGIN:VEVENT",
+		"UID:1@example.com",
+		"DTSTAMP:20240101T120000Z",
+		"DTSTART:20240501T080000Z",
+		"DURATION:PT1H30M",
+		`SUMMARY:10 km\; Run\, Walk`,
+		`DESCRIPTION:Line 1\nLine 2 \\ end`,
+		"LOCATION:Marktplatz",
+		"URL:https://example.com/event",
+		`CATEGORIES:Run,10\,5 km`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, lines(c.String()))
+}
+
+func TestCalendarTimezone(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	e := Event{
+		UID:      "parkrun@example.com",
+		Summary:  "parkrun",
+		Start:    time.Date(2024, 3, 16, 9, 0, 0, 0, berlin),
+		Duration: time.Hour,
+		Rule:     timespan.RRule{Freq: timespan.Weekly, Count: 10, WeekStart: time.Monday},
+		ExDates:  []time.Time{time.Date(2024, 3, 30, 9, 0, 0, 0, berlin)},
+	}
+	c := Calendar{Stamp: stamp, Events: []Event{e}}
+	assert.Equal(t, []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//raceresult//go-model//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20230326T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20231029T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20240331T020000",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20241027T030000",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:parkrun@example.com",
+		"DTSTAMP:20240101T120000Z",
+		"DTSTART;TZID=Europe/Berlin:20240316T090000",
+		"DURATION:PT1H",
+		"RRULE:FREQ=WEEKLY;COUNT=10",
+		"EXDATE;TZID=Europe/Berlin:20240330T090000",
+		"SUMMARY:parkrun",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, lines(c.String()))
+}
+
+func TestTimezoneRecurring(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	e := Event{UID: "x", Start: time.Date(2024, 1, 6, 9, 0, 0, 0, berlin), Rule: timespan.RRule{Freq: timespan.Weekly, Count: 200, WeekStart: time.Monday}}
+
+	// the time zone covers the last occurrence of rules with COUNT
+	s := Calendar{Stamp: stamp, Events: []Event{e}}.String()
+	assert.Contains(t, s, "BEGIN:STANDARD\r\nDTSTART:20271031T030000\r\n")
+	assert.NotContains(t, s, "DTSTART:2028")
+	assert.NotContains(t, s, "RRULE:FREQ=YEARLY")
+
+	// unbounded rules result in recurring observances
+	e.Rule.Count = 0
+	s = Calendar{Stamp: stamp, Events: []Event{e}}.String()
+	assert.Contains(t, s, "BEGIN:DAYLIGHT\r\nDTSTART:20240331T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\n"+
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\nTZNAME:CEST\r\nEND:DAYLIGHT\r\n")
+	assert.Contains(t, s, "BEGIN:STANDARD\r\nDTSTART:20241027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n"+
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nTZNAME:CET\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n")
+
+	// rules without matches end with recurring observances as well
+	e.Rule = timespan.RRule{Freq: timespan.Minutely, Count: 2, ByMonth: []time.Month{time.February}, ByMonthDay: []int{30}, WeekStart: time.Monday}
+	s = Calendar{Stamp: stamp, Events: []Event{e}}.String()
+	assert.Contains(t, s, "RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\n")
+	e.Rule = timespan.RRule{Freq: timespan.Weekly, WeekStart: time.Monday}
+
+	newYork, _ := time.LoadLocation("America/New_York")
+	e.Start = time.Date(2024, 1, 6, 9, 0, 0, 0, newYork)
+	s = Calendar{Stamp: stamp, Events: []Event{e}}.String()
+	assert.Contains(t, s, "DTSTART:20240310T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU\r\n")
+	assert.Contains(t, s, "DTSTART:20241103T020000\r\nTZOFFSETFROM:-0400\r\nTZOFFSETTO:-0500\r\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU\r\n")
+
+	assert.Equal(t, 31, ruleDay(yearlyRule(time.Date(2024, 3, 31, 2, 0, 0, 0, time.UTC)), 2024))
+	assert.Equal(t, 30, ruleDay(yearlyRule(time.Date(2024, 3, 31, 2, 0, 0, 0, time.UTC)), 2025))
+	assert.Equal(t, 9, ruleDay(yearlyRule(time.Date(2024, 3, 10, 2, 0, 0, 0, time.UTC)), 2025))
+}
+
+func TestTimezoneWithoutTransitions(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	c := Calendar{Stamp: stamp, Events: []Event{{UID: "x", Start: time.Date(2024, 5, 1, 9, 0, 0, 0, tokyo)}}}
+	assert.Contains(t, c.String(), "BEGIN:VTIMEZONE\r\nTZID:Asia/Tokyo\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\n"+
+		"TZOFFSETFROM:+0900\r\nTZOFFSETTO:+0900\r\nTZNAME:JST\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n")
+
+	assert.Equal(t, "+0530", formatOffset(5*3600+30*60))
+	assert.Equal(t, "-0930", formatOffset(-(9*3600 + 30*60)))
+	assert.Equal(t, "+001215", formatOffset(12*60+15))
+}
+
+func TestAllDayEvent(t *testing.T) {
+	e := NewAllDayEvent("Expo", timespan.NewDateRange(date.New(2024, 5, 3), date.New(2024, 5, 5)))
+	e.ExDates = []time.Time{time.Date(2024, 5, 4, 0, 0, 0, 0, time.UTC)}
+	c := Calendar{Stamp: stamp, Domain: "example.com", Events: []Event{e}}
+	s := c.String()
+	assert.Contains(t, s, "\r\nDTSTART;VALUE=DATE:20240503\r\nDTEND;VALUE=DATE:20240505\r\nEXDATE;VALUE=DATE:20240504\r\nSUMMARY:Expo\r\n")
+	assert.NotContains(t, s, "VTIMEZONE")
+
+	e = NewAllDayEvent("Deadline", timespan.OneDayRange(date.New(2024, 5, 3)))
+	assert.Contains(t, Calendar{Stamp: stamp, Events: []Event{e}}.String(), "\r\nDTSTART;VALUE=DATE:20240503\r\nDTEND;VALUE=DATE:20240504\r\n")
+}
+
+func TestFolding(t *testing.T) {
+	e := Event{UID: "x", Start: stamp, Description: strings.Repeat("ä", 60)}
+	s := Calendar{Stamp: stamp, Events: []Event{e}}.String()
+	var desc []string
+	for _, l := range lines(s) {
+		assert.LessOrEqual(t, len(l), 75)
+		if strings.HasPrefix(l, "DESCRIPTION:") || len(desc) > 0 && strings.HasPrefix(l, " ") {
+			desc = append(desc, l)
+		}
+	}
+	assert.Equal(t, []string{
+		"DESCRIPTION:" + strings.Repeat("ä", 31),
+		" " + strings.Repeat("ä", 29),
+	}, desc)
+
+	unfolded := strings.ReplaceAll(s, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat("ä", 60)+"\r\n")
+}
+
+func TestEscape(t *testing.T) {
+	s := "a\\b;c,d\r\ne\nf"
+	assert.Equal(t, `a\\b\;c\,d\ne\nf`, Escape(s))
+	assert.Equal(t, "a\\b;c,d\ne\nf", Unescape(Escape(s)))
+	assert.Equal(t, "x\ny:", Unescape(`x\Ny\:`))
+}
+
+func TestNewUID(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	uid := NewUID("example.com", "10 km", start)
+	assert.True(t, strings.HasSuffix(uid, "@example.com"))
+	assert.Len(t, uid, 24+len("@example.com"))
+	assert.Equal(t, uid, NewUID("example.com", "10 km", start.In(berlin)))
+	assert.NotEqual(t, uid, NewUID("example.com", "5 km", start))
+	assert.True(t, strings.HasSuffix(NewUID("", "x"), "@go-model"))
+
+	// events without UID get the same UID on every export
+	c := Calendar{Stamp: stamp, Domain: "example.com", Events: []Event{{Summary: "10 km", Start: start}}}
+	assert.Contains(t, c.String(), "\r\nUID:"+uid+"\r\n")
+
+	// with a key, the UID does not change when the event is renamed or moved
+	c.Events[0].Key = "contest/1"
+	keyUID := NewUID("example.com", "contest/1")
+	assert.Contains(t, c.String(), "\r\nUID:"+keyUID+"\r\n")
+	c.Events[0].Summary, c.Events[0].Start = "10 km Run", start.Add(time.Hour)
+	assert.Contains(t, c.String(), "\r\nUID:"+keyUID+"\r\n")
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteToError(t *testing.T) {
+	_, err := Calendar{}.WriteTo(failingWriter{})
+	assert.EqualError(t, err, "disk full")
+}
+
+func TestModelEvents(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	c := sesbase.Contest{ID: 3, Name: "Marathon", Day: 2, StartTime: decimal.FromDuration(9*time.Hour + 30*time.Minute)}
+	e := ContestEvent(c, date.New(2024, 3, 30), berlin)
+	assert.Equal(t, "Marathon", e.Summary)
+	assert.Equal(t, "contest/3", e.Key)
+	assert.Equal(t, time.Date(2024, 3, 31, 9, 30, 0, 0, berlin), e.Start)
+
+	c.Day = 0
+	assert.Equal(t, time.Date(2024, 3, 30, 9, 30, 0, 0, berlin), ContestEvent(c, date.New(2024, 3, 30), berlin).Start)
+
+	r := registration.Registration{
+		Name:        "online",
+		Key:         "k1",
+		EnabledFrom: datetime.New(2024, 1, 1, 0, 0, 0),
+		EnabledTo:   datetime.New(2024, 3, 29, 23, 59, 0).WithTimezone(time.UTC),
+	}
+	evs := RegistrationEvents(r, berlin, "Registration opens", "Registration closes")
+	assert.Len(t, evs, 2)
+	assert.Equal(t, "Registration opens", evs[0].Summary)
+	assert.Equal(t, "registration/k1/open", evs[0].Key)
+	assert.Equal(t, "registration/k1/close", evs[1].Key)
+	r.Key = ""
+	assert.Equal(t, "registration/online/open", RegistrationEvents(r, berlin, "o", "c")[0].Key)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, berlin), evs[0].Start)
+	assert.True(t, evs[1].Start.Equal(time.Date(2024, 3, 29, 23, 59, 0, 0, time.UTC)))
+	assert.Len(t, RegistrationEvents(registration.Registration{EnabledFrom: datetime.ZeroDate()}, berlin, "o", "c"), 0)
+
+	rc := website.RegistrationContest{ID: 3, Name: "10 km", Start: time.Date(2024, 5, 1, 10, 0, 0, 0, berlin), EnabledTo: time.Date(2024, 4, 28, 0, 0, 0, 0, berlin)}
+	evs = RegistrationContestEvents(rc, "Registration opens", "Registration closes")
+	assert.Len(t, evs, 2)
+	assert.Equal(t, "10 km", evs[0].Summary)
+	assert.Equal(t, "contest/3", evs[0].Key)
+	assert.Equal(t, "Registration closes", evs[1].Summary)
+	assert.Equal(t, "contest/3/registration/close", evs[1].Key)
+}
@@ -0,0 +1,74 @@
+package ical
+
+import (
+	"strconv"
+	"time"
+
+	sesbase "github.com/raceresult/go-model"
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/registration"
+	"github.com/raceresult/go-model/website"
+)
+
+// ContestEvent creates an event for the start of a contest. Day 1 is the first day of the event
+// given by firstDay, contests without day start on the first day. The start time is taken as
+// local time in loc, also on days on which daylight saving time begins or ends. The key of the event
+// is built from the contest ID, so the UID does not change when the contest is renamed or moved.
+func ContestEvent(c sesbase.Contest, firstDay date.Date, loc *time.Location) Event {
+	day := c.Day
+	if day < 1 {
+		day = 1
+	}
+	y, m, d := firstDay.Add(date.PeriodOfDays(day - 1)).Date()
+	tod := c.StartTime.ToDuration()
+	start := time.Date(y, m, d, int(tod/time.Hour), int(tod/time.Minute%60), int(tod/time.Second%60), int(tod%time.Second), loc)
+	return Event{Key: contestKey(c.ID), Summary: c.Name, Start: start}
+}
+
+// RegistrationWindowEvents creates events at the opening and the closing time of a registration
+// window with the given summaries, e.g. "Registration opens" and "Registration closes". The keys of
+// the events are key followed by "/open" and "/close", key should identify the registration
+// window, e.g. "registration/online". Zero times are skipped.
+func RegistrationWindowEvents(key string, from, to time.Time, openSummary, closeSummary string) []Event {
+	var res []Event
+	if !from.IsZero() {
+		res = append(res, Event{Key: key + "/open", Summary: openSummary, Start: from})
+	}
+	if !to.IsZero() {
+		res = append(res, Event{Key: key + "/close", Summary: closeSummary, Start: to})
+	}
+	return res
+}
+
+// RegistrationEvents creates events for the registration window of an online registration form,
+// identified by the key of the form or by its name if the key is empty. Times without time zone
+// are taken as local time in loc.
+func RegistrationEvents(r registration.Registration, loc *time.Location, openSummary, closeSummary string) []Event {
+	id := r.Key
+	if id == "" {
+		id = r.Name
+	}
+	return RegistrationWindowEvents("registration/"+id, timeIn(r.EnabledFrom, loc), timeIn(r.EnabledTo, loc), openSummary, closeSummary)
+}
+
+// RegistrationContestEvents creates an event for the start of a contest offered in a registration
+// form and the events of its registration window, see RegistrationWindowEvents. The start event has
+// the same key as the one of ContestEvent.
+func RegistrationContestEvents(rc website.RegistrationContest, openSummary, closeSummary string) []Event {
+	var res []Event
+	if !rc.Start.IsZero() {
+		res = append(res, Event{Key: contestKey(rc.ID), Summary: rc.Name, Start: rc.Start})
+	}
+	key := contestKey(rc.ID) + "/registration"
+	return append(res, RegistrationWindowEvents(key, rc.EnabledFrom, rc.EnabledTo, openSummary, closeSummary)...)
+}
+
+// contestKey returns the key of the start event of a contest.
+func contestKey(id int) string {
+	return "contest/" + strconv.Itoa(id)
+}
+
+func timeIn(dt datetime.DateTime, loc *time.Location) time.Time {
+	return dt.WithTimezone(loc).ToTime()
+}
@@ -0,0 +1,181 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raceresult/go-model/date/timespan"
+)
+
+// timezone is a VTIMEZONE block for the location, covering the years from and to. If recurring is
+// set, the block also covers the following years, see addRules.
+type timezone struct {
+	loc       *time.Location
+	from, to  int
+	recurring bool
+}
+
+// checkYears is the number of years after timezone.to in which recurring observances are checked.
+const checkYears = 10
+
+// transition is a change of the UTC offset or the abbreviation of a location.
+type transition struct {
+	at                   time.Time
+	fromOffset, toOffset int
+	name                 string
+	dst                  bool
+	// rule is the yearly rule of a recurring observance, Freq is zero for a single transition.
+	rule timespan.RRule
+}
+
+// local returns the time of the transition in the local time before it, as used by DTSTART.
+func (tr transition) local() time.Time {
+	return tr.at.In(time.FixedZone("", tr.fromOffset))
+}
+
+func (tz timezone) write(cw *contentWriter) {
+	cw.line("BEGIN:VTIMEZONE")
+	cw.line("TZID:" + tz.loc.String())
+	trs := tz.transitions(tz.from-1, tz.to)
+	if tz.recurring {
+		trs = tz.addRules(trs)
+	}
+	if len(trs) == 0 {
+		t := time.Date(tz.from, time.January, 1, 0, 0, 0, 0, tz.loc)
+		name, offset := t.Zone()
+		at := time.Date(1970, time.January, 1, 0, 0, 0, 0, time.FixedZone("", offset))
+		trs = []transition{{at: at, fromOffset: offset, toOffset: offset, name: name, dst: t.IsDST()}}
+	}
+	for _, tr := range trs {
+		kind := "STANDARD"
+		if tr.dst {
+			kind = "DAYLIGHT"
+		}
+		cw.line("BEGIN:" + kind)
+		cw.line("DTSTART:" + tr.local().Format(timespan.RFC5545DateTimeLayout))
+		cw.line("TZOFFSETFROM:" + formatOffset(tr.fromOffset))
+		cw.line("TZOFFSETTO:" + formatOffset(tr.toOffset))
+		if tr.rule.Freq != 0 {
+			cw.line("RRULE:" + tr.rule.String())
+		}
+		if tr.name != "" && tr.name[0] != '+' && tr.name[0] != '-' {
+			cw.line("TZNAME:" + Escape(tr.name))
+		}
+		cw.line("END:" + kind)
+	}
+	cw.line("END:VTIMEZONE")
+}
+
+// transitions finds the transitions of the location from the beginning of the year from to the end
+// of the year to. The VTIMEZONE block starts with the year before tz.from, so that the observance
+// in effect at the beginning of tz.from is included.
+func (tz timezone) transitions(from, to int) []transition {
+	var res []transition
+	end := time.Date(to+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for t := time.Date(from, time.January, 1, 0, 0, 0, 0, time.UTC); t.Before(end); {
+		next := t.Add(24 * time.Hour)
+		if !sameZone(t.In(tz.loc), next.In(tz.loc)) {
+			// binary search for the first second of the new zone
+			lo, hi := t, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2).Truncate(time.Second)
+				if sameZone(lo.In(tz.loc), mid.In(tz.loc)) {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			_, from := lo.In(tz.loc).Zone()
+			name, to := hi.In(tz.loc).Zone()
+			res = append(res, transition{at: hi, fromOffset: from, toOffset: to, name: name, dst: hi.In(tz.loc).IsDST()})
+		}
+		t = next
+	}
+	return res
+}
+
+// addRules turns the transitions of the year tz.to into recurring observances with a yearly rule
+// such as "FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU" if the location changes its offset in the same way
+// in the following checkYears years. Otherwise the transitions of these years are added instead.
+func (tz timezone) addRules(trs []transition) []transition {
+	var last []int
+	for i, tr := range trs {
+		if tr.local().Year() == tz.to {
+			last = append(last, i)
+		}
+	}
+	next := tz.transitions(tz.to+1, tz.to+checkYears)
+	if len(last) == 0 || len(next) != len(last)*checkYears {
+		return append(trs, next...)
+	}
+	rules := make([]timespan.RRule, len(last))
+	for j, i := range last {
+		rules[j] = yearlyRule(trs[i].local())
+	}
+	for k, tr := range next {
+		j := k % len(last)
+		prev := trs[last[j]]
+		t := tr.local()
+		hh, mm, ss := prev.local().Clock()
+		expected := time.Date(t.Year(), rules[j].ByMonth[0], ruleDay(rules[j], t.Year()), hh, mm, ss, 0, t.Location())
+		if !t.Equal(expected) || tr.fromOffset != prev.fromOffset || tr.toOffset != prev.toOffset ||
+			tr.name != prev.name || tr.dst != prev.dst {
+			return append(trs, next...)
+		}
+	}
+	for j, i := range last {
+		trs[i].rule = rules[j]
+	}
+	return trs
+}
+
+// yearlyRule returns the rule for the weekday of the month of t, e.g. the last Sunday in March.
+func yearlyRule(t time.Time) timespan.RRule {
+	n := (t.Day()-1)/7 + 1
+	if t.Day()+7 > daysIn(t.Year(), t.Month()) {
+		n = -1
+	}
+	return timespan.RRule{
+		Freq:      timespan.Yearly,
+		ByMonth:   []time.Month{t.Month()},
+		ByDay:     []timespan.WeekdayNum{{N: n, Weekday: t.Weekday()}},
+		WeekStart: time.Monday,
+	}
+}
+
+// ruleDay returns the day of the month of a rule created by yearlyRule in the given year.
+func ruleDay(r timespan.RRule, year int) int {
+	m, wd := r.ByMonth[0], r.ByDay[0]
+	if wd.N < 0 {
+		last := daysIn(year, m)
+		lastWeekday := time.Date(year, m, last, 0, 0, 0, 0, time.UTC).Weekday()
+		return last - (7+int(lastWeekday)-int(wd.Weekday))%7
+	}
+	first := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	return 1 + (7+int(wd.Weekday)-int(first))%7 + 7*(wd.N-1)
+}
+
+func daysIn(year int, m time.Month) int {
+	return time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func sameZone(a, b time.Time) bool {
+	an, ao := a.Zone()
+	bn, bo := b.Zone()
+	return an == bn && ao == bo
+}
+
+// formatOffset formats a UTC offset in seconds as required by TZOFFSETFROM and TZOFFSETTO,
+// e.g. "+0100" or "-0930".
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	s := fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset/60%60)
+	if offset%60 != 0 {
+		s += fmt.Sprintf("%02d", offset%60)
+	}
+	return s
+}
@@ -0,0 +1,267 @@
+// Package money pairs decimal amounts with ISO 4217 currencies. Operations on two amounts refuse to
+// mix currencies, conversions and allocations are done in the minor units of the currency (e.g. cents)
+// so that no cent is lost or invented.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/raceresult/go-model/decimal"
+)
+
+var (
+	// ErrCurrencyMismatch is returned when amounts of different currencies are combined.
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	// ErrOverflow is returned when the result is out of the range of decimal.Decimal.
+	ErrOverflow = errors.New("amount out of range")
+)
+
+// Money is an amount in a certain currency.
+type Money struct {
+	Amount   decimal.Decimal
+	Currency Currency
+}
+
+// New creates a Money value.
+func New(amount decimal.Decimal, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// FromMinor creates a Money value from an amount in minor units, e.g. cents.
+func FromMinor(units int64, currency Currency) Money {
+	return Money{Amount: decimal.Decimal(units) * minorStep(currency), Currency: currency}
+}
+
+// minorStep returns the Decimal value of one minor unit of the currency.
+func minorStep(c Currency) decimal.Decimal {
+	step := decimal.Decimal(decimal.Decimals)
+	for i := 0; i < c.MinorUnits() && step > 1; i++ {
+		step /= 10
+	}
+	return step
+}
+
+func (s Money) check(m Money) error {
+	if s.Currency != m.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, s.Currency, m.Currency)
+	}
+	return nil
+}
+
+// Add adds another amount of the same currency.
+func (s Money) Add(m Money) (Money, error) {
+	if err := s.check(m); err != nil {
+		return Money{}, err
+	}
+	x, ok := s.Amount.AddChecked(m.Amount)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: x, Currency: s.Currency}, nil
+}
+
+// Sub subtracts another amount of the same currency.
+func (s Money) Sub(m Money) (Money, error) {
+	if err := s.check(m); err != nil {
+		return Money{}, err
+	}
+	x, ok := s.Amount.SubChecked(m.Amount)
+	if !ok {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: x, Currency: s.Currency}, nil
+}
+
+// Cmp compares with another amount of the same currency and returns -1, 0 or 1.
+func (s Money) Cmp(m Money) (int, error) {
+	if err := s.check(m); err != nil {
+		return 0, err
+	}
+	switch {
+	case s.Amount < m.Amount:
+		return -1, nil
+	case s.Amount > m.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Neg returns the negated amount.
+func (s Money) Neg() Money {
+	return Money{Amount: -s.Amount, Currency: s.Currency}
+}
+
+// IsZero checks if the amount is zero.
+func (s Money) IsZero() bool {
+	return s.Amount == 0
+}
+
+// Minor returns the amount in minor units, e.g. cents. The amount is rounded with the given mode
+// if it has more decimals than the currency.
+func (s Money) Minor(mode decimal.RoundingMode) int64 {
+	step := minorStep(s.Currency)
+	return int64(s.Amount.RoundTo(step, mode) / step)
+}
+
+// Round rounds the amount to the minor units of the currency.
+func (s Money) Round(mode decimal.RoundingMode) Money {
+	return Money{Amount: s.Amount.RoundTo(minorStep(s.Currency), mode), Currency: s.Currency}
+}
+
+// Mult multiplies the amount, e.g. by a quantity or tax rate, and rounds the result to the minor
+// units of the currency.
+func (s Money) Mult(d decimal.Decimal, mode decimal.RoundingMode) (Money, error) {
+	x := new(big.Rat).Mul(decimalRat(s.Amount), decimalRat(d))
+	return fromRat(x, s.Currency, mode)
+}
+
+// Convert converts the amount into another currency by multiplying with the exchange rate, as stored
+// in the ExchangeRate fields of the pay package, and rounds the result to the minor units of the
+// target currency. The rate is used with the decimal digits of its shortest representation, e.g. 1.1
+// is exactly 11/10.
+func (s Money) Convert(to Currency, rate float64, mode decimal.RoundingMode) (Money, error) {
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return Money{}, fmt.Errorf("invalid exchange rate %v", rate)
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(rate, 'g', -1, 64))
+	return fromRat(r.Mul(r, decimalRat(s.Amount)), to, mode)
+}
+
+// decimalRat converts a Decimal to a big.Rat.
+func decimalRat(d decimal.Decimal) *big.Rat {
+	return big.NewRat(int64(d), decimal.Decimals)
+}
+
+// fromRat rounds a value to the minor units of the currency.
+func fromRat(x *big.Rat, c Currency, mode decimal.RoundingMode) (Money, error) {
+	step := minorStep(c)
+	units := new(big.Rat).Mul(x, big.NewRat(decimal.Decimals/int64(step), 1))
+	n := roundRat(units, mode)
+	n.Mul(n, big.NewInt(int64(step)))
+	if !n.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: decimal.Decimal(n.Int64()), Currency: c}, nil
+}
+
+// roundRat rounds a rational number to an integer.
+func roundRat(x *big.Rat, mode decimal.RoundingMode) *big.Int {
+	q, m := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if m.Sign() == 0 {
+		return q
+	}
+	sign := x.Sign()
+	half := new(big.Int).Lsh(m, 1).CmpAbs(x.Denom())
+
+	var away bool
+	switch mode {
+	case decimal.HalfUp:
+		away = half >= 0
+	case decimal.HalfEven:
+		away = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case decimal.Up:
+		away = true
+	case decimal.Floor:
+		away = sign < 0
+	case decimal.Ceiling:
+		away = sign > 0
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Allocate divides the amount into parts proportional to the ratios, e.g. for tax rates or group
+// members. The parts are multiples of the minor unit of the currency (or of 0.0001 if the amount
+// itself is not) and always add up to the amount: units which remain after rounding down are
+// assigned to the parts with the largest remainders, on ties to the earlier part.
+func (s Money) Allocate(ratios ...decimal.Decimal) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("no ratios")
+	}
+	total := new(big.Int)
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("negative ratio")
+		}
+		total.Add(total, big.NewInt(int64(r)))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("sum of ratios is zero")
+	}
+
+	step := minorStep(s.Currency)
+	if s.Amount%step != 0 {
+		step = 1
+	}
+	units := big.NewInt(int64(s.Amount / step))
+	neg := units.Sign() < 0
+	units.Abs(units)
+
+	type share struct {
+		units *big.Int
+		rem   *big.Int
+	}
+	shares := make([]share, len(ratios))
+	rest := new(big.Int).Set(units)
+	for i, r := range ratios {
+		q, m := new(big.Int).QuoRem(new(big.Int).Mul(units, big.NewInt(int64(r))), total, new(big.Int))
+		shares[i] = share{units: q, rem: m}
+		rest.Sub(rest, q)
+	}
+
+	// the rest is smaller than the number of parts
+	order := append([]share(nil), shares...)
+	sort.SliceStable(order, func(i, j int) bool { return order[i].rem.Cmp(order[j].rem) > 0 })
+	for i := 0; int64(i) < rest.Int64(); i++ {
+		order[i].units.Add(order[i].units, big.NewInt(1))
+	}
+
+	parts := make([]Money, len(shares))
+	for i, sh := range shares {
+		x := sh.units.Mul(sh.units, big.NewInt(int64(step)))
+		if neg {
+			x.Neg(x)
+		}
+		parts[i] = Money{Amount: decimal.Decimal(x.Int64()), Currency: s.Currency}
+	}
+	return parts, nil
+}
+
+// Split divides the amount into n parts which differ by at most one minor unit, see Allocate.
+func (s Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, errors.New("number of parts must be positive")
+	}
+	ratios := make([]decimal.Decimal, n)
+	for i := range ratios {
+		ratios[i] = decimal.Decimals
+	}
+	return s.Allocate(ratios...)
+}
+
+// String returns the amount with at least the number of decimals of the currency and the currency code, e.g. "12.50 EUR".
+func (s Money) String() string {
+	str := s.Amount.ToString()
+	if n := s.Currency.MinorUnits(); n > 0 {
+		p := strings.IndexByte(str, '.')
+		if p < 0 {
+			str += "."
+			p = len(str) - 1
+		}
+		if missing := n - (len(str) - p - 1); missing > 0 {
+			str += strings.Repeat("0", missing)
+		}
+	}
+	if s.Currency == "" {
+		return str
+	}
+	return str + " " + string(s.Currency)
+}
@@ -0,0 +1,137 @@
+package money
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func eur(f float64) Money {
+	return New(decimal.FromFloat(f), "EUR")
+}
+
+func TestCurrency(t *testing.T) {
+	c, err := ParseCurrency(" eur ")
+	assert.NoError(t, err)
+	assert.Equal(t, Currency("EUR"), c)
+	_, err = ParseCurrency("EURO")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	assert.Equal(t, 2, Currency("EUR").MinorUnits())
+	assert.Equal(t, 0, Currency("JPY").MinorUnits())
+	assert.Equal(t, 3, Currency("KWD").MinorUnits())
+	assert.Equal(t, 4, Currency("CLF").MinorUnits())
+	assert.False(t, Currency("XXX").IsValid())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	x, err := eur(10.5).Add(eur(0.25))
+	assert.NoError(t, err)
+	assert.Equal(t, eur(10.75), x)
+
+	x, err = eur(10.5).Sub(eur(11))
+	assert.NoError(t, err)
+	assert.Equal(t, eur(-0.5), x)
+
+	_, err = eur(1).Add(New(decimal.FromInt(1), "USD"))
+	assert.True(t, errors.Is(err, ErrCurrencyMismatch))
+	_, err = eur(1).Cmp(New(decimal.FromInt(1), "USD"))
+	assert.Error(t, err)
+	c, _ := eur(1).Cmp(eur(2))
+	assert.Equal(t, -1, c)
+
+	_, err = New(decimal.Max, "EUR").Add(eur(1))
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	assert.Equal(t, int64(1235), eur(12.345).Minor(decimal.HalfUp))
+	assert.Equal(t, int64(1234), eur(12.345).Minor(decimal.HalfEven))
+	assert.Equal(t, eur(12.34), eur(12.345).Round(decimal.Down))
+	assert.Equal(t, FromMinor(1234, "EUR"), eur(12.34))
+	assert.Equal(t, New(decimal.FromInt(1234), "JPY"), FromMinor(1234, "JPY"))
+
+	x, err = eur(19.99).Mult(decimal.FromFloat(0.19), decimal.HalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, eur(3.80), x)
+}
+
+func TestMoney_Convert(t *testing.T) {
+	x, err := eur(100).Convert("USD", 1.1, decimal.Ceiling)
+	assert.NoError(t, err)
+	assert.Equal(t, New(decimal.FromInt(110), "USD"), x)
+
+	x, err = eur(10).Convert("JPY", 161.235, decimal.HalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, New(decimal.FromInt(1612), "JPY"), x)
+
+	x, err = eur(10).Convert("JPY", 161.25, decimal.HalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, New(decimal.FromInt(1612), "JPY"), x)
+
+	x, err = eur(-10).Convert("CHF", 0.93333, decimal.Floor)
+	assert.NoError(t, err)
+	assert.Equal(t, New(decimal.FromFloat(-9.34), "CHF"), x)
+
+	_, err = eur(10).Convert("USD", 0, decimal.HalfUp)
+	assert.Error(t, err)
+	_, err = New(decimal.Max, "EUR").Convert("USD", 2, decimal.HalfUp)
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	parts, err := eur(100).Split(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{eur(33.34), eur(33.33), eur(33.33)}, parts)
+
+	parts, err = eur(-100).Split(3)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{eur(-33.34), eur(-33.33), eur(-33.33)}, parts)
+
+	// 19% and 7% tax parts, the remaining cent goes to the largest remainder
+	parts, err = eur(10).Allocate(decimal.FromInt(19), decimal.FromInt(7), decimal.FromInt(74))
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{eur(1.9), eur(0.7), eur(7.4)}, parts)
+
+	parts, err = eur(0.05).Allocate(decimal.FromInt(3), decimal.FromInt(7))
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{eur(0.02), eur(0.03)}, parts)
+
+	parts, err = New(decimal.FromInt(1000), "JPY").Split(6)
+	assert.NoError(t, err)
+	assert.Equal(t, decimal.FromInt(167), parts[0].Amount)
+	assert.Equal(t, decimal.FromInt(166), parts[5].Amount)
+
+	// amounts with more decimals than the currency are split in units of 0.0001
+	parts, err = eur(0.0005).Split(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []Money{eur(0.0003), eur(0.0002)}, parts)
+
+	for _, m := range []Money{eur(123.45), New(decimal.Min, "EUR"), New(decimal.Max, "EUR")} {
+		parts, err = m.Allocate(1, 2, 3, 0, 7)
+		assert.NoError(t, err)
+		sum := Money{Currency: "EUR"}
+		for _, p := range parts {
+			sum.Amount += p.Amount
+		}
+		assert.Equal(t, m, sum)
+		assert.True(t, parts[3].IsZero())
+	}
+
+	_, err = eur(1).Allocate()
+	assert.Error(t, err)
+	_, err = eur(1).Allocate(0, 0)
+	assert.Error(t, err)
+	_, err = eur(1).Allocate(-1, 2)
+	assert.Error(t, err)
+	_, err = eur(1).Split(0)
+	assert.Error(t, err)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "12.50 EUR", eur(12.5).String())
+	assert.Equal(t, "-12.00 EUR", eur(-12).String())
+	assert.Equal(t, "12.505 EUR", eur(12.505).String())
+	assert.Equal(t, "1612 JPY", New(decimal.FromInt(1612), "JPY").String())
+	assert.Equal(t, "1.500 KWD", New(decimal.FromFloat(1.5), "KWD").String())
+}
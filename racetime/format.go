@@ -0,0 +1,399 @@
+package racetime
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/raceresult/go-model/decimal"
+)
+
+type tokenKind int
+
+const (
+	tokenLiteral tokenKind = iota
+	tokenSign
+	tokenDays
+	tokenHours
+	tokenMinutes
+	tokenSeconds
+	tokenFraction
+)
+
+// token is a single placeholder or literal text of a format.
+type token struct {
+	kind     tokenKind
+	width    int
+	optional bool
+	text     string
+}
+
+// size returns the size of the unit of the token in 1/10000 seconds.
+func (s token) size() decimal.Decimal {
+	switch s.kind {
+	case tokenDays:
+		return 86400 * decimal.Decimals
+	case tokenHours:
+		return 3600 * decimal.Decimals
+	case tokenMinutes:
+		return 60 * decimal.Decimals
+	case tokenSeconds:
+		return decimal.Decimals
+	case tokenFraction:
+		x := decimal.Decimal(decimal.Decimals)
+		for i := 0; i < s.width; i++ {
+			x /= 10
+		}
+		return x
+	}
+	return 0
+}
+
+func (s token) isUnit() bool {
+	return s.kind >= tokenDays
+}
+
+// Format is a parsed time format string.
+type Format struct {
+	src    string
+	tokens []token
+}
+
+// ParseFormat parses a time format string such as "h:mm:ss.kk".
+func ParseFormat(s string) (Format, error) {
+	f := Format{src: s}
+	last := tokenLiteral
+	for i := 0; i < len(s); {
+		c := s[i]
+		n := 1
+		for i+n < len(s) && s[i+n] == c {
+			n++
+		}
+		var t token
+		switch c {
+		case 'd':
+			t = token{kind: tokenDays, width: n}
+		case 'h', 'H':
+			t = token{kind: tokenHours, width: n, optional: c == 'H'}
+		case 'm', 'M':
+			t = token{kind: tokenMinutes, width: n, optional: c == 'M'}
+		case 's':
+			t = token{kind: tokenSeconds, width: n}
+		case 'k':
+			if n > 4 {
+				return Format{}, errors.New("at most four decimals are supported")
+			}
+			t = token{kind: tokenFraction, width: n}
+		case '+':
+			t = token{kind: tokenSign}
+			n = 1
+		case '"':
+			p := strings.IndexByte(s[i+1:], '"')
+			if p < 0 {
+				return Format{}, errors.New("unterminated literal in time format")
+			}
+			f.addLiteral(s[i+1 : i+1+p])
+			i += p + 2
+			continue
+		default:
+			f.addLiteral(s[i : i+n])
+			i += n
+			continue
+		}
+		if t.isUnit() {
+			if n := len(f.tokens); n > 0 && f.tokens[n-1].isUnit() {
+				return Format{}, errors.New("units of time format must be separated by literal text")
+			}
+			if t.optional && t.width > 1 {
+				return Format{}, errors.New("optional units cannot be padded")
+			}
+			if last != tokenLiteral && t.kind != last+1 {
+				return Format{}, errors.New("units of time format must be contiguous and descending")
+			}
+			if last == tokenLiteral && t.kind == tokenFraction {
+				return Format{}, errors.New("decimals require seconds")
+			}
+			last = t.kind
+		}
+		f.tokens = append(f.tokens, t)
+		i += n
+	}
+	if last == tokenLiteral {
+		return Format{}, errors.New("time format without units")
+	}
+	required := false
+	for _, t := range f.tokens {
+		if t.kind == tokenLiteral && strings.ContainsAny(t.text, "0123456789") {
+			return Format{}, errors.New("literal text of time format must not contain digits")
+		}
+		required = required || t.isUnit() && !t.optional
+	}
+	if !required {
+		return Format{}, errors.New("time format without required units")
+	}
+	return f, nil
+}
+
+// MustParseFormat is like ParseFormat but panics if the format cannot be parsed.
+func MustParseFormat(s string) Format {
+	f, err := ParseFormat(s)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func (s *Format) addLiteral(text string) {
+	if text == "" {
+		return
+	}
+	if n := len(s.tokens); n > 0 && s.tokens[n-1].kind == tokenLiteral {
+		s.tokens[n-1].text += text
+		return
+	}
+	s.tokens = append(s.tokens, token{kind: tokenLiteral, text: text})
+}
+
+// String returns the format string.
+func (s Format) String() string {
+	return s.src
+}
+
+// Unit returns the smallest unit of the format in seconds, e.g. 0.1 for "h:mm:ss.k".
+func (s Format) Unit() decimal.Decimal {
+	var unit decimal.Decimal
+	for _, t := range s.tokens {
+		if t.isUnit() {
+			unit = t.size()
+		}
+	}
+	return unit
+}
+
+// Format formats the time (in seconds) after rounding it to the smallest unit of the format.
+func (s Format) Format(t decimal.Decimal, r Rounding) string {
+	t = Round(t, s.Unit(), r)
+	neg := t < 0
+	if neg {
+		t = -t
+	}
+
+	var sb strings.Builder
+	if neg && !s.hasSign() {
+		sb.WriteByte('-')
+	}
+	var prev decimal.Decimal // size of the previous unit, 0 for the largest unit
+	leading := true          // no unit written yet
+	skip := false            // skip literals after an omitted unit
+	unpadded := false        // pad the next unit to one digit only
+	for _, tok := range s.tokens {
+		switch tok.kind {
+		case tokenLiteral:
+			if !skip {
+				sb.WriteString(tok.text)
+			}
+		case tokenSign:
+			if neg {
+				sb.WriteByte('-')
+			} else {
+				sb.WriteByte('+')
+			}
+		default:
+			size := tok.size()
+			v := t
+			if prev != 0 {
+				v %= prev
+			}
+			v /= size
+			prev = size
+
+			if tok.optional && leading && v == 0 {
+				skip = true
+				unpadded = true
+				continue
+			}
+			width := tok.width
+			if unpadded {
+				width = 1
+			}
+			skip = false
+			unpadded = false
+			leading = false
+
+			str := strconv.FormatInt(int64(v), 10)
+			if len(str) < width {
+				sb.WriteString(strings.Repeat("0", width-len(str)))
+			}
+			sb.WriteString(str)
+		}
+	}
+	return sb.String()
+}
+
+func (s Format) hasSign() bool {
+	for _, t := range s.tokens {
+		if t.kind == tokenSign {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses a time written in the format, e.g. "1:02:03.4" for "h:mm:ss.k" or "1d 01:01:01" for
+// `d"d "hh:mm:ss`. Literal text of the format has to match exactly, digits of the units are not
+// limited to their width and optional units may be omitted as by Format.
+//
+// Input not matching the format is parsed as parts separated by colons with an optional sign, e.g.
+// "1:02:03.4" or "-0:30". The last part corresponds to the smallest unit of the format without
+// decimals and may have decimals separated by a point or comma. Literal text is not allowed then.
+func (s Format) Parse(str string) (decimal.Decimal, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return 0, errors.New("empty time")
+	}
+	neg := false
+	formatted := str
+	if !s.hasSign() && formatted[0] == '-' {
+		neg = true
+		formatted = formatted[1:]
+	}
+	if res, n, ok := s.match(formatted, 0, true, false); ok {
+		if neg || n {
+			res = -res
+		}
+		return res, nil
+	}
+	return s.parseParts(str)
+}
+
+// match parses str with the tokens starting at index i. leading is set as long as no unit has been
+// read, skip while the literals after an omitted unit are skipped. It returns the time, whether a
+// negative sign has been read and false if str does not match the format.
+func (s Format) match(str string, i int, leading, skip bool) (decimal.Decimal, bool, bool) {
+	var res decimal.Decimal
+	neg := false
+	for ; i < len(s.tokens); i++ {
+		tok := s.tokens[i]
+		switch tok.kind {
+		case tokenLiteral:
+			if skip {
+				continue
+			}
+			if !strings.HasPrefix(str, tok.text) {
+				return 0, false, false
+			}
+			str = str[len(tok.text):]
+		case tokenSign:
+			if str == "" || str[0] != '+' && str[0] != '-' {
+				return 0, false, false
+			}
+			neg = str[0] == '-'
+			str = str[1:]
+		default:
+			if tok.optional && leading {
+				if r, n, ok := s.match(str, i+1, true, true); ok {
+					return res + r, neg || n, true
+				}
+			}
+			n := 0
+			for n < len(str) && str[n] >= '0' && str[n] <= '9' {
+				n++
+			}
+			if n == 0 {
+				return 0, false, false
+			}
+			if tok.kind == tokenFraction {
+				v, err := parseFraction(str[:n], decimal.Decimals)
+				if err != nil {
+					return 0, false, false
+				}
+				res += v
+			} else {
+				v, err := strconv.ParseInt(str[:n], 10, 64)
+				if err != nil {
+					return 0, false, false
+				}
+				res += decimal.Decimal(v) * tok.size()
+			}
+			str = str[n:]
+			leading, skip = false, false
+		}
+	}
+	return res, neg, str == ""
+}
+
+// parseParts parses a time written as parts separated by colons.
+func (s Format) parseParts(str string) (decimal.Decimal, error) {
+	neg := false
+	if str != "" && (str[0] == '-' || str[0] == '+') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+	if str == "" {
+		return 0, errors.New("empty time")
+	}
+
+	// smallest unit without decimals
+	unit := tokenSeconds
+	for _, t := range s.tokens {
+		if t.isUnit() && t.kind != tokenFraction {
+			unit = t.kind
+		}
+	}
+
+	parts := strings.Split(str, ":")
+	var res decimal.Decimal
+	for i := len(parts) - 1; i >= 0; i-- {
+		if unit < tokenDays {
+			return 0, errors.New("too many parts in time " + str)
+		}
+		size := token{kind: unit}.size()
+		p := parts[i]
+		if i == len(parts)-1 {
+			if x := strings.IndexAny(p, ".,"); x >= 0 {
+				frac, err := parseFraction(p[x+1:], size)
+				if err != nil {
+					return 0, err
+				}
+				res += frac
+				p = p[:x]
+			}
+		}
+		v, err := parseDigits(p)
+		if err != nil {
+			return 0, err
+		}
+		res += decimal.Decimal(v) * size
+		unit--
+	}
+	if neg {
+		res = -res
+	}
+	return res, nil
+}
+
+// parseDigits parses a non-negative integer consisting of digits only.
+func parseDigits(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("missing digits in time")
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, errors.New("invalid character in time: " + s)
+		}
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// parseFraction parses the decimals of a unit of the given size. Digits beyond 1/10000 seconds are truncated.
+func parseFraction(s string, size decimal.Decimal) (decimal.Decimal, error) {
+	if _, err := parseDigits(s); err != nil {
+		return 0, err
+	}
+	var res, div decimal.Decimal = 0, 1
+	for i := 0; i < len(s) && div < size; i++ {
+		res = res*10 + decimal.Decimal(s[i]-'0')
+		div *= 10
+	}
+	return res * size / div, nil
+}
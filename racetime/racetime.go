@@ -0,0 +1,63 @@
+// Package racetime formats and parses race times according to the time format strings used in
+// Contest.TimeFormat, Result.TimeFormat and TeamScore.TimeFormat, e.g. "h:mm:ss.k".
+//
+// Race times are decimal.Decimal seconds (variant.RDecimal), so they can be added to and subtracted
+// from each other and from datetimes with the usual variant arithmetic. Negative times and times
+// of 24 hours or more are supported.
+//
+// A format string consists of the following placeholders, all other characters are copied:
+//
+//	d           days
+//	h, hh       hours, at least one/two digits
+//	H           hours, omitted together with the following separator if zero
+//	m, mm       minutes, at least one/two digits
+//	M           minutes, omitted together with the following separator if zero
+//	s, ss       seconds, at least one/two digits
+//	k - kkkk    tenths, hundredths, thousandths or ten thousandths of a second
+//	+           sign, also shown for positive times
+//	"..."       literal text
+//
+// The largest unit of the format is not limited, e.g. 25 hours are shown as "25:00:00" with "h:mm:ss"
+// and as "1500:00" with "m:ss". Negative times are shown with a leading minus sign unless the format
+// contains a + placeholder defining the position of the sign.
+package racetime
+
+import (
+	"github.com/raceresult/go-model/decimal"
+)
+
+// Rounding defines how a time is rounded to the smallest unit of a format, as stored in TimeRounding.
+type Rounding int
+
+// Constants for the rounding modes.
+const (
+	RoundDown    Rounding = 0 // truncate
+	RoundUp      Rounding = 1 // round up to the next unit unless the time is already a multiple
+	RoundNearest Rounding = 2 // round half up
+)
+
+// Round rounds the time to a multiple of unit (in seconds) using the given rounding mode.
+// Negative times are rounded symmetrically, i.e. RoundDown rounds towards zero.
+func Round(t decimal.Decimal, unit decimal.Decimal, r Rounding) decimal.Decimal {
+	if unit <= 0 {
+		return t
+	}
+	if t < 0 {
+		if t == decimal.Min {
+			return t
+		}
+		return -Round(-t, unit, r)
+	}
+	rest := t % unit
+	switch r {
+	case RoundUp:
+		if rest != 0 {
+			return t - rest + unit
+		}
+	case RoundNearest:
+		if 2*rest >= unit {
+			return t - rest + unit
+		}
+	}
+	return t - rest
+}
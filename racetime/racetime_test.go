@@ -0,0 +1,158 @@
+package racetime
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/raceresult/go-model/variant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	tenth := decimal.FromFloat(0.1)
+	assert.Equal(t, decimal.FromFloat(1.2), Round(decimal.FromFloat(1.26), tenth, RoundDown))
+	assert.Equal(t, decimal.FromFloat(1.3), Round(decimal.FromFloat(1.21), tenth, RoundUp))
+	assert.Equal(t, decimal.FromFloat(1.2), Round(decimal.FromFloat(1.2), tenth, RoundUp))
+	assert.Equal(t, decimal.FromFloat(1.3), Round(decimal.FromFloat(1.25), tenth, RoundNearest))
+	assert.Equal(t, decimal.FromFloat(1.2), Round(decimal.FromFloat(1.2499), tenth, RoundNearest))
+	assert.Equal(t, decimal.FromFloat(-1.2), Round(decimal.FromFloat(-1.26), tenth, RoundDown))
+	assert.Equal(t, decimal.FromFloat(-1.3), Round(decimal.FromFloat(-1.21), tenth, RoundUp))
+}
+
+func TestFormat_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		t      float64
+		r      Rounding
+		want   string
+	}{
+		{"h:mm:ss", 3723, RoundDown, "1:02:03"},
+		{"hh:mm:ss", 3723, RoundDown, "01:02:03"},
+		{"h:mm:ss", 3723.9, RoundDown, "1:02:03"},
+		{"h:mm:ss", 3723.9, RoundUp, "1:02:04"},
+		{"h:mm:ss", 3723.5, RoundNearest, "1:02:04"},
+		{"h:mm:ss.k", 3723.45, RoundDown, "1:02:03.4"},
+		{"h:mm:ss.kk", 3723.45, RoundDown, "1:02:03.45"},
+		{"h:mm:ss,kkk", 3723.45, RoundDown, "1:02:03,450"},
+		{"m:ss.k", 3723.45, RoundDown, "62:03.4"},
+		{"s.kk", 83.456, RoundNearest, "83.46"},
+		{"h:mm", 3659, RoundUp, "1:01"},
+		{"h:mm:ss", 90000, RoundDown, "25:00:00"},
+		{"d\"d \"hh:mm:ss", 90061, RoundDown, "1d 01:01:01"},
+		{"H:mm:ss", 3723, RoundDown, "1:02:03"},
+		{"H:mm:ss", 123, RoundDown, "2:03"},
+		{"H:M:ss.k", 3.25, RoundDown, "3.2"},
+		{"h:mm:ss", -3723.9, RoundDown, "-1:02:03"},
+		{"h:mm:ss", -3723.9, RoundUp, "-1:02:04"},
+		{"h:mm:ss", -0.4, RoundDown, "0:00:00"},
+		{"H:mm:ss.k", -12.34, RoundDown, "-0:12.3"},
+		{"+m:ss", 65, RoundDown, "+1:05"},
+		{"+m:ss", -65, RoundDown, "-1:05"},
+		{"\"+\"s\"s\"", 12, RoundDown, "+12s"},
+	}
+	for _, tt := range tests {
+		f, err := ParseFormat(tt.format)
+		if assert.NoError(t, err, tt.format) {
+			assert.Equal(t, tt.want, f.Format(decimal.FromFloat(tt.t), tt.r), "%s %v", tt.format, tt.t)
+		}
+	}
+}
+
+func TestParseFormat_Errors(t *testing.T) {
+	for _, s := range []string{"", "abc", "h:ss", "ss:mm", "kk", "s.kkkkk", "HH:mm", "h\"abc", "hmm", "h:mms", "h\"\"mm", "h\"1\"mm", "H", "H:M"} {
+		_, err := ParseFormat(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestFormat_Parse(t *testing.T) {
+	tests := []struct {
+		format string
+		str    string
+		want   float64
+	}{
+		{"h:mm:ss", "1:02:03", 3723},
+		{"h:mm:ss", "2:03", 123},
+		{"h:mm:ss", "3", 3},
+		{"h:mm:ss.k", "1:02:03.45", 3723.45},
+		{"h:mm:ss.k", "1:02:03,45678", 3723.4567},
+		{"h:mm:ss", "-1:02:03", -3723},
+		{"h:mm:ss", " +25:00:00 ", 90000},
+		{"h:mm", "1:30", 5400},
+		{"h:mm", "1:30.5", 5430},
+		{"m:ss", "1:00:00:00", 86400},
+		{"d\"d \"hh:mm:ss", "1d 01:01:01", 90061},
+		{"d\"d \"hh:mm:ss", "-1d 01:01:01", -90061},
+		{"d\"d \"hh:mm:ss", "1:01:01:01", 90061},
+		{"\"+\"s\"s\"", "+12s", 12},
+		{"\"+\"s\"s\"", "-+12s", -12},
+		{"H:mm:ss", "2:03", 123},
+		{"H:M:ss.k", "3.2", 3.2},
+		{"+m:ss", "-1:05", -65},
+		{"h\"h \"mm\"m\"", "1h 05m", 3900},
+	}
+	for _, tt := range tests {
+		got, err := MustParseFormat(tt.format).Parse(tt.str)
+		if assert.NoError(t, err, tt.str) {
+			assert.Equal(t, decimal.FromFloat(tt.want), got, tt.str)
+		}
+	}
+
+	for _, s := range []string{"", "-", "1:x", "1::2", "1:02.", "1:2:3:4:5"} {
+		_, err := MustParseFormat("h:mm:ss").Parse(s)
+		assert.Error(t, err, s)
+	}
+	for _, s := range []string{"1d01:01:01", "1x 01:01:01", "1d 01:01:01s"} {
+		_, err := MustParseFormat("d\"d \"hh:mm:ss").Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestFormat_RoundTrip(t *testing.T) {
+	f := MustParseFormat("h:mm:ss.kkkk")
+	for _, x := range []float64{0, 0.0001, 59.9999, 3600, 86399.5, 100000.25, -7200.125} {
+		d := decimal.FromFloat(x)
+		got, err := f.Parse(f.Format(d, RoundDown))
+		assert.NoError(t, err)
+		assert.Equal(t, d, got)
+	}
+
+	// every format reads back what it writes
+	for _, format := range []string{"hh:mm:ss", "m:ss.k", "s.kk", "h:mm", "d\"d \"hh:mm:ss", "H:mm:ss", "H:M:ss.k", "+m:ss", "\"+\"s\"s\"", "\"T\"h\"h\"mm"} {
+		f := MustParseFormat(format)
+		for _, x := range []float64{0, 3.25, 65, 3723.45, 90061, -65, -90061.5} {
+			d := Round(decimal.FromFloat(x), f.Unit(), RoundDown)
+			str := f.Format(d, RoundDown)
+			got, err := f.Parse(str)
+			if assert.NoError(t, err, "%s %s", format, str) {
+				assert.Equal(t, d, got, "%s %s", format, str)
+			}
+		}
+	}
+}
+
+func TestFormat_Variant(t *testing.T) {
+	f := MustParseFormat("h:mm:ss.k")
+	start, err := f.ParseVariant("9:00:00")
+	assert.NoError(t, err)
+	finish, err := f.ParseVariant("10:02:03.45")
+	assert.NoError(t, err)
+
+	// race times are decimals and interoperate with decimal arithmetic
+	assert.Equal(t, "1:02:03.4", f.FormatVariant(variant.Minus(finish, start), RoundDown))
+	assert.Equal(t, "-1:02:03.4", f.FormatVariant(variant.Minus(start, finish), RoundDown))
+	assert.Equal(t, "1:02:13.4", f.FormatVariant(variant.Plus(variant.Minus(finish, start), variant.RDecimal(decimal.FromInt(10))), RoundDown))
+	assert.Equal(t, "0:00:10.0", f.FormatVariant(variant.RInt(10), RoundDown))
+	assert.Equal(t, "10:02:03.5", f.FormatVariant(variant.RDateTime(datetime.New(1899, 12, 30, 10, 2, 3).Add(450000000)), RoundNearest))
+	assert.Equal(t, "34:02:03.0", f.FormatVariant(variant.RDateTime(datetime.New(1899, 12, 31, 10, 2, 3)), RoundDown))
+	assert.Equal(t, "-13:57:57.0", f.FormatVariant(variant.RDateTime(datetime.New(1899, 12, 29, 10, 2, 3)), RoundDown))
+	assert.Equal(t, "1089912:00:00.0", f.FormatVariant(variant.RDateTime(datetime.New(2024, 5, 1, 0, 0, 0)), RoundDown))
+	assert.Equal(t, "0:01:00.0", f.FormatVariant(variant.RString("60"), RoundDown))
+	assert.Equal(t, "DNF", f.FormatVariant(variant.RString("DNF"), RoundDown))
+	assert.Equal(t, "", f.FormatVariant(nil, RoundDown))
+
+	v, err := f.ParseVariant(" ")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,47 @@
+package racetime
+
+import (
+	"strings"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/raceresult/go-model/variant"
+)
+
+// FormatVariant formats a variant holding a race time. Numbers are interpreted as seconds and
+// datetimes as duration since the VB zero date 1899-12-30, so that times of day stored as
+// datetimes on the zero date are formatted as such. Empty values result in an empty string and
+// other strings are returned unchanged.
+func (s Format) FormatVariant(v variant.Variant, r Rounding) string {
+	switch variant.GetType(v) {
+	case variant.TypeEmpty:
+		return ""
+	case variant.TypeRDateTime:
+		dt := variant.ToDateTime(v)
+		days := int(date.New(dt.Date()).Sub(date.ZeroDateVB))
+		secs := days*86400 + dt.Hour()*3600 + dt.Minute()*60 + dt.Second()
+		return s.Format(decimal.FromInt(secs)+decimal.Decimal(dt.Nanosecond()/100000), r)
+	case variant.TypeRString:
+		str := variant.ToString(v)
+		n, err := variant.ParseNumber(str)
+		if err != nil {
+			return str
+		}
+		return s.Format(variant.ToDecimal(n), r)
+	default:
+		return s.Format(variant.ToDecimal(v), r)
+	}
+}
+
+// ParseVariant parses a time written in the format and returns it as decimal variant,
+// so that it can be used in calculations with other race times. Empty strings result in nil.
+func (s Format) ParseVariant(str string) (variant.Variant, error) {
+	if strings.TrimSpace(str) == "" {
+		return nil, nil
+	}
+	t, err := s.Parse(str)
+	if err != nil {
+		return nil, err
+	}
+	return variant.RDecimal(t), nil
+}
@@ -3,6 +3,8 @@ package statistic
 import (
 	"github.com/raceresult/go-model/decimal"
 	"github.com/raceresult/go-model/page"
+	"github.com/raceresult/go-model/variant"
+	"golang.org/x/text/collate"
 )
 
 type Aggregation int
@@ -15,6 +17,33 @@ const (
 	SASum     Aggregation = 5
 )
 
+// Apply calculates the aggregation over the given values. Empty values are ignored.
+func (s Aggregation) Apply(values variant.RList, collator *collate.Collator) variant.Variant {
+	switch s {
+	case SACount:
+		return variant.RInt(variant.Count(values))
+	case SAMinimum:
+		return variant.Min(values, collator)
+	case SAMaximum:
+		return variant.Max(values, collator)
+	case SAMean:
+		return variant.Mean(values)
+	case SASum:
+		return variant.Sum(values)
+	default:
+		return nil
+	}
+}
+
+// ApplyGrouped calculates the aggregation per group of records having equal keys. String keys are
+// compared case-insensitively. It returns the distinct keys in order of their first appearance and
+// the corresponding results.
+func (s Aggregation) ApplyGrouped(values variant.RList, keys variant.RList, collator *collate.Collator) (variant.VariantList, variant.VariantList) {
+	return variant.GroupAggregate(values, keys, false, func(l variant.RList) variant.Variant {
+		return s.Apply(l, collator)
+	})
+}
+
 type Statistics struct {
 	Name             string `json:"StatisticName"`
 	Type             string
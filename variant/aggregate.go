@@ -0,0 +1,316 @@
+package variant
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"golang.org/x/text/collate"
+)
+
+// AggregateFunc calculates a single value from a list of values.
+type AggregateFunc func(l RList) Variant
+
+// Count returns the number of non-empty values in the list.
+func Count(l RList) int {
+	switch v := l.(type) {
+	case IntList, DecimalList, Float64List, BoolList:
+		return l.Len()
+	case StringList:
+		c := 0
+		for _, x := range v {
+			if x != "" {
+				c++
+			}
+		}
+		return c
+	case DateList:
+		c := 0
+		for _, x := range v {
+			if !x.IsZero() {
+				c++
+			}
+		}
+		return c
+	case DateTimeList:
+		c := 0
+		for _, x := range v {
+			if !x.IsZero() {
+				c++
+			}
+		}
+		return c
+	default:
+		c := 0
+		for i := 0; i < l.Len(); i++ {
+			if !isEmptyItem(l.Item(i)) {
+				c++
+			}
+		}
+		return c
+	}
+}
+
+// Sum returns the sum of all non-empty values. The sum of an IntList is an int, the sum of a
+// DecimalList is an exact Decimal. For other lists the values are added using the Plus semantics,
+// values which are not numeric are ignored. If the list contains no values, the result is empty.
+func Sum(l RList) Variant {
+	switch v := l.(type) {
+	case IntList:
+		if len(v) == 0 {
+			return nil
+		}
+		var sum int
+		for _, x := range v {
+			sum += x
+		}
+		return rInt(sum)
+	case DecimalList:
+		if len(v) == 0 {
+			return nil
+		}
+		var sum decimal.Decimal
+		for _, x := range v {
+			sum += x
+		}
+		return rDecimal(sum)
+	case Float64List:
+		if len(v) == 0 {
+			return nil
+		}
+		var sum float64
+		for _, x := range v {
+			sum += x
+		}
+		return rFloat(sum)
+	case BoolList:
+		return Sum(v.ToInt())
+	default:
+		var sum Variant
+		for i := 0; i < l.Len(); i++ {
+			x := l.Item(i)
+			if isEmptyItem(x) || !x.isNumeric() {
+				continue
+			}
+			if sum == nil {
+				sum = x.val()
+			} else if r := sum.plus(x); r != nil {
+				sum = r
+			}
+		}
+		return sum
+	}
+}
+
+// Min returns the smallest non-empty value of the list, the collator is used to compare strings and
+// may be nil. The type of the result matches the type of the list, e.g. the minimum of a DateList is a date.
+// If the list contains no values, the result is empty.
+func Min(l RList, collator *collate.Collator) Variant {
+	return extreme(l, collator, -1)
+}
+
+// Max returns the greatest non-empty value of the list, the collator is used to compare strings and
+// may be nil. The type of the result matches the type of the list, e.g. the maximum of a DateList is a date.
+// If the list contains no values, the result is empty.
+func Max(l RList, collator *collate.Collator) Variant {
+	return extreme(l, collator, 1)
+}
+
+// extreme returns the minimum (dir=-1) or maximum (dir=1) of the list.
+func extreme(l RList, collator *collate.Collator, dir int) Variant {
+	cmp := listComparer(l, collator)
+	best := -1
+	for i := 0; i < l.Len(); i++ {
+		if isEmptyListItem(l, i) {
+			continue
+		}
+		if best < 0 || cmp(i, best) == dir {
+			best = i
+		}
+	}
+	if best < 0 {
+		return nil
+	}
+	return l.Item(best)
+}
+
+// Mean returns the arithmetic mean of all non-empty values. The mean of dates is a date.
+// If the list contains no values, the result is empty.
+func Mean(l RList) Variant {
+	switch v := l.(type) {
+	case DateList:
+		days := make(Float64List, 0, len(v))
+		for _, x := range v {
+			if !x.IsZero() {
+				days = append(days, float64(x.Sub(date.ZeroDateVB)))
+			}
+		}
+		if len(days) == 0 {
+			return nil
+		}
+		return RDate(date.ZeroDateVB.Add(date.PeriodOfDays(math.Floor(ToFloat64(Mean(days))))))
+	case DateTimeList:
+		var base datetime.DateTime
+		var offset float64
+		n := 0
+		for _, x := range v {
+			if x.IsZero() {
+				continue
+			}
+			if n == 0 {
+				base = x
+			}
+			offset += float64(x.Sub(base))
+			n++
+		}
+		if n == 0 {
+			return nil
+		}
+		return rDateTime(base.Add(time.Duration(offset / float64(n))))
+	}
+
+	n := countNumeric(l)
+	if n == 0 {
+		return nil
+	}
+	return Div(Sum(l), rInt(n))
+}
+
+// Median returns the median of all non-empty numeric values, dates or datetimes. Numeric strings are
+// compared by their numeric value, so "9" is less than "10". For an even number of values, the mean of
+// the two middle values is returned. If the list contains no values, the result is empty.
+func Median(l RList) Variant {
+	switch l.(type) {
+	case IntList, DecimalList, Float64List, BoolList, DateList, DateTimeList:
+	default:
+		l = medianValues(l)
+	}
+	idx := make([]int, 0, l.Len())
+	for i := 0; i < l.Len(); i++ {
+		if !isEmptyListItem(l, i) {
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 {
+		return nil
+	}
+	cmp := listComparer(l, nil)
+	sort.SliceStable(idx, func(a, b int) bool {
+		return cmp(idx[a], idx[b]) < 0
+	})
+
+	m := len(idx) / 2
+	if len(idx)%2 == 1 {
+		return l.Item(idx[m])
+	}
+	a, b := l.Item(idx[m-1]), l.Item(idx[m])
+	switch {
+	case IsDate(a) && IsDate(b):
+		return Mean(DateList{ToDate(a), ToDate(b)})
+	case IsDateTime(a) && IsDateTime(b):
+		return Mean(DateTimeList{ToDateTime(a), ToDateTime(b)})
+	default:
+		return Mean(VariantList{a, b})
+	}
+}
+
+// medianValues returns the non-empty numeric values, dates and datetimes of the list with numeric
+// strings converted to numbers.
+func medianValues(l RList) VariantList {
+	values := make(VariantList, 0, l.Len())
+	for i := 0; i < l.Len(); i++ {
+		x := l.Item(i)
+		if isEmptyItem(x) || !x.isNumeric() {
+			continue
+		}
+		if GetType(x) == TypeRString {
+			x, _ = ParseNumber(x.toString())
+		}
+		values = append(values, x)
+	}
+	return values
+}
+
+// StdDev returns the sample standard deviation of all non-empty numeric values as float.
+// If the list contains less than two values, the result is empty.
+func StdDev(l RList) Variant {
+	var values []float64
+	switch v := l.(type) {
+	case Float64List:
+		values = v
+	case IntList, DecimalList, BoolList:
+		values = l.ToFloat64()
+	default:
+		for i := 0; i < l.Len(); i++ {
+			x := l.Item(i)
+			if !isEmptyItem(x) && x.isNumeric() {
+				values = append(values, x.toFloat64())
+			}
+		}
+	}
+	if len(values) < 2 {
+		return nil
+	}
+
+	var mean float64
+	for _, x := range values {
+		mean += x
+	}
+	mean /= float64(len(values))
+
+	var sq float64
+	for _, x := range values {
+		sq += (x - mean) * (x - mean)
+	}
+	return rFloat(math.Sqrt(sq / float64(len(values)-1)))
+}
+
+// GroupAggregate splits the list into groups of records having equal keys (see GroupBy) and applies the
+// aggregate function to each group. It returns the distinct keys in order of their first appearance and
+// the corresponding results. l and keys must have the same length.
+func GroupAggregate(l RList, keys RList, caseSensitive bool, fn AggregateFunc) (VariantList, VariantList) {
+	if l.Len() != keys.Len() {
+		panic("list and keys must have the same length")
+	}
+	groups := GroupBy(keys, caseSensitive)
+	results := NewVariantList(groups.Len())
+	for i := range results {
+		results[i] = fn(Take(l, groups.Indexes(i)))
+	}
+	return groups.Keys(), results
+}
+
+// isEmptyListItem checks if the i-th item of the list is empty without creating a Variant for typed lists.
+func isEmptyListItem(l RList, i int) bool {
+	switch v := l.(type) {
+	case IntList, DecimalList, Float64List, BoolList:
+		return false
+	case StringList:
+		return v[i] == ""
+	case DateList:
+		return v[i].IsZero()
+	case DateTimeList:
+		return v[i].IsZero()
+	default:
+		return isEmptyItem(l.Item(i))
+	}
+}
+
+// countNumeric returns the number of non-empty numeric values.
+func countNumeric(l RList) int {
+	switch l.(type) {
+	case IntList, DecimalList, Float64List, BoolList:
+		return l.Len()
+	}
+	c := 0
+	for i := 0; i < l.Len(); i++ {
+		x := l.Item(i)
+		if !isEmptyItem(x) && x.isNumeric() {
+			c++
+		}
+	}
+	return c
+}
@@ -0,0 +1,83 @@
+package variant
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	assert.Equal(t, 3, Count(IntList{0, 1, 2}))
+	assert.Equal(t, 2, Count(StringList{"a", "", "b"}))
+	assert.Equal(t, 1, Count(DateList{date.ZeroDateVB, date.New(2024, 1, 1)}))
+	assert.Equal(t, 2, Count(VariantList{nil, RInt(0), RString(""), RString("x")}))
+}
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, RInt(6), Sum(IntList{1, 2, 3}))
+	assert.Equal(t, RDecimal(decimal.FromFloat(0.3)), Sum(DecimalList{decimal.FromFloat(0.1), decimal.FromFloat(0.2)}))
+	assert.Equal(t, RInt(2), Sum(BoolList{true, false, true}))
+	assert.Equal(t, RDecimal(decimal.FromFloat(3.5)), Sum(VariantList{RInt(1), nil, RString("abc"), RDecimal(decimal.FromFloat(2.5))}))
+	assert.Nil(t, Sum(IntList{}))
+	assert.Nil(t, Sum(VariantList{nil, RString("")}))
+}
+
+func TestMinMax(t *testing.T) {
+	assert.Equal(t, RInt(-2), Min(IntList{3, -2, 5}, nil))
+	assert.Equal(t, RInt(5), Max(IntList{3, -2, 5}, nil))
+
+	dates := DateList{date.New(2024, 5, 1), date.ZeroDateVB, date.New(2023, 1, 1)}
+	assert.Equal(t, RDate(date.New(2023, 1, 1)), Min(dates, nil))
+	assert.Equal(t, RDate(date.New(2024, 5, 1)), Max(dates, nil))
+
+	assert.Equal(t, RString("a"), Min(StringList{"b", "", "a"}, nil))
+	assert.Equal(t, RInt(-1), Min(VariantList{nil, RInt(3), RInt(-1)}, nil))
+	assert.Nil(t, Max(VariantList{nil}, nil))
+}
+
+func TestMean(t *testing.T) {
+	assert.Equal(t, RFloat(2), Mean(IntList{1, 2, 3}))
+	assert.Equal(t, RFloat(1.5), Mean(IntList{1, 2}))
+	assert.Equal(t, RDecimal(decimal.FromFloat(1.5)), Mean(DecimalList{decimal.FromInt(1), decimal.FromInt(2)}))
+	assert.Equal(t, RDate(date.New(2024, 1, 2)), Mean(DateList{date.New(2024, 1, 1), date.ZeroDateVB, date.New(2024, 1, 3)}))
+	assert.Equal(t, RDateTime(datetime.New(2024, 1, 1, 12, 0, 0)), Mean(DateTimeList{datetime.New(2024, 1, 1, 10, 0, 0), datetime.New(2024, 1, 1, 14, 0, 0)}))
+	assert.Nil(t, Mean(StringList{"", ""}))
+}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, RInt(3), Median(IntList{5, 1, 3}))
+	assert.Equal(t, RFloat(3), Median(IntList{5, 1, 4, 2}))
+	assert.Equal(t, RInt(2), Median(VariantList{nil, RInt(2), RString("")}))
+	assert.Nil(t, Median(VariantList{}))
+
+	assert.Equal(t, RInt(9), Median(StringList{"9", "10", "2", "abc", ""}))
+	assert.Equal(t, RFloat(9.5), Median(StringList{"9", "10", "2", "20"}))
+	assert.Equal(t, RDate(date.New(2024, 1, 3)), Median(DateList{date.New(2024, 1, 5), {}, date.New(2024, 1, 1)}))
+	assert.Equal(t, RDate(date.New(2024, 1, 3)), Median(VariantList{RDate(date.New(2024, 1, 5)), RDate(date.New(2024, 1, 1))}))
+	assert.Equal(t, RDateTime(datetime.New(2024, 1, 1, 11, 0, 0)), Median(DateTimeList{
+		datetime.New(2024, 1, 1, 12, 0, 0), datetime.New(2024, 1, 1, 10, 0, 0), datetime.New(2024, 1, 1, 9, 0, 0), datetime.New(2024, 1, 1, 23, 0, 0),
+	}))
+}
+
+func TestStdDev(t *testing.T) {
+	assert.InDelta(t, 2.138, ToFloat64(StdDev(IntList{2, 4, 4, 4, 5, 5, 7, 9})), 0.001)
+	assert.Nil(t, StdDev(IntList{1}))
+}
+
+func TestGroupAggregate(t *testing.T) {
+	contest := IntList{1, 2, 1, 2, 3}
+	time := DecimalList{decimal.FromInt(10), decimal.FromInt(20), decimal.FromInt(30), decimal.FromInt(40), decimal.FromInt(50)}
+
+	keys, sums := GroupAggregate(time, contest, false, Sum)
+	assert.Equal(t, VariantList{RInt(1), RInt(2), RInt(3)}, keys)
+	assert.Equal(t, VariantList{RDecimal(decimal.FromInt(40)), RDecimal(decimal.FromInt(60)), RDecimal(decimal.FromInt(50))}, sums)
+
+	keys, counts := GroupAggregate(time, StringList{"a", "b", "A", "a", ""}, false, func(l RList) Variant {
+		return RInt(Count(l))
+	})
+	assert.Equal(t, VariantList{RString("a"), RString("b"), RString("")}, keys)
+	assert.Equal(t, VariantList{RInt(3), RInt(1), RInt(1)}, counts)
+}
@@ -0,0 +1,78 @@
+package variant
+
+// Plus implements v1 + v2 for Variant types. If one of the values is empty, the result is empty.
+func Plus(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.plus(v2)
+}
+
+// Minus implements v1 - v2 for Variant types. If one of the values is empty, the result is empty.
+func Minus(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.minus(v2)
+}
+
+// Mult implements v1 * v2 for Variant types. If one of the values is empty, the result is empty.
+func Mult(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.mult(v2)
+}
+
+// Div implements v1 / v2 for Variant types. Division by zero returns an empty value.
+func Div(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.div(v2)
+}
+
+// DivInt implements the integer division v1 \ v2 for Variant types. Division by zero returns an empty value.
+func DivInt(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.divInt(v2)
+}
+
+// Mod implements v1 mod v2 for Variant types. Division by zero returns an empty value.
+func Mod(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.mod(v2)
+}
+
+// Exp implements v1 ^ v2 for Variant types.
+func Exp(v1 Variant, v2 Variant) Variant {
+	if v1 == nil || v2 == nil {
+		return nil
+	}
+	return v1.exp(v2)
+}
+
+// Abs returns the absolute value of v.
+func Abs(v Variant) Variant {
+	if v == nil {
+		return nil
+	}
+	return v.abs()
+}
+
+// Negate returns -v.
+func Negate(v Variant) Variant {
+	return Mult(v, rInt(-1))
+}
+
+// IsNumeric returns true if the value can be used as a number.
+func IsNumeric(v Variant) bool {
+	if v == nil {
+		return false
+	}
+	return v.isNumeric()
+}
@@ -0,0 +1,249 @@
+package variant
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+)
+
+const codecDateTimeNanoFormat = "2006-01-02 15:04:05.999999999"
+
+// typedJSON is the JSON representation of a value encoded by MarshalTypedJSON.
+type typedJSON struct {
+	Type  string
+	Value string
+}
+
+// MarshalTypedJSON encodes the value as JSON object with the type name and the text representation
+// returned by FormatTyped, e.g. {"Type":"decimal","Value":"2.5"}. Empty values are encoded as null.
+// In contrast to the JSON encoding of the values, UnmarshalTypedJSON restores the identical value.
+func MarshalTypedJSON(v Variant) ([]byte, error) {
+	if v == nil {
+		return []byte("null"), nil
+	}
+	t, str := FormatTyped(v)
+	return json.Marshal(typedJSON{Type: t, Value: str})
+}
+
+// UnmarshalTypedJSON decodes a value encoded by MarshalTypedJSON.
+func UnmarshalTypedJSON(data []byte) (Variant, error) {
+	var x *typedJSON
+	if err := json.Unmarshal(data, &x); err != nil {
+		return nil, err
+	}
+	if x == nil {
+		return nil, nil
+	}
+	return ParseTyped(x.Type, x.Value)
+}
+
+// codecDateString formats the date as yyyy-mm-dd, including the VB zero date.
+func codecDateString(d date.Date) string {
+	if d.IsZero() {
+		return "1899-12-30"
+	}
+	return d.String()
+}
+
+// parseCodecDateTime parses a datetime with time zone in RFC 3339 format, or without time zone
+// in the format yyyy-mm-dd hh:mm:ss with optional fractional seconds.
+func parseCodecDateTime(s string) (datetime.DateTime, error) {
+	if len(s) > 10 && s[10] == 'T' {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return datetime.ZeroDate(), err
+		}
+		return datetime.FromTime(t, true), nil
+	}
+	t, err := time.ParseInLocation(codecDateTimeNanoFormat, s, time.UTC)
+	if err != nil {
+		return datetime.ZeroDate(), err
+	}
+	return datetime.FromTime(t, false), nil
+}
+
+// FormatTyped returns the type name and a text representation of the value from which ParseTyped
+// recreates the identical value.
+func FormatTyped(v Variant) (string, string) {
+	switch val := v.(type) {
+	case nil:
+		return TypeEmpty.String(), ""
+	case rFloat:
+		return TypeRFloat.String(), strconv.FormatFloat(float64(val), 'g', -1, 64)
+	case rDate:
+		return TypeRDate.String(), codecDateString(date.Date(val))
+	case rDateTime:
+		dt := datetime.DateTime(val)
+		if dt.HasZone() {
+			return TypeRDateTime.String(), dt.Time.Format(time.RFC3339Nano)
+		}
+		return TypeRDateTime.String(), dt.Time.Format(codecDateTimeNanoFormat)
+	default:
+		return v.getType().String(), v.toString()
+	}
+}
+
+// ParseTyped parses a value from the type name and text representation returned by FormatTyped.
+func ParseTyped(typeName string, s string) (Variant, error) {
+	t, ok := ParseType(typeName)
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", typeName)
+	}
+	switch t {
+	case TypeEmpty:
+		return nil, nil
+	case TypeRBool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		return RBool(b), nil
+	case TypeRString:
+		return RString(s), nil
+	case TypeRInt:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		return RInt(i), nil
+	case TypeRFloat:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		return RFloat(f), nil
+	case TypeRDecimal:
+		d, err := decimal.FromString(s)
+		if err != nil {
+			return nil, err
+		}
+		return RDecimal(d), nil
+	case TypeRDate:
+		d, err := date.ParseISO(s)
+		if err != nil {
+			return nil, err
+		}
+		return RDate(d), nil
+	case TypeRDateTime:
+		d, err := parseCodecDateTime(s)
+		if err != nil {
+			return nil, err
+		}
+		return RDateTime(d), nil
+	default:
+		return nil, errors.New("new type not implemented")
+	}
+}
+
+// EncodeJSON encodes the value as plain JSON value which DecodeJSON decodes to the identical value:
+// ints are written without and decimals with decimal point, floats in exponent notation and dates
+// and datetimes as strings in the formats of FormatTyped. With hashDates, dates and datetimes are
+// enclosed in #, e.g. "#2024-05-01#", and strings starting with # get an additional leading #.
+// Without hashDates, strings in the format of a date or datetime are decoded as date or datetime,
+// so only the encoding with hashDates restores all values. Empty values are encoded as null.
+func EncodeJSON(v Variant, hashDates bool) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return []byte("null"), nil
+	case rFloat:
+		f := float64(val)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("unsupported float value %v", f)
+		}
+		return []byte(strconv.FormatFloat(f, 'e', -1, 64)), nil
+	case rDecimal:
+		s := val.toString()
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		return []byte(s), nil
+	case rDate, rDateTime:
+		_, s := FormatTyped(v)
+		if hashDates {
+			s = "#" + s + "#"
+		}
+		return json.Marshal(s)
+	case rString:
+		s := string(val)
+		if hashDates && strings.HasPrefix(s, "#") {
+			s = "#" + s
+		}
+		return json.Marshal(s)
+	default:
+		return json.Marshal(ToInterface(v))
+	}
+}
+
+// DecodeJSON decodes a value encoded by EncodeJSON with the same hashDates setting.
+func DecodeJSON(data []byte, hashDates bool) (Variant, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+
+	switch val := x.(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		return RBool(val), nil
+	case json.Number:
+		return parseJSONNumber(string(val))
+	case string:
+		return parseJSONString(val, hashDates)
+	default:
+		return nil, fmt.Errorf("unsupported JSON value %s", data)
+	}
+}
+
+// parseJSONNumber parses a number written by EncodeJSON.
+func parseJSONNumber(s string) (Variant, error) {
+	switch {
+	case strings.ContainsAny(s, "eE"):
+		return ParseTyped(TypeRFloat.String(), s)
+	case strings.Contains(s, "."):
+		return ParseTyped(TypeRDecimal.String(), s)
+	default:
+		return ParseTyped(TypeRInt.String(), s)
+	}
+}
+
+// parseJSONString parses a string written by EncodeJSON.
+func parseJSONString(s string, hashDates bool) (Variant, error) {
+	if hashDates {
+		switch {
+		case strings.HasPrefix(s, "##"):
+			return RString(s[1:]), nil
+		case len(s) >= 2 && s[0] == '#' && s[len(s)-1] == '#':
+			s = s[1 : len(s)-1]
+			if len(s) == 10 {
+				return ParseTyped(TypeRDate.String(), s)
+			}
+			return ParseTyped(TypeRDateTime.String(), s)
+		}
+		return RString(s), nil
+	}
+	if len(s) == 10 && s[4] == '-' && s[7] == '-' {
+		if d, err := date.ParseISO(s); err == nil {
+			return RDate(d), nil
+		}
+	} else if len(s) > 10 && s[4] == '-' && s[7] == '-' {
+		if d, err := parseCodecDateTime(s); err == nil {
+			return RDateTime(d), nil
+		}
+	}
+	return RString(s), nil
+}
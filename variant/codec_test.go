@@ -0,0 +1,232 @@
+package variant
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var codecValues = VariantMap{
+	"Empty":        nil,
+	"True":         RBool(true),
+	"False":        RBool(false),
+	"String":       RString("Miller \"<&>\""),
+	"EmptyString":  RString(""),
+	"DateString":   RString("2024-05-01"),
+	"NumberString": RString("12.50"),
+	"Int":          RInt(3),
+	"NegInt":       RInt(-12),
+	"Float":        RFloat(2.5),
+	"IntFloat":     RFloat(3),
+	"LongFloat":    RFloat(0.1 + 0.2),
+	"Decimal":      RDecimal(decimal.FromFloat(2.5)),
+	"IntDecimal":   RDecimal(decimal.FromInt(3)),
+	"NegDecimal":   RDecimal(decimal.FromFloat(-0.0001)),
+	"Date":         RDate(date.New(2024, 5, 1)),
+	"ZeroDate":     RDate(date.ZeroDateVB),
+	"DateTime":     RDateTime(datetime.New(2024, 5, 1, 0, 0, 0)),
+	"DateTimeSecs": RDateTime(datetime.New(2024, 5, 1, 10, 30, 15)),
+	"DateTimeZone": RDateTime(datetime.FromTime(time.Date(2024, 5, 1, 10, 30, 15, 500000000, time.FixedZone("", 7200)), true)),
+	"DateTimeUTC":  RDateTime(datetime.FromTime(time.Date(2024, 5, 1, 10, 30, 15, 0, time.UTC), true)),
+}
+
+// assertSameValues checks that both maps have the same keys and values of the same type.
+func assertSameValues(t *testing.T, want, got VariantMap) {
+	assert.Equal(t, len(want), len(got))
+	for k, w := range want {
+		g, ok := got[k]
+		if !assert.True(t, ok, k) {
+			continue
+		}
+		assert.Equal(t, GetType(w), GetType(g), k)
+		if IsDateTime(w) {
+			assert.True(t, ToDateTime(w).Time.Equal(ToDateTime(g).Time), k)
+			assert.Equal(t, ToDateTime(w).HasZone(), ToDateTime(g).HasZone(), k)
+		} else {
+			assert.Equal(t, w, g, k)
+		}
+	}
+}
+
+func TestVariantMap_JSON(t *testing.T) {
+	bb, err := json.Marshal(VariantMap{"Decimal": RDecimal(decimal.FromInt(3)), "Float": RFloat(2.5), "Date": RDate(date.New(2024, 5, 1))})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Date":"2024-05-01","Decimal":3,"Float":2.5}`, string(bb))
+
+	var m VariantMap
+	assert.NoError(t, json.Unmarshal([]byte(`{"a":1,"b":2.5,"c":"x","d":true,"e":null}`), &m))
+	assert.Equal(t, VariantMap{"a": RFloat(1), "b": RFloat(2.5), "c": RString("x"), "d": RBool(true), "e": nil}, m)
+}
+
+func TestVariantMap_TypedJSON(t *testing.T) {
+	bb, err := codecValues.MarshalTypedJSON()
+	assert.NoError(t, err)
+
+	var m VariantMap
+	assert.NoError(t, m.UnmarshalTypedJSON(bb))
+	assertSameValues(t, codecValues, m)
+
+	bb, err = VariantMap{"Decimal": RDecimal(decimal.FromFloat(2.5)), "Empty": nil}.MarshalTypedJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Decimal":{"Type":"decimal","Value":"2.5"},"Empty":null}`, string(bb))
+
+	assert.Error(t, m.UnmarshalTypedJSON([]byte(`{"a":{"Type":"int","Value":"x"}}`)))
+	assert.Error(t, m.UnmarshalTypedJSON([]byte(`{"a":{"Type":"unknown","Value":"1"}}`)))
+	assert.NoError(t, m.UnmarshalTypedJSON([]byte(`null`)))
+	assert.Nil(t, m)
+}
+
+func TestVariantMap_XML(t *testing.T) {
+	bb, err := xml.Marshal(struct {
+		XMLName xml.Name   `xml:"Data"`
+		Values  VariantMap `xml:"Values"`
+	}{Values: codecValues})
+	assert.NoError(t, err)
+
+	var x struct {
+		Values VariantMap `xml:"Values"`
+	}
+	assert.NoError(t, xml.Unmarshal(bb, &x))
+	assertSameValues(t, codecValues, x.Values)
+}
+
+func TestVariantMap_SQL(t *testing.T) {
+	v, err := codecValues.Value()
+	assert.NoError(t, err)
+
+	var m VariantMap
+	assert.NoError(t, m.Scan(v))
+	assertSameValues(t, codecValues, m)
+
+	assert.NoError(t, m.Scan(nil))
+	assert.Nil(t, m)
+}
+
+func TestValue(t *testing.T) {
+	for k, v := range codecValues {
+		bb, err := json.Marshal(Value{V: v})
+		assert.NoError(t, err)
+		var x Value
+		assert.NoError(t, json.Unmarshal(bb, &x))
+		assertSameValues(t, VariantMap{k: v}, VariantMap{k: x.V})
+
+		bb, err = xml.Marshal(Value{V: v})
+		assert.NoError(t, err)
+		x = Value{}
+		assert.NoError(t, xml.Unmarshal(bb, &x))
+		assertSameValues(t, VariantMap{k: v}, VariantMap{k: x.V})
+
+		dv, err := Value{V: v}.Value()
+		assert.NoError(t, err)
+		x = Value{ScanType: GetType(v)}
+		assert.NoError(t, x.Scan(dv))
+		assertSameValues(t, VariantMap{k: v}, VariantMap{k: x.V})
+	}
+
+	// time.Time values have a zone unless they are in UTC
+	var dt Value
+	assert.NoError(t, dt.Scan(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+	assert.False(t, ToDateTime(dt.V).HasZone())
+	assert.NoError(t, dt.Scan(time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("", 7200))))
+	assert.True(t, ToDateTime(dt.V).HasZone())
+	dt.ScanType = TypeRDate
+	assert.NoError(t, dt.Scan(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, RDate(date.New(2024, 5, 1)), dt.V)
+
+	var x Value
+	assert.NoError(t, x.Scan([]byte("12.5")))
+	assert.Equal(t, RString("12.5"), x.V)
+	x.ScanType = TypeRDecimal
+	assert.NoError(t, x.Scan([]byte("12.5")))
+	assert.Equal(t, RDecimal(decimal.FromFloat(12.5)), x.V)
+}
+
+func TestEncodeJSON(t *testing.T) {
+	values := []Variant{
+		nil,
+		RBool(true),
+		RString("x"),
+		RString("#"),
+		RString("#2024-05-01#"),
+		RString("##"),
+		RInt(3),
+		RInt(-12),
+		RFloat(2.5),
+		RFloat(3),
+		RFloat(0.1 + 0.2),
+		RFloat(-1e-300),
+		RDecimal(decimal.FromFloat(2.5)),
+		RDecimal(decimal.FromInt(3)),
+		RDecimal(decimal.FromFloat(-0.0001)),
+		RDate(date.New(2024, 5, 1)),
+		RDate(date.ZeroDateVB),
+		RDateTime(datetime.New(2024, 5, 1, 0, 0, 0)),
+		RDateTime(datetime.New(2024, 5, 1, 10, 30, 15)),
+		RDateTime(datetime.FromTime(time.Date(2024, 5, 1, 10, 30, 15, 123456789, time.UTC), false)),
+		RDateTime(datetime.FromTime(time.Date(2024, 5, 1, 10, 30, 15, 500000000, time.FixedZone("", 7200)), true)),
+	}
+	for _, v := range values {
+		bb, err := EncodeJSON(v, true)
+		assert.NoError(t, err)
+		x, err := DecodeJSON(bb, true)
+		assert.NoError(t, err, string(bb))
+		assert.Equal(t, v, x, string(bb))
+	}
+
+	for v, exp := range map[Variant]string{
+		RInt(3):                      `3`,
+		RDecimal(decimal.FromInt(3)): `3.0`,
+		RFloat(2.5):                  `2.5e+00`,
+		RDate(date.New(2024, 5, 1)):  `"#2024-05-01#"`,
+		RString("#a"):                `"##a"`,
+		RDateTime(datetime.New(2024, 5, 1, 10, 30, 15)): `"#2024-05-01 10:30:15#"`,
+	} {
+		bb, err := EncodeJSON(v, true)
+		assert.NoError(t, err)
+		assert.Equal(t, exp, string(bb))
+	}
+
+	// without hashDates, dates are written as plain strings
+	bb, err := EncodeJSON(RDate(date.New(2024, 5, 1)), false)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2024-05-01"`, string(bb))
+	x, err := DecodeJSON(bb, false)
+	assert.NoError(t, err)
+	assert.Equal(t, RDate(date.New(2024, 5, 1)), x)
+	x, err = DecodeJSON([]byte(`"#2024-05-01#"`), false)
+	assert.NoError(t, err)
+	assert.Equal(t, RString("#2024-05-01#"), x)
+
+	_, err = EncodeJSON(RFloat(math.NaN()), true)
+	assert.Error(t, err)
+	_, err = DecodeJSON([]byte(`"#2024-05-0x#"`), true)
+	assert.Error(t, err)
+	_, err = DecodeJSON([]byte(`[1]`), true)
+	assert.Error(t, err)
+	_, err = DecodeJSON([]byte(`1 2`), true)
+	assert.Error(t, err)
+}
+
+func TestVariantMap_EncodeJSON(t *testing.T) {
+	bb, err := codecValues.EncodeJSON(true)
+	assert.NoError(t, err)
+
+	var m VariantMap
+	assert.NoError(t, m.DecodeJSON(bb, true))
+	assertSameValues(t, codecValues, m)
+
+	bb, err = VariantMap{"Date": RDate(date.New(2024, 5, 1)), "Decimal": RDecimal(decimal.FromFloat(2.5)), "Empty": nil}.EncodeJSON(true)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Date":"#2024-05-01#","Decimal":2.5,"Empty":null}`, string(bb))
+
+	assert.Error(t, m.DecodeJSON([]byte(`{"a":"#x#"}`), true))
+	assert.NoError(t, m.DecodeJSON([]byte(`null`), true))
+	assert.Nil(t, m)
+}
@@ -0,0 +1,347 @@
+package variant
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+)
+
+var (
+	variantType  = reflect.TypeOf((*Variant)(nil)).Elem()
+	decimalType  = reflect.TypeOf(decimal.Decimal(0))
+	dateType     = reflect.TypeOf(date.Date{})
+	dateTimeType = reflect.TypeOf(datetime.DateTime{})
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// FieldError describes an error decoding a single struct field.
+type FieldError struct {
+	Field string // name of the struct field, nested fields separated by dots
+	Key   string // key in the VariantMap
+	Err   error
+}
+
+func (s *FieldError) Error() string {
+	return s.Field + " (" + s.Key + "): " + s.Err.Error()
+}
+
+func (s *FieldError) Unwrap() error {
+	return s.Err
+}
+
+// FieldErrors contains the errors of all fields that could not be decoded or encoded.
+type FieldErrors []*FieldError
+
+func (s FieldErrors) Error() string {
+	msgs := make([]string, len(s))
+	for i, e := range s {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// structField describes a struct field and the key of its value in a VariantMap.
+type structField struct {
+	name      string
+	key       string
+	index     []int
+	omitEmpty bool
+}
+
+// structFields returns the fields of a struct type. The key of a field is defined by the tag
+// `variant:"Key"` and defaults to the field name. Fields tagged with `variant:"-"` and unexported
+// fields are ignored. Fields of struct types are flattened with the key as prefix, e.g. Contest.Name,
+// embedded structs are flattened without prefix. Pointers to structs are not supported and result
+// in an error unless the field is ignored.
+func structFields(t reflect.Type, namePrefix, keyPrefix string, index []int) ([]structField, error) {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		tag := f.Tag.Get("variant")
+		if tag == "-" {
+			continue
+		}
+		key, opts := tag, ""
+		if p := strings.IndexByte(tag, ','); p >= 0 {
+			key, opts = tag[:p], tag[p+1:]
+		}
+		idx := append(append([]int(nil), index...), i)
+
+		if isNestedStruct(f.Type) {
+			var nested []structField
+			var err error
+			switch {
+			case f.Anonymous && key == "":
+				nested, err = structFields(f.Type, namePrefix, keyPrefix, idx)
+			case f.PkgPath == "":
+				if key == "" {
+					key = f.Name
+				}
+				nested, err = structFields(f.Type, namePrefix+f.Name+".", keyPrefix+key+".", idx)
+			}
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Type.Kind() == reflect.Ptr && isNestedStruct(f.Type.Elem()) {
+			return nil, fmt.Errorf("field %s: pointer to struct %s not supported", namePrefix+f.Name, f.Type.Elem())
+		}
+		if key == "" {
+			key = f.Name
+		}
+		fields = append(fields, structField{
+			name:      namePrefix + f.Name,
+			key:       keyPrefix + key,
+			index:     idx,
+			omitEmpty: opts == "omitempty",
+		})
+	}
+	return fields, nil
+}
+
+// isNestedStruct checks if the type is a struct whose fields are stored separately.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != dateType && t != dateTimeType && t != timeType
+}
+
+// Decode stores the values of the map in the struct pointed to by v. Keys are matched case-insensitively,
+// see structFields for the mapping of fields to keys. Fields without value in the map are left
+// unchanged, empty values set the zero value. Values are converted to the field types with the
+// conversion functions of this package, strings which cannot be converted to a number or date result
+// in an error. Decoding continues after errors, all failed fields are returned as FieldErrors.
+func Decode(m VariantMap, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("decode target must be a non-nil pointer to a struct")
+	}
+	rv = rv.Elem()
+	fields, err := structFields(rv.Type(), "", "", nil)
+	if err != nil {
+		return err
+	}
+
+	var errs FieldErrors
+	for _, f := range fields {
+		value, ok := m.GetItem(f.key)
+		if !ok {
+			continue
+		}
+		if err := setField(rv.FieldByIndex(f.index), value); err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Key: f.key, Err: err})
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// setField converts the variant to the type of the field and stores it.
+func setField(fv reflect.Value, v Variant) error {
+	if v == nil || (IsString(v) && ToString(v) == "" && !acceptsString(fv.Type())) {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+
+	switch fv.Type() {
+	case variantType:
+		fv.Set(reflect.ValueOf(v))
+		return nil
+	case decimalType:
+		n, err := toNumber(v)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(ToDecimal(n)))
+		return nil
+	case dateType:
+		d := ToDate(v)
+		if IsString(v) && d.IsZero() {
+			return fmt.Errorf("cannot convert %q to date", ToString(v))
+		}
+		fv.Set(reflect.ValueOf(d))
+		return nil
+	case dateTimeType, timeType:
+		dt := ToDateTime(v)
+		if IsString(v) && dt.IsZero() {
+			return fmt.Errorf("cannot convert %q to datetime", ToString(v))
+		}
+		if fv.Type() == timeType {
+			fv.Set(reflect.ValueOf(dt.Time))
+		} else {
+			fv.Set(reflect.ValueOf(dt))
+		}
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Ptr:
+		x := reflect.New(fv.Type().Elem())
+		if err := setField(x.Elem(), v); err != nil {
+			return err
+		}
+		fv.Set(x)
+	case reflect.String:
+		fv.SetString(ToString(v))
+	case reflect.Bool:
+		fv.SetBool(ToBool(v))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := toNumber(v)
+		if err != nil {
+			return err
+		}
+		i := int64(ToInt(n))
+		if fv.OverflowInt(i) {
+			return fmt.Errorf("value %d out of range", i)
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toNumber(v)
+		if err != nil {
+			return err
+		}
+		i := ToInt(n)
+		if i < 0 || fv.OverflowUint(uint64(i)) {
+			return fmt.Errorf("value %d out of range", i)
+		}
+		fv.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		n, err := toNumber(v)
+		if err != nil {
+			return err
+		}
+		f := ToFloat64(n)
+		if fv.Kind() == reflect.Float32 && math.Abs(f) > math.MaxFloat32 {
+			return fmt.Errorf("value %v out of range", f)
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
+
+// acceptsString checks if an empty string can be stored in a field of the given type, otherwise
+// empty strings are treated like empty values.
+func acceptsString(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String || t == variantType
+}
+
+// toNumber parses strings to numbers and returns all other values unchanged.
+func toNumber(v Variant) (Variant, error) {
+	if !IsString(v) {
+		return v, nil
+	}
+	n, err := ParseNumber(ToString(v))
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %q to number", ToString(v))
+	}
+	return n, nil
+}
+
+// Encode creates a VariantMap from the struct v or the struct pointed to by v using the same keys as Decode.
+// Fields tagged with omitempty are omitted if they have the zero value, nil pointers and zero dates
+// result in empty values.
+func Encode(v interface{}) (VariantMap, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("encode source must be a struct or a pointer to a struct")
+	}
+
+	fields, err := structFields(rv.Type(), "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m := VariantMap{}
+	var errs FieldErrors
+	for _, f := range fields {
+		fv := rv.FieldByIndex(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		x, err := fieldVariant(fv)
+		if err != nil {
+			errs = append(errs, &FieldError{Field: f.name, Key: f.key, Err: err})
+			continue
+		}
+		m[f.key] = x
+	}
+	if errs != nil {
+		return m, errs
+	}
+	return m, nil
+}
+
+// fieldVariant converts a struct field to a variant.
+func fieldVariant(fv reflect.Value) (Variant, error) {
+	switch fv.Type() {
+	case variantType:
+		if fv.IsNil() {
+			return nil, nil
+		}
+		return fv.Interface().(Variant), nil
+	case decimalType:
+		return RDecimal(decimal.Decimal(fv.Int())), nil
+	case dateType:
+		if d := fv.Interface().(date.Date); !d.IsZero() {
+			return RDate(d), nil
+		}
+		return nil, nil
+	case dateTimeType:
+		if d := fv.Interface().(datetime.DateTime); !d.IsZero() {
+			return RDateTime(d), nil
+		}
+		return nil, nil
+	case timeType:
+		if t := fv.Interface().(time.Time); !t.IsZero() {
+			return RDateTime(datetime.FromTime(t, true)), nil
+		}
+		return nil, nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Ptr:
+		if fv.IsNil() {
+			return nil, nil
+		}
+		return fieldVariant(fv.Elem())
+	case reflect.String:
+		return RString(fv.String()), nil
+	case reflect.Bool:
+		return RBool(fv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return RInt(int(fv.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u := fv.Uint()
+		if u > math.MaxInt {
+			return nil, fmt.Errorf("value %d out of range", u)
+		}
+		return RInt(int(u)), nil
+	case reflect.Float32, reflect.Float64:
+		return RFloat(fv.Float()), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+}
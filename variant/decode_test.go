@@ -0,0 +1,179 @@
+package variant
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type decodeContest struct {
+	ID   int
+	Name string
+}
+
+type decodeBase struct {
+	Bib int
+}
+
+type decodeParticipant struct {
+	decodeBase
+	FirstName   string
+	LastName    string `variant:"Lastname"`
+	Age         uint8
+	Time        decimal.Decimal
+	Speed       float64
+	Finished    bool
+	DateOfBirth date.Date
+	Created     datetime.DateTime
+	Modified    time.Time
+	Club        *string
+	Extra       Variant
+	Contest     decodeContest
+	Team        decodeContest `variant:"T"`
+	Ignored     string        `variant:"-"`
+	Comment     string        `variant:",omitempty"`
+	internal    int
+}
+
+func TestDecode(t *testing.T) {
+	m := VariantMap{
+		"BIB":          RString("123"),
+		"firstname":    RString("Anna"),
+		"Lastname":     RString("Miller"),
+		"Age":          RDecimal(decimal.FromFloat(34.0)),
+		"Time":         RString("3600,5"),
+		"Speed":        RInt(12),
+		"Finished":     RString("1"),
+		"DateOfBirth":  RString("1990-05-01"),
+		"Created":      RDateTime(datetime.New(2024, 5, 1, 10, 0, 0)),
+		"Modified":     RString("2024-05-01 12:00:00"),
+		"Club":         RString(""),
+		"Extra":        RInt(5),
+		"Contest.ID":   RInt(2),
+		"contest.name": RString("10 km"),
+		"T.Name":       RString("Team A"),
+		"Ignored":      RString("x"),
+		"internal":     RInt(1),
+	}
+	p := decodeParticipant{Comment: "keep"}
+	assert.NoError(t, Decode(m, &p))
+
+	club := ""
+	assert.Equal(t, decodeParticipant{
+		decodeBase:  decodeBase{Bib: 123},
+		FirstName:   "Anna",
+		LastName:    "Miller",
+		Age:         34,
+		Time:        decimal.FromFloat(3600.5),
+		Speed:       12,
+		Finished:    true,
+		DateOfBirth: date.New(1990, 5, 1),
+		Created:     datetime.New(2024, 5, 1, 10, 0, 0),
+		Modified:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Club:        &club,
+		Extra:       RInt(5),
+		Contest:     decodeContest{ID: 2, Name: "10 km"},
+		Team:        decodeContest{Name: "Team A"},
+		Comment:     "keep",
+	}, p)
+
+	assert.NoError(t, Decode(VariantMap{"Club": nil, "Bib": RString(""), "Time": nil}, &p))
+	assert.Nil(t, p.Club)
+	assert.Equal(t, 0, p.Bib)
+	assert.Equal(t, decimal.Decimal(0), p.Time)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	var p decodeParticipant
+	err := Decode(VariantMap{
+		"Bib":         RString("abc"),
+		"Age":         RInt(300),
+		"DateOfBirth": RString("tomorrow"),
+		"FirstName":   RString("Anna"),
+	}, &p)
+
+	var errs FieldErrors
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs, 3)
+		fields := map[string]bool{}
+		for _, e := range errs {
+			fields[e.Field] = true
+		}
+		assert.Equal(t, map[string]bool{"Bib": true, "Age": true, "DateOfBirth": true}, fields)
+	}
+	assert.Equal(t, "Anna", p.FirstName)
+
+	assert.Error(t, Decode(VariantMap{}, p))
+	assert.Error(t, Decode(VariantMap{}, (*decodeParticipant)(nil)))
+
+	var x struct{ C chan int }
+	assert.Error(t, Decode(VariantMap{"C": RInt(1)}, &x))
+
+	// pointers to structs are rejected unless ignored
+	var withPtr struct {
+		Name    string
+		Contest *decodeContest
+	}
+	err = Decode(VariantMap{"Name": RString("Anna")}, &withPtr)
+	assert.EqualError(t, err, "field Contest: pointer to struct variant.decodeContest not supported")
+	assert.Equal(t, "", withPtr.Name)
+	_, err = Encode(withPtr)
+	assert.Error(t, err)
+
+	var ignoredPtr struct {
+		Name    string
+		Contest *decodeContest `variant:"-"`
+	}
+	assert.NoError(t, Decode(VariantMap{"Name": RString("Anna")}, &ignoredPtr))
+	assert.Equal(t, "Anna", ignoredPtr.Name)
+}
+
+func TestEncode(t *testing.T) {
+	club := "LG"
+	p := decodeParticipant{
+		decodeBase:  decodeBase{Bib: 123},
+		LastName:    "Miller",
+		Age:         34,
+		Time:        decimal.FromFloat(3600.5),
+		DateOfBirth: date.New(1990, 5, 1),
+		Club:        &club,
+		Contest:     decodeContest{ID: 2, Name: "10 km"},
+	}
+	m, err := Encode(&p)
+	assert.NoError(t, err)
+	assert.Equal(t, RInt(123), m["Bib"])
+	assert.Equal(t, RString("Miller"), m["Lastname"])
+	assert.Equal(t, RInt(34), m["Age"])
+	assert.Equal(t, RDecimal(decimal.FromFloat(3600.5)), m["Time"])
+	assert.Equal(t, RDate(date.New(1990, 5, 1)), m["DateOfBirth"])
+	assert.Equal(t, RString("LG"), m["Club"])
+	assert.Equal(t, RInt(2), m["Contest.ID"])
+	assert.Equal(t, RString(""), m["T.Name"])
+	assert.Nil(t, m["Extra"])
+	assert.Nil(t, m["Created"])
+	assert.NotContains(t, m, "Ignored")
+	assert.NotContains(t, m, "Comment")
+	assert.NotContains(t, m, "internal")
+
+	var q decodeParticipant
+	assert.NoError(t, Decode(m, &q))
+	assert.Equal(t, p, q)
+
+	_, err = Encode(3)
+	assert.Error(t, err)
+
+	m, err = Encode(struct{ A, B uint64 }{A: 7, B: math.MaxUint64})
+	var errs FieldErrors
+	if assert.True(t, errors.As(err, &errs)) {
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "B", errs[0].Field)
+	}
+	assert.Equal(t, RInt(7), m["A"])
+	assert.NotContains(t, m, "B")
+}
@@ -0,0 +1,157 @@
+package variant
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/raceresult/go-model/decimal"
+)
+
+// Key returns a canonical key of the value which can be used as map key. Two values have the same key
+// if Equals considers them equal, e.g. RInt(3), RDecimal(3) and RString("3") have the same key, and
+// with caseSensitive=false RString("abc") and RString("ABC") have the same key.
+//
+// Numbers are normalised like Equals compares floats with decimals: floats are rounded to the precision
+// of Decimal, so RFloat(0.00001) has the same key as RDecimal(0).
+//
+// As Equals is not transitive, a few exceptions apply: nil has the same key as the empty string but
+// not as 0, booleans only have the same key as other booleans, dates and datetimes have the same
+// key as their string representation, and floats which differ only beyond the fourth decimal place
+// have the same key although they are not equal to each other or to the corresponding int.
+func Key(v Variant, caseSensitive bool) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case rBool:
+		if val {
+			return "\x00true"
+		}
+		return "\x00false"
+	case rString:
+		if caseSensitive {
+			return string(val)
+		}
+		return strings.Map(foldRune, string(val))
+	case rFloat:
+		if math.Abs(float64(val)) < maxDecimalFloat {
+			return rDecimal(val.toDecimal()).toString()
+		}
+		return v.toString()
+	default:
+		return v.toString()
+	}
+}
+
+// maxDecimalFloat is the magnitude up to which a float can be converted to a Decimal.
+const maxDecimalFloat = float64(math.MaxInt64 / decimal.Decimals)
+
+// VariantGroupMap assigns values to groups of equal values.
+// Groups are numbered in order of their first appearance.
+//
+//goland:noinspection GoNameStartsWithPackageName
+type VariantGroupMap struct {
+	caseSensitive bool
+	lookup        map[string]int
+	keys          VariantList
+	indexes       [][]int
+}
+
+// NewVariantGroupMap creates a new VariantGroupMap
+func NewVariantGroupMap(caseSensitive bool) *VariantGroupMap {
+	return &VariantGroupMap{
+		caseSensitive: caseSensitive,
+		lookup:        make(map[string]int),
+	}
+}
+
+// Add adds the record with the given index and value to the matching group and returns the group number.
+func (s *VariantGroupMap) Add(v Variant, index int) int {
+	g, ok := s.Lookup(v)
+	if !ok {
+		g = s.newGroup(Key(v, s.caseSensitive), v)
+	}
+	s.indexes[g] = append(s.indexes[g], index)
+	return g
+}
+
+// Lookup returns the group number of the value.
+func (s *VariantGroupMap) Lookup(v Variant) (int, bool) {
+	g, ok := s.lookup[Key(v, s.caseSensitive)]
+	return g, ok
+}
+
+// Len returns the number of groups
+func (s *VariantGroupMap) Len() int {
+	return len(s.keys)
+}
+
+// Keys returns the first value of each group.
+func (s *VariantGroupMap) Keys() VariantList {
+	return s.keys
+}
+
+// Indexes returns the indexes of the records of the given group.
+func (s *VariantGroupMap) Indexes(group int) []int {
+	return s.indexes[group]
+}
+
+func (s *VariantGroupMap) newGroup(key string, v Variant) int {
+	g := len(s.keys)
+	s.lookup[key] = g
+	s.keys = append(s.keys, v)
+	s.indexes = append(s.indexes, nil)
+	return g
+}
+
+// GroupBy assigns all items of the list to groups of equal values.
+func GroupBy(l RList, caseSensitive bool) *VariantGroupMap {
+	m := NewVariantGroupMap(caseSensitive)
+	switch v := l.(type) {
+	case IntList:
+		// group by the int values first to avoid creating a key for each item
+		groups := make(map[int]int)
+		for i, x := range v {
+			g, ok := groups[x]
+			if !ok {
+				g = m.newGroup(Key(rInt(x), caseSensitive), rInt(x))
+				groups[x] = g
+			}
+			m.indexes[g] = append(m.indexes[g], i)
+		}
+	case StringList:
+		groups := make(map[string]int)
+		for i, x := range v {
+			g, ok := groups[x]
+			if !ok {
+				g = m.Add(rString(x), i)
+				groups[x] = g
+				continue
+			}
+			m.indexes[g] = append(m.indexes[g], i)
+		}
+	default:
+		for i := 0; i < l.Len(); i++ {
+			m.Add(l.Item(i), i)
+		}
+	}
+	return m
+}
+
+// foldRune returns the smallest rune which is equivalent under simple case folding,
+// matching the case-insensitive comparison of strings.EqualFold.
+func foldRune(r rune) rune {
+	if r < 0x80 {
+		if 'a' <= r && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < m {
+			m = f
+		}
+	}
+	return m
+}
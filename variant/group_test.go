@@ -0,0 +1,78 @@
+package variant
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey_ConsistentWithEquals(t *testing.T) {
+	values := []Variant{
+		nil,
+		RString(""),
+		RString("3"),
+		RString("abc"),
+		RString("ABC"),
+		RString("Straße"),
+		RString("STRASSE"),
+		RString("2024-05-01"),
+		RInt(0),
+		RInt(3),
+		RInt(-3),
+		RDecimal(decimal.FromInt(3)),
+		RDecimal(decimal.FromFloat(2.5)),
+		RFloat(2.5),
+		RFloat(3),
+		RDate(date.New(2024, 5, 1)),
+		RDateTime(datetime.New(2024, 5, 1, 0, 0, 0)),
+		RDateTime(datetime.New(2024, 5, 1, 10, 0, 0)),
+		RBool(true),
+		RBool(false),
+	}
+	for _, caseSensitive := range []bool{false, true} {
+		for _, v1 := range values {
+			for _, v2 := range values {
+				if Key(v1, caseSensitive) == Key(v2, caseSensitive) {
+					assert.True(t, Equals(v1, v2, caseSensitive), "same key but not equal: %v, %v", v1, v2)
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, Key(RInt(3), false), Key(RString("3"), false))
+	assert.Equal(t, Key(RDecimal(decimal.FromFloat(2.5)), false), Key(RFloat(2.5), false))
+	assert.True(t, Equals(RFloat(0.00001), RDecimal(0), false))
+	assert.Equal(t, Key(RDecimal(0), false), Key(RFloat(0.00001), false))
+	assert.Equal(t, Key(RDecimal(decimal.FromFloat(0.1)), false), Key(RFloat(0.1), false))
+	assert.Equal(t, RFloat(1e20).toString(), Key(RFloat(1e20), false))
+	assert.Equal(t, Key(RString("abc"), false), Key(RString("ABC"), false))
+	assert.NotEqual(t, Key(RString("abc"), true), Key(RString("ABC"), true))
+	assert.Equal(t, Key(nil, false), Key(RString(""), false))
+	assert.Equal(t, Key(RDate(date.New(2024, 5, 1)), false), Key(RString("2024-05-01"), false))
+}
+
+func TestGroupBy(t *testing.T) {
+	g := GroupBy(VariantList{RInt(1), RString("1"), nil, RDecimal(decimal.FromInt(2)), RString(""), RInt(2)}, false)
+	assert.Equal(t, 3, g.Len())
+	assert.Equal(t, VariantList{RInt(1), nil, RDecimal(decimal.FromInt(2))}, g.Keys())
+	assert.Equal(t, []int{0, 1}, g.Indexes(0))
+	assert.Equal(t, []int{2, 4}, g.Indexes(1))
+	assert.Equal(t, []int{3, 5}, g.Indexes(2))
+
+	g = GroupBy(StringList{"LG Nord", "lg nord", "TV Süd", "LG Nord"}, false)
+	assert.Equal(t, VariantList{RString("LG Nord"), RString("TV Süd")}, g.Keys())
+	assert.Equal(t, []int{0, 1, 3}, g.Indexes(0))
+
+	g = GroupBy(StringList{"LG Nord", "lg nord", "TV Süd", "LG Nord"}, true)
+	assert.Equal(t, 3, g.Len())
+
+	g = GroupBy(IntList{2, 1, 2}, false)
+	assert.Equal(t, VariantList{RInt(2), RInt(1)}, g.Keys())
+	assert.Equal(t, []int{0, 2}, g.Indexes(0))
+	x, ok := g.Lookup(RString("1"))
+	assert.True(t, ok)
+	assert.Equal(t, 1, x)
+}
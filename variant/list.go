@@ -66,6 +66,35 @@ type RList interface {
 	DivInt(p RList) RList
 }
 
+// CloneList returns a copy of the list. This is useful before calling one of the arithmetic
+// functions of a typed list, as these may modify the list in place.
+func CloneList(l RList) RList {
+	switch v := l.(type) {
+	case nil:
+		return nil
+	case BoolList:
+		return append(BoolList(nil), v...)
+	case IntList:
+		return append(IntList(nil), v...)
+	case Float64List:
+		return append(Float64List(nil), v...)
+	case DecimalList:
+		return append(DecimalList(nil), v...)
+	case StringList:
+		return append(StringList(nil), v...)
+	case DateList:
+		return append(DateList(nil), v...)
+	case DateTimeList:
+		return append(DateTimeList(nil), v...)
+	case VariantList:
+		return append(VariantList(nil), v...)
+	case NullList:
+		return NullList{Values: CloneList(v.Values), Empty: append(Bitmap(nil), v.Empty...)}
+	default:
+		panic("new type not implemented")
+	}
+}
+
 // RListArrayToJSON creates a JSON from an RList array
 func RListArrayToJSON(result []RList, hashDates bool) []byte {
 	R := len(result)
@@ -138,6 +138,8 @@ func (s DateList) Plus(p RList) RList {
 			v[i] = rDate(s[i]).plus(v.Item(i))
 		}
 		return v
+	case NullList:
+		return nullableOp(s, v, RList.Plus)
 	default:
 		panic("new type not implemented")
 	}
@@ -176,6 +178,8 @@ func (s DateList) Minus(p RList) RList {
 			v[i] = rDate(s[i]).minus(v.Item(i))
 		}
 		return v
+	case NullList:
+		return nullableOp(s, v, RList.Minus)
 	default:
 		panic("new type not implemented")
 	}
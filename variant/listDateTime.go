@@ -146,6 +146,8 @@ func (s DateTimeList) Plus(p RList) RList {
 			v[i] = rDateTime(s[i]).plus(v.Item(i))
 		}
 		return v
+	case NullList:
+		return nullableOp(s, v, RList.Plus)
 	default:
 		panic("new type not implemented")
 	}
@@ -194,6 +196,8 @@ func (s DateTimeList) Minus(p RList) RList {
 			v[i] = rDateTime(s[i]).minus(v.Item(i))
 		}
 		return v
+	case NullList:
+		return nullableOp(s, v, RList.Minus)
 	default:
 		panic("new type not implemented")
 	}
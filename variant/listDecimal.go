@@ -138,6 +138,8 @@ func (s DecimalList) Plus(p RList) RList {
 			result[i] = rDecimal(s[i]).plus(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Plus)
 	default:
 		panic("new type not implemented")
 	}
@@ -167,6 +169,8 @@ func (s DecimalList) Minus(p RList) RList {
 			result[i] = rDecimal(s[i]).minus(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Minus)
 	default:
 		panic("new type not implemented")
 	}
@@ -202,6 +206,8 @@ func (s DecimalList) Mult(p RList) RList {
 			result[i] = rDecimal(s[i]).mult(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Mult)
 	default:
 		panic("new type not implemented")
 	}
@@ -239,6 +245,8 @@ func (s DecimalList) Div(p RList) RList {
 			result[i] = rDecimal(s[i]).div(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Div)
 	default:
 		panic("new type not implemented")
 	}
@@ -274,6 +282,8 @@ func (s DecimalList) DivInt(p RList) RList {
 			result[i] = rDecimal(s[i]).divInt(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.DivInt)
 	default:
 		panic("new type not implemented")
 	}
@@ -308,6 +318,8 @@ func (s DecimalList) Mod(p RList) RList {
 			result[i] = rDecimal(s[i]).mod(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Mod)
 	default:
 		panic("new type not implemented")
 	}
@@ -132,6 +132,8 @@ func (s Float64List) Plus(p RList) RList {
 			result[i] = rFloat(s[i]).plus(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Plus)
 	default:
 		panic("New type not implemented")
 	}
@@ -159,6 +161,8 @@ func (s Float64List) Minus(p RList) RList {
 			result[i] = rFloat(s[i]).minus(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Minus)
 	default:
 		panic("New type not implemented")
 	}
@@ -186,6 +190,8 @@ func (s Float64List) Mult(p RList) RList {
 			result[i] = rFloat(s[i]).mult(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Mult)
 	default:
 		panic("New type not implemented")
 	}
@@ -217,6 +223,8 @@ func (s Float64List) Div(p RList) RList { //nolint:dupl
 			result[i] = rFloat(s[i]).div(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Div)
 	default:
 		panic("New type not implemented")
 	}
@@ -251,6 +259,8 @@ func (s Float64List) DivInt(p RList) RList { //nolint:dupl
 			result[i] = rFloat(s[i]).divInt(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.DivInt)
 	default:
 		panic("New type not implemented")
 	}
@@ -289,6 +299,8 @@ func (s Float64List) Mod(p RList) RList {
 			result[i] = rFloat(s[i]).mod(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Mod)
 	default:
 		panic("New type not implemented")
 	}
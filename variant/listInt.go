@@ -130,6 +130,8 @@ func (s IntList) Plus(p RList) RList {
 			result[i] = rInt(s[i]).plus(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Plus)
 	default:
 		panic("new type not implemented")
 	}
@@ -155,6 +157,8 @@ func (s IntList) Minus(p RList) RList {
 			result[i] = rInt(s[i]).minus(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Minus)
 	default:
 		panic("new type not implemented")
 	}
@@ -180,6 +184,8 @@ func (s IntList) Mult(p RList) RList {
 			result[i] = rInt(s[i]).mult(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Mult)
 	default:
 		panic("new type not implemented")
 	}
@@ -209,6 +215,8 @@ func (s IntList) Div(p RList) RList {
 			result[i] = rInt(s[i]).div(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Div)
 	default:
 		panic("new type not implemented")
 	}
@@ -241,6 +249,8 @@ func (s IntList) DivInt(p RList) RList {
 			result[i] = rInt(s[i]).divInt(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.DivInt)
 	default:
 		panic("new type not implemented")
 	}
@@ -269,6 +279,8 @@ func (s IntList) Mod(p RList) RList {
 			result[i] = rInt(s[i]).mod(v[i])
 		}
 		return result
+	case NullList:
+		return nullableOp(s, v, RList.Mod)
 	default:
 		panic("new type not implemented")
 	}
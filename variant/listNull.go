@@ -0,0 +1,340 @@
+package variant
+
+import (
+	"encoding/json"
+	"math/bits"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+)
+
+// Bitmap is a set of indexes stored as bits. The zero value is an empty set.
+type Bitmap []uint64
+
+// NewBitmap creates a bitmap with room for n indexes.
+func NewBitmap(n int) Bitmap {
+	return make(Bitmap, (n+63)/64)
+}
+
+// Get checks if the index is contained in the bitmap.
+func (s Bitmap) Get(i int) bool {
+	w := i >> 6
+	return w < len(s) && s[w]&(1<<(uint(i)&63)) != 0
+}
+
+// Set adds or removes the index. The bitmap grows if necessary.
+func (s *Bitmap) Set(i int, v bool) {
+	w := i >> 6
+	if w >= len(*s) {
+		if !v {
+			return
+		}
+		*s = append(*s, make(Bitmap, w-len(*s)+1)...)
+	}
+	if v {
+		(*s)[w] |= 1 << (uint(i) & 63)
+	} else {
+		(*s)[w] &^= 1 << (uint(i) & 63)
+	}
+}
+
+// Count returns the number of indexes in the bitmap.
+func (s Bitmap) Count() int {
+	n := 0
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// Or returns a new bitmap containing the indexes of both bitmaps.
+func (s Bitmap) Or(b Bitmap) Bitmap {
+	if len(s) < len(b) {
+		s, b = b, s
+	}
+	if len(s) == 0 {
+		return nil
+	}
+	r := append(Bitmap(nil), s...)
+	for i, w := range b {
+		r[i] |= w
+	}
+	return r
+}
+
+// NullList is a typed list with an explicit mask of empty items, so that empty values can be told
+// apart from 0, "" or the zero date. Values holds the values and can be any list type, the values
+// at empty indexes are ignored. Item returns nil for empty items, conversions return the same value
+// as the scalar conversion of nil (e.g. 0 for ToInt, "" for ToString) and the result of arithmetic
+// is empty if one of the operands is empty, as for Plus, Minus etc. on variants.
+//
+// Like the other typed lists, the arithmetic functions may modify Values in place.
+type NullList struct {
+	Values RList
+	Empty  Bitmap
+}
+
+// NewNullList creates a NullList from the values and the mask of empty items.
+func NewNullList(values RList, empty Bitmap) NullList {
+	return NullList{Values: values, Empty: empty}
+}
+
+// NullableList converts a VariantList to a NullList. If all non-empty values have the same type,
+// the values are stored in a typed list (ints and decimals are combined to decimals), otherwise in a VariantList.
+func NullableList(l VariantList) NullList {
+	t := TypeEmpty
+	var empty Bitmap
+	for i, v := range l {
+		if v == nil {
+			empty.Set(i, true)
+			continue
+		}
+		vt := v.getType()
+		switch {
+		case t == TypeEmpty:
+			t = vt
+		case t == vt:
+		case t == TypeRInt && vt == TypeRDecimal, t == TypeRDecimal && vt == TypeRInt:
+			t = TypeRDecimal
+		default:
+			return NullList{Values: append(VariantList(nil), l...), Empty: empty}
+		}
+	}
+	if t == TypeEmpty {
+		return NullList{Values: append(VariantList(nil), l...), Empty: empty}
+	}
+	return NullList{Values: ConvertList(l, t), Empty: empty}
+}
+
+// IsEmpty checks if the item at the given index is empty.
+func (s NullList) IsEmpty(index int) bool {
+	return s.Empty.Get(index)
+}
+
+// ToString converts the list into a StringList
+func (s NullList) ToString() StringList {
+	r := s.Values.ToString()
+	if _, ok := s.Values.(StringList); ok {
+		r = append(StringList(nil), r...)
+	}
+	return s.clearStrings(r)
+}
+
+// ToStringWithDateFormat converts the list into a StringList using a certain date format
+func (s NullList) ToStringWithDateFormat(df string) StringList {
+	r := s.Values.ToStringWithDateFormat(df)
+	if _, ok := s.Values.(StringList); ok {
+		r = append(StringList(nil), r...)
+	}
+	return s.clearStrings(r)
+}
+
+func (s NullList) clearStrings(r StringList) StringList {
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = ""
+		}
+	}
+	return r
+}
+
+// ToInt converts the list into an IntList
+func (s NullList) ToInt() IntList {
+	r := s.Values.ToInt()
+	if _, ok := s.Values.(IntList); ok {
+		r = append(IntList(nil), r...)
+	}
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = 0
+		}
+	}
+	return r
+}
+
+// ToFloat64 converts the list into a Float64List
+func (s NullList) ToFloat64() Float64List {
+	r := s.Values.ToFloat64()
+	if _, ok := s.Values.(Float64List); ok {
+		r = append(Float64List(nil), r...)
+	}
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = 0
+		}
+	}
+	return r
+}
+
+// ToDecimal converts the list into a DecimalList
+func (s NullList) ToDecimal() DecimalList {
+	r := s.Values.ToDecimal()
+	if _, ok := s.Values.(DecimalList); ok {
+		r = append(DecimalList(nil), r...)
+	}
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = 0
+		}
+	}
+	return r
+}
+
+// ToBool converts the list into a BoolList
+func (s NullList) ToBool() BoolList {
+	r := s.Values.ToBool()
+	if _, ok := s.Values.(BoolList); ok {
+		r = append(BoolList(nil), r...)
+	}
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = false
+		}
+	}
+	return r
+}
+
+// ToDateTime converts the list into a DateTimeList
+func (s NullList) ToDateTime() DateTimeList {
+	r := s.Values.ToDateTime()
+	if _, ok := s.Values.(DateTimeList); ok {
+		r = append(DateTimeList(nil), r...)
+	}
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = datetime.ZeroDate()
+		}
+	}
+	return r
+}
+
+// ToDate converts the list into a DateList
+func (s NullList) ToDate() DateList {
+	r := s.Values.ToDate()
+	if _, ok := s.Values.(DateList); ok {
+		r = append(DateList(nil), r...)
+	}
+	for i := range r {
+		if s.Empty.Get(i) {
+			r[i] = date.ZeroDateVB
+		}
+	}
+	return r
+}
+
+// ToVariant converts the list into a VariantList with nil for empty items
+func (s NullList) ToVariant() VariantList {
+	r := NewVariantList(s.Len())
+	for i := range r {
+		r[i] = s.Item(i)
+	}
+	return r
+}
+
+// Item returns an item of the list, nil if the item is empty
+func (s NullList) Item(index int) Variant {
+	if s.Empty.Get(index) {
+		return nil
+	}
+	return s.Values.Item(index)
+}
+
+// Len returns the length of the list
+func (s NullList) Len() int {
+	return s.Values.Len()
+}
+
+// Abs returns a list with the absolute values
+func (s NullList) Abs() RList {
+	return NullList{Values: s.Values.Abs(), Empty: s.Empty}
+}
+
+// Val returns a list having all values converted into numbers. Empty items are converted to 0 as by Val.
+func (s NullList) Val() RList {
+	r := s.Values.Val()
+	switch v := r.(type) {
+	case IntList:
+		for i := range v {
+			if s.Empty.Get(i) {
+				v[i] = 0
+			}
+		}
+	case DecimalList:
+		for i := range v {
+			if s.Empty.Get(i) {
+				v[i] = 0
+			}
+		}
+	case Float64List:
+		for i := range v {
+			if s.Empty.Get(i) {
+				v[i] = 0
+			}
+		}
+	default:
+		vl := r.ToVariant()
+		if _, ok := r.(VariantList); ok {
+			vl = append(VariantList(nil), vl...)
+		}
+		for i := range vl {
+			if s.Empty.Get(i) {
+				vl[i] = RInt(0)
+			}
+		}
+		return vl
+	}
+	return r
+}
+
+// Plus adds a list of values
+func (s NullList) Plus(p RList) RList {
+	return s.op(p, RList.Plus)
+}
+
+// Minus subtracts a list of values
+func (s NullList) Minus(p RList) RList {
+	return s.op(p, RList.Minus)
+}
+
+// Mult multiplies a list of values
+func (s NullList) Mult(p RList) RList {
+	return s.op(p, RList.Mult)
+}
+
+// Div divides a list of values
+func (s NullList) Div(p RList) RList {
+	return s.op(p, RList.Div)
+}
+
+// Mod returns modulo values
+func (s NullList) Mod(p RList) RList {
+	return s.op(p, RList.Mod)
+}
+
+// Exp calculates a ^ b
+func (s NullList) Exp(p RList) RList {
+	return s.op(p, RList.Exp)
+}
+
+// DivInt returns result of integer division
+func (s NullList) DivInt(p RList) RList {
+	return s.op(p, RList.DivInt)
+}
+
+// op applies the arithmetic function to the values, the result is empty where one of the operands is empty.
+func (s NullList) op(p RList, fn func(a, b RList) RList) RList {
+	if pn, ok := p.(NullList); ok {
+		return NullList{Values: fn(s.Values, pn.Values), Empty: s.Empty.Or(pn.Empty)}
+	}
+	return NullList{Values: fn(s.Values, p), Empty: s.Empty}
+}
+
+// nullableOp applies the arithmetic function to a typed list and the values of a NullList.
+func nullableOp(s RList, p NullList, fn func(a, b RList) RList) RList {
+	return NullList{Values: fn(s, p.Values), Empty: append(Bitmap(nil), p.Empty...)}
+}
+
+// MarshalJSON creates a JSON array like a VariantList with null for empty items.
+func (s NullList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToVariant())
+}
@@ -0,0 +1,143 @@
+package variant
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitmap(t *testing.T) {
+	var b Bitmap
+	assert.False(t, b.Get(3))
+	b.Set(3, true)
+	b.Set(130, true)
+	b.Set(500, false)
+	assert.True(t, b.Get(3))
+	assert.True(t, b.Get(130))
+	assert.False(t, b.Get(64))
+	assert.Equal(t, 2, b.Count())
+	assert.Len(t, b, 3)
+	b.Set(3, false)
+	assert.False(t, b.Get(3))
+
+	var c Bitmap
+	c.Set(1, true)
+	u := c.Or(b)
+	assert.True(t, u.Get(1))
+	assert.True(t, u.Get(130))
+	assert.False(t, c.Get(130))
+	assert.Nil(t, Bitmap(nil).Or(nil))
+	assert.Len(t, NewBitmap(65), 2)
+}
+
+func TestNullableList(t *testing.T) {
+	l := NullableList(VariantList{RInt(1), nil, RDecimal(decimal.FromFloat(2.5)), RInt(0)})
+	assert.Equal(t, DecimalList{decimal.FromInt(1), 0, decimal.FromFloat(2.5), 0}, l.Values)
+	assert.Equal(t, 4, l.Len())
+	assert.True(t, l.IsEmpty(1))
+	assert.False(t, l.IsEmpty(3))
+	assert.Nil(t, l.Item(1))
+	assert.Equal(t, RDecimal(0), l.Item(3))
+	assert.Equal(t, VariantList{RDecimal(decimal.FromInt(1)), nil, RDecimal(decimal.FromFloat(2.5)), RDecimal(0)}, l.ToVariant())
+
+	l = NullableList(VariantList{RString("a"), RInt(1), nil})
+	assert.Equal(t, VariantList{RString("a"), RInt(1), nil}, l.Values)
+	assert.Nil(t, l.Item(2))
+
+	l = NullableList(VariantList{nil, nil})
+	assert.Equal(t, 2, l.Empty.Count())
+}
+
+// assertMatchesScalar checks that the list contains the same values as the scalar function applied to all items.
+func assertMatchesScalar(t *testing.T, got RList, x, y RList, fn func(a, b Variant) Variant) {
+	assert.Equal(t, x.Len(), got.Len())
+	for i := 0; i < x.Len(); i++ {
+		want := fn(x.Item(i), y.Item(i))
+		if want == nil {
+			assert.Nil(t, got.Item(i), "index %d", i)
+		} else {
+			assert.True(t, Equals(want, got.Item(i), true), "index %d: want %v, got %v", i, want, got.Item(i))
+		}
+	}
+}
+
+func TestNullList_Arithmetic(t *testing.T) {
+	ints := NullList{Values: IntList{1, 0, 3, 4}}
+	ints.Empty.Set(1, true)
+	decs := NullList{Values: DecimalList{decimal.FromFloat(0.5), 1, 0, decimal.FromInt(2)}}
+	decs.Empty.Set(2, true)
+	others := []RList{
+		IntList{2, 2, 2, 0},
+		DecimalList{decimal.FromFloat(1.5), 1, 1, 1},
+		Float64List{0.5, 1, 1, 2},
+		VariantList{RInt(1), RInt(2), nil, RFloat(1.5)},
+		decs,
+	}
+	ops := []struct {
+		name   string
+		list   func(a, b RList) RList
+		scalar func(a, b Variant) Variant
+	}{
+		{"Plus", RList.Plus, Plus},
+		{"Minus", RList.Minus, Minus},
+		{"Mult", RList.Mult, Mult},
+		{"Div", RList.Div, Div},
+	}
+	for _, op := range ops {
+		for _, o := range others {
+			// NullList as left and right operand
+			assertMatchesScalar(t, op.list(CloneList(ints), CloneList(o)), ints, o, op.scalar)
+			assertMatchesScalar(t, op.list(CloneList(o), CloneList(ints)), o, ints, op.scalar)
+		}
+	}
+
+	abs := NullList{Values: IntList{-1, -2}}
+	abs.Empty.Set(0, true)
+	assert.Equal(t, VariantList{nil, RInt(2)}, abs.Abs().ToVariant())
+
+	val := NullList{Values: IntList{-1, -2}}
+	val.Empty.Set(0, true)
+	assert.Equal(t, VariantList{RInt(0), RInt(-2)}, val.Val().ToVariant())
+}
+
+func TestNullList_Conversion(t *testing.T) {
+	l := NullList{Values: IntList{5, 7}}
+	l.Empty.Set(0, true)
+	assert.Equal(t, IntList{0, 7}, l.ToInt())
+	assert.Equal(t, IntList{5, 7}, l.Values, "values must not be modified")
+	assert.Equal(t, StringList{"", "7"}, l.ToString())
+	assert.Equal(t, Float64List{0, 7}, l.ToFloat64())
+	assert.Equal(t, DecimalList{0, decimal.FromInt(7)}, l.ToDecimal())
+	assert.Equal(t, BoolList{false, true}, l.ToBool())
+
+	d := NullList{Values: DateList{date.New(2024, 5, 1), date.New(2024, 5, 2)}}
+	d.Empty.Set(1, true)
+	assert.Equal(t, DateList{date.New(2024, 5, 1), date.ZeroDateVB}, d.ToDate())
+	assert.Equal(t, datetime.ZeroDate(), d.ToDateTime()[1])
+	assert.Equal(t, "", d.ToStringWithDateFormat("02.01.2006")[1])
+}
+
+func TestNullList_TakeCloneJSON(t *testing.T) {
+	l := NullList{Values: IntList{1, 2, 3}}
+	l.Empty.Set(1, true)
+
+	taken := Take(l, []int{1, 2, 1, 0})
+	assert.Equal(t, VariantList{nil, RInt(3), nil, RInt(1)}, taken.ToVariant())
+
+	c := CloneList(l).(NullList)
+	c.Values.(IntList)[0] = 9
+	c.Empty.Set(1, false)
+	assert.Equal(t, IntList{1, 2, 3}, l.Values)
+	assert.True(t, l.IsEmpty(1))
+
+	bb, err := json.Marshal(l)
+	assert.NoError(t, err)
+	assert.Equal(t, "[1,null,3]", string(bb))
+
+	_, sums := GroupAggregate(l, StringList{"a", "a", "b"}, false, Sum)
+	assert.Equal(t, VariantList{RInt(1), RInt(3)}, sums)
+}
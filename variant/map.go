@@ -1,7 +1,13 @@
 package variant
 
 import (
+	"bytes"
+	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -23,6 +29,160 @@ func (s *VariantMap) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalTypedJSON creates a JSON object with the values encoded by MarshalTypedJSON, so that
+// UnmarshalTypedJSON restores the identical values. json.Marshal, by contrast, writes plain JSON
+// values which lose the type, e.g. of decimals or dates.
+func (s VariantMap) MarshalTypedJSON() ([]byte, error) {
+	return s.marshalJSON(MarshalTypedJSON)
+}
+
+// UnmarshalTypedJSON parses a VariantMap created by MarshalTypedJSON.
+func (s *VariantMap) UnmarshalTypedJSON(data []byte) error {
+	return s.unmarshalJSON(data, UnmarshalTypedJSON)
+}
+
+// EncodeJSON creates a JSON object with the values encoded by EncodeJSON, so that DecodeJSON
+// with the same hashDates setting restores the values including their types.
+func (s VariantMap) EncodeJSON(hashDates bool) ([]byte, error) {
+	return s.marshalJSON(func(v Variant) ([]byte, error) {
+		return EncodeJSON(v, hashDates)
+	})
+}
+
+// DecodeJSON parses a VariantMap created by EncodeJSON.
+func (s *VariantMap) DecodeJSON(data []byte, hashDates bool) error {
+	return s.unmarshalJSON(data, func(data []byte) (Variant, error) {
+		return DecodeJSON(data, hashDates)
+	})
+}
+
+// marshalJSON creates a JSON object with the keys in sorted order and the values encoded by enc.
+func (s VariantMap) marshalJSON(enc func(Variant) ([]byte, error)) ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := enc(s[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalJSON parses a JSON object with the values decoded by dec.
+func (s *VariantMap) unmarshalJSON(data []byte, dec func([]byte) (Variant, error)) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*s = nil
+		return nil
+	}
+	*s = make(map[string]Variant, len(raw))
+	for k, v := range raw {
+		x, err := dec(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		(*s)[k] = x
+	}
+	return nil
+}
+
+// xmlItem is the XML representation of a single value of a VariantMap.
+type xmlItem struct {
+	Key   string `xml:"Key,attr"`
+	Type  string `xml:"Type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// MarshalXML encodes the VariantMap as list of Item elements with key and type attributes.
+func (s VariantMap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		t, v := FormatTyped(s[k])
+		if err := e.EncodeElement(xmlItem{Key: k, Type: t, Value: v}, xml.StartElement{Name: xml.Name{Local: "Item"}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML decodes a VariantMap encoded by MarshalXML.
+func (s *VariantMap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var x struct {
+		Items []xmlItem `xml:"Item"`
+	}
+	if err := d.DecodeElement(&x, &start); err != nil {
+		return err
+	}
+	*s = make(map[string]Variant, len(x.Items))
+	for _, item := range x.Items {
+		v, err := ParseTyped(item.Type, item.Value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.Key, err)
+		}
+		(*s)[item.Key] = v
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, the VariantMap is stored as JSON created by MarshalTypedJSON.
+func (s VariantMap) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	bb, err := s.MarshalTypedJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(bb), nil
+}
+
+// Scan implements sql.Scanner for values stored by Value.
+func (s *VariantMap) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case string:
+		return s.UnmarshalTypedJSON([]byte(v))
+	case []byte:
+		return s.UnmarshalTypedJSON(v)
+	default:
+		return errors.New("incompatible type for VariantMap: " + fmt.Sprintf("%T", src))
+	}
+}
+
 // GetItem returns the value with the given key with case-insensitive search
 func (s *VariantMap) GetItem(key string) (Variant, bool) {
 	if v, ok := (*s)[key]; ok {
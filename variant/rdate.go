@@ -72,6 +72,9 @@ func (s rDate) toStringWithDateFormat(df string) string {
 	if df == "" {
 		return date.Date(s).String()
 	}
+	if tag, pattern, ok := date.ParseLocaleFormat(df); ok {
+		return date.Date(s).FormatPattern(pattern, tag)
+	}
 	return date.Date(s).Format(df)
 }
 
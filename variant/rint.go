@@ -205,6 +205,10 @@ func (s rInt) mod(p Variant) Variant {
 func (s rInt) exp(p Variant) Variant {
 	switch GetType(p) {
 	case TypeRInt, TypeRBool:
+		if p.toInt() < 0 {
+			// negative exponents result in fractions, e.g. 2^-1 = 0.5
+			return rFloat(s.toFloat64()).exp(p)
+		}
 		return RInt(int(math.Pow(s.toFloat64(), p.toFloat64())))
 	case TypeRDecimal:
 		return RFloat(s.toFloat64()).exp(p)
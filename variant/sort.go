@@ -0,0 +1,239 @@
+package variant
+
+import (
+	"sort"
+
+	"golang.org/x/text/collate"
+)
+
+// SortPermutation sorts the records described by the given key lists and returns the sort order as
+// permutation: the i-th record of the sorted result is the record with index perm[i].
+// All key lists must have the same length. desc defines per key whether it is sorted descending,
+// missing flags mean ascending. The sort is stable, records with equal keys keep their original order.
+//
+// Empty values (nil, empty strings and zero dates) are always sorted to the end, for ascending as well
+// as descending keys. All other values are compared like Less and Greater do, the collator is used to
+// compare strings and may be nil.
+func SortPermutation(keys []RList, desc []bool, collator *collate.Collator) []int {
+	n := 0
+	if len(keys) > 0 {
+		n = keys[0].Len()
+	}
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	if len(keys) == 0 {
+		return perm
+	}
+
+	comparers := make([]func(i, j int) int, len(keys))
+	for k, key := range keys {
+		if key.Len() != n {
+			panic("sort keys must have the same length")
+		}
+		cmp := listComparer(key, collator)
+		if k < len(desc) && desc[k] {
+			comparers[k] = descending(key, cmp)
+		} else {
+			comparers[k] = cmp
+		}
+	}
+
+	sort.SliceStable(perm, func(a, b int) bool {
+		i, j := perm[a], perm[b]
+		for _, cmp := range comparers {
+			if c := cmp(i, j); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return perm
+}
+
+// Take returns a new list with the items at the given indexes, e.g. to apply a permutation
+// returned by SortPermutation.
+func Take(l RList, indexes []int) RList {
+	switch v := l.(type) {
+	case BoolList:
+		r := NewBoolList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case IntList:
+		r := NewIntList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case Float64List:
+		r := NewFloat64List(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case DecimalList:
+		r := NewDecimalList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case StringList:
+		r := NewStringList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case DateList:
+		r := NewDateList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case DateTimeList:
+		r := NewDateTimeList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case VariantList:
+		r := NewVariantList(len(indexes))
+		for i, idx := range indexes {
+			r[i] = v[idx]
+		}
+		return r
+	case NullList:
+		var empty Bitmap
+		for i, idx := range indexes {
+			if v.Empty.Get(idx) {
+				empty.Set(i, true)
+			}
+		}
+		return NullList{Values: Take(v.Values, indexes), Empty: empty}
+	default:
+		panic("new type not implemented")
+	}
+}
+
+// isEmptyItem checks if the item is treated as empty value when sorting.
+func isEmptyItem(v Variant) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case rString:
+		return val == ""
+	case rDate:
+		return val.isZero()
+	case rDateTime:
+		return val.isZero()
+	default:
+		return false
+	}
+}
+
+// listComparer returns a function comparing two items of the list, returning -1, 0 or 1.
+// Empty values are greater than all other values.
+func listComparer(l RList, collator *collate.Collator) func(i, j int) int {
+	switch v := l.(type) {
+	case IntList:
+		return func(i, j int) int {
+			return compareOrdered(v[i] < v[j], v[i] > v[j])
+		}
+	case DecimalList:
+		return func(i, j int) int {
+			return compareOrdered(v[i] < v[j], v[i] > v[j])
+		}
+	case Float64List:
+		return func(i, j int) int {
+			return compareOrdered(v[i] < v[j], v[i] > v[j])
+		}
+	case BoolList:
+		return func(i, j int) int {
+			return compareOrdered(!v[i] && v[j], v[i] && !v[j])
+		}
+	case StringList:
+		return func(i, j int) int {
+			a, b := v[i], v[j]
+			if c := compareEmpty(a == "", b == ""); c != 0 || a == "" {
+				return c
+			}
+			if collator != nil {
+				if c := collator.CompareString(a, b); c != 0 {
+					return c
+				}
+			}
+			return compareOrdered(a < b, a > b)
+		}
+	case DateList:
+		return func(i, j int) int {
+			a, b := v[i], v[j]
+			if c := compareEmpty(a.IsZero(), b.IsZero()); c != 0 || a.IsZero() {
+				return c
+			}
+			return compareOrdered(a.Before(b), a.After(b))
+		}
+	case DateTimeList:
+		return func(i, j int) int {
+			a, b := v[i], v[j]
+			if c := compareEmpty(a.IsZero(), b.IsZero()); c != 0 || a.IsZero() {
+				return c
+			}
+			return compareOrdered(a.Before(b), a.After(b))
+		}
+	default:
+		return func(i, j int) int {
+			a, b := l.Item(i), l.Item(j)
+			ea, eb := isEmptyItem(a), isEmptyItem(b)
+			if c := compareEmpty(ea, eb); c != 0 || ea {
+				return c
+			}
+			return compareOrdered(Less(a, b, collator), Greater(a, b, collator))
+		}
+	}
+}
+
+// descending reverses the order of the comparer but keeps empty values at the end.
+func descending(l RList, cmp func(i, j int) int) func(i, j int) int {
+	var isEmpty func(i int) bool
+	switch v := l.(type) {
+	case IntList, DecimalList, Float64List, BoolList:
+		isEmpty = nil
+	case StringList:
+		isEmpty = func(i int) bool { return v[i] == "" }
+	case DateList:
+		isEmpty = func(i int) bool { return v[i].IsZero() }
+	case DateTimeList:
+		isEmpty = func(i int) bool { return v[i].IsZero() }
+	default:
+		isEmpty = func(i int) bool { return isEmptyItem(l.Item(i)) }
+	}
+	if isEmpty == nil {
+		return func(i, j int) int {
+			return -cmp(i, j)
+		}
+	}
+	return func(i, j int) int {
+		ei, ej := isEmpty(i), isEmpty(j)
+		if ei || ej {
+			return compareEmpty(ei, ej)
+		}
+		return -cmp(i, j)
+	}
+}
+
+// compareEmpty compares two values regarding only whether they are empty. Empty values are greater.
+func compareEmpty(aEmpty, bEmpty bool) int {
+	return compareOrdered(!aEmpty && bEmpty, aEmpty && !bEmpty)
+}
+
+func compareOrdered(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package variant
+
+import (
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+func TestSortPermutation(t *testing.T) {
+	contest := IntList{2, 1, 2, 1, 1}
+	time := DecimalList{decimal.FromInt(50), decimal.FromInt(70), decimal.FromInt(40), decimal.FromInt(70), decimal.FromInt(60)}
+
+	perm := SortPermutation([]RList{contest, time}, nil, nil)
+	assert.Equal(t, []int{4, 1, 3, 2, 0}, perm)
+
+	perm = SortPermutation([]RList{contest, time}, []bool{true, false}, nil)
+	assert.Equal(t, []int{2, 0, 4, 1, 3}, perm)
+
+	perm = SortPermutation([]RList{contest, time}, []bool{false, true}, nil)
+	assert.Equal(t, []int{1, 3, 4, 0, 2}, perm)
+
+	assert.Equal(t, []int{}, SortPermutation(nil, nil, nil))
+}
+
+func TestSortPermutation_Empty(t *testing.T) {
+	names := StringList{"b", "", "a", "c", ""}
+	assert.Equal(t, []int{2, 0, 3, 1, 4}, SortPermutation([]RList{names}, nil, nil))
+	assert.Equal(t, []int{3, 0, 2, 1, 4}, SortPermutation([]RList{names}, []bool{true}, nil))
+
+	dates := DateList{date.New(2000, 1, 1), date.ZeroDateVB, date.New(1990, 1, 1)}
+	assert.Equal(t, []int{2, 0, 1}, SortPermutation([]RList{dates}, nil, nil))
+	assert.Equal(t, []int{0, 2, 1}, SortPermutation([]RList{dates}, []bool{true}, nil))
+
+	values := VariantList{RInt(3), nil, RInt(-1), RString(""), RDecimal(decimal.FromInt(2))}
+	assert.Equal(t, []int{2, 4, 0, 1, 3}, SortPermutation([]RList{values}, nil, nil))
+	assert.Equal(t, []int{0, 4, 2, 1, 3}, SortPermutation([]RList{values}, []bool{true}, nil))
+}
+
+func TestSortPermutation_Collator(t *testing.T) {
+	names := StringList{"Zoe", "Älex", "anna", "Bob"}
+	assert.Equal(t, []int{3, 0, 2, 1}, SortPermutation([]RList{names}, nil, nil))
+
+	c := collate.New(language.German, collate.IgnoreCase)
+	assert.Equal(t, []int{1, 2, 3, 0}, SortPermutation([]RList{names}, nil, c))
+}
+
+func TestTake(t *testing.T) {
+	assert.Equal(t, StringList{"c", "a"}, Take(StringList{"a", "b", "c"}, []int{2, 0}))
+	assert.Equal(t, VariantList{nil, RInt(1)}, Take(VariantList{RInt(1), nil}, []int{1, 0}))
+}
@@ -0,0 +1,322 @@
+package variant
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// RowWriter writes a result set as JSON array of rows to an io.Writer, row by row.
+// The output has the same format as RListArrayToJSON.
+type RowWriter struct {
+	w         *bufio.Writer
+	hashDates bool
+	rows      int
+	closed    bool
+}
+
+// NewRowWriter creates a new RowWriter. If hashDates is true, dates are enclosed in hashes.
+func NewRowWriter(w io.Writer, hashDates bool) *RowWriter {
+	return &RowWriter{w: bufio.NewWriter(w), hashDates: hashDates}
+}
+
+// WriteRow writes a single row.
+func (s *RowWriter) WriteRow(values []Variant) error {
+	if s.closed {
+		return errors.New("writer already closed")
+	}
+	s.writeRowStart()
+	for j, v := range values {
+		if j > 0 {
+			_ = s.w.WriteByte(',')
+		}
+		_, _ = s.w.Write(ToJSON(v, s.hashDates))
+	}
+	return s.w.WriteByte(']')
+}
+
+// WriteLists writes all rows of the given columns. All lists must have the same length.
+func (s *RowWriter) WriteLists(result []RList) error {
+	if s.closed {
+		return errors.New("writer already closed")
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	L := result[0].Len()
+	for _, l := range result {
+		if l.Len() != L {
+			return errors.New("lists must have the same length")
+		}
+	}
+	for i := 0; i < L; i++ {
+		s.writeRowStart()
+		for j, l := range result {
+			if j > 0 {
+				_ = s.w.WriteByte(',')
+			}
+			_, _ = s.w.Write(ToJSON(l.Item(i), s.hashDates))
+		}
+		if err := s.w.WriteByte(']'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close writes the end of the array and flushes the output. It does not close the underlying writer.
+func (s *RowWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.rows == 0 {
+		_ = s.w.WriteByte('[')
+	}
+	_ = s.w.WriteByte(']')
+	return s.w.Flush()
+}
+
+func (s *RowWriter) writeRowStart() {
+	if s.rows == 0 {
+		_ = s.w.WriteByte('[')
+	} else {
+		_ = s.w.WriteByte(',')
+	}
+	_ = s.w.WriteByte('[')
+	s.rows++
+}
+
+// WriteRListArrayJSON writes the result set to w in the same format as RListArrayToJSON
+// without building the whole output in memory.
+func WriteRListArrayJSON(w io.Writer, result []RList, hashDates bool) error {
+	rw := NewRowWriter(w, hashDates)
+	if err := rw.WriteLists(result); err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+// RowReader reads a result set in the format of RListArrayToJSON from an io.Reader, row by row.
+type RowReader struct {
+	dec       *json.Decoder
+	hashDates bool
+	started   bool
+	done      bool
+}
+
+// NewRowReader creates a new RowReader. hashDates must match the setting used when writing the data.
+func NewRowReader(r io.Reader, hashDates bool) *RowReader {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return &RowReader{dec: dec, hashDates: hashDates}
+}
+
+// ReadRow reads the next row. It returns io.EOF if there are no more rows.
+func (s *RowReader) ReadRow() ([]Variant, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+	if !s.started {
+		t, err := s.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := t.(json.Delim); !ok || d != '[' {
+			return nil, fmt.Errorf("unexpected token %v", t)
+		}
+		s.started = true
+	}
+	if !s.dec.More() {
+		if _, err := s.dec.Token(); err != nil {
+			return nil, err
+		}
+		s.done = true
+		return nil, io.EOF
+	}
+
+	var row []interface{}
+	if err := s.dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	values := make([]Variant, len(row))
+	for i, x := range row {
+		v, err := s.toVariant(x)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (s *RowReader) toVariant(x interface{}) (Variant, error) {
+	switch v := x.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		return ParseNumber(string(v))
+	case string:
+		return ToVariant2(v, s.hashDates), nil
+	case bool:
+		return RBool(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v", x)
+	}
+}
+
+// ReadRListArrayJSON reads a result set in the format of RListArrayToJSON and returns one list per column.
+// If types is given, the columns are converted to the list types matching the given variant types while
+// reading (TypeEmpty creates a VariantList). Otherwise, the type of each column is taken from its first
+// non-empty value and the values are stored in a list of this type while reading. If a later value has
+// a different type, the column is changed to a VariantList. Integers in a column with decimals are read
+// as decimals.
+func ReadRListArrayJSON(r io.Reader, hashDates bool, types []Type) ([]RList, error) {
+	rr := NewRowReader(r, hashDates)
+	var columns []*columnReader
+	for {
+		row, err := rr.ReadRow()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if columns == nil {
+			columns = make([]*columnReader, len(row))
+			for j := range columns {
+				columns[j] = &columnReader{list: NewVariantList(0)}
+				if types != nil {
+					if j >= len(types) {
+						return nil, fmt.Errorf("no type given for column %d", j)
+					}
+					columns[j].list = ConvertList(NewVariantList(0), types[j])
+					columns[j].fixed = true
+				}
+			}
+		}
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("row has %d values, expected %d", len(row), len(columns))
+		}
+		for j, v := range row {
+			columns[j].add(v)
+		}
+	}
+
+	if columns == nil {
+		return nil, nil
+	}
+	result := make([]RList, len(columns))
+	for j, c := range columns {
+		result[j] = c.list
+	}
+	return result, nil
+}
+
+// columnReader collects the values of a column read by ReadRListArrayJSON.
+type columnReader struct {
+	// list is a VariantList until the first non-empty value or after a type conflict, otherwise
+	// a list of type typ.
+	list  RList
+	typ   Type
+	fixed bool // the type is given by the caller or the column is a VariantList after a type conflict
+
+	// empty values stored in the typed list, so that the VariantList can be restored on a type conflict
+	nulls  Bitmap
+	blanks Bitmap
+	others map[int]Variant
+}
+
+func (s *columnReader) add(v Variant) {
+	if s.fixed {
+		s.list = appendItem(s.list, v)
+		return
+	}
+	i := s.list.Len()
+	if isEmptyItem(v) {
+		if s.typ != TypeEmpty {
+			s.addEmpty(i, v)
+		}
+		s.list = appendItem(s.list, v)
+		return
+	}
+
+	vt := v.getType()
+	switch {
+	case s.typ == TypeEmpty:
+		// first non-empty value
+		leading := s.list.(VariantList)
+		for j, x := range leading {
+			s.addEmpty(j, x)
+		}
+		s.typ = vt
+		s.list = ConvertList(leading, vt)
+	case s.typ == vt:
+	case s.typ == TypeRDecimal && vt == TypeRInt:
+	case s.typ == TypeRInt && vt == TypeRDecimal:
+		s.typ = TypeRDecimal
+		s.list = s.list.ToDecimal()
+	case s.typ != TypeEmpty:
+		s.toVariantList()
+	}
+	s.list = appendItem(s.list, v)
+}
+
+func (s *columnReader) addEmpty(i int, v Variant) {
+	switch {
+	case v == nil:
+		s.nulls.Set(i, true)
+	case IsString(v):
+		s.blanks.Set(i, true)
+	default:
+		if s.others == nil {
+			s.others = make(map[int]Variant)
+		}
+		s.others[i] = v
+	}
+}
+
+// toVariantList changes the column to a VariantList with the original empty values.
+func (s *columnReader) toVariantList() {
+	l := s.list.ToVariant()
+	for i := range l {
+		switch {
+		case s.nulls.Get(i):
+			l[i] = nil
+		case s.blanks.Get(i):
+			l[i] = RString("")
+		}
+	}
+	for i, v := range s.others {
+		l[i] = v
+	}
+	s.list = l
+	s.fixed = true
+	s.nulls, s.blanks, s.others = nil, nil, nil
+}
+
+// appendItem appends a value to the list, converting it to the type of the list.
+func appendItem(l RList, v Variant) RList {
+	switch x := l.(type) {
+	case BoolList:
+		return append(x, ToBool(v))
+	case IntList:
+		return append(x, ToInt(v))
+	case Float64List:
+		return append(x, ToFloat64(v))
+	case DecimalList:
+		return append(x, ToDecimal(v))
+	case StringList:
+		return append(x, ToString(v))
+	case DateList:
+		return append(x, ToDate(v))
+	case DateTimeList:
+		return append(x, ToDateTime(v))
+	case VariantList:
+		return append(x, v)
+	default:
+		panic("new type not implemented")
+	}
+}
@@ -0,0 +1,90 @@
+package variant
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var streamTestLists = []RList{
+	IntList{1, 2, 3},
+	StringList{"Miller", "", "Zahn \"Z\""},
+	DecimalList{decimal.FromFloat(3600.5), decimal.FromInt(3), 0},
+	DateList{date.New(1980, 5, 1), date.ZeroDateVB, date.New(2001, 12, 31)},
+	DateTimeList{datetime.New(2024, 5, 1, 9, 0, 0), datetime.New(2024, 5, 1, 9, 0, 30), datetime.ZeroDate()},
+	VariantList{RInt(1), RString("x"), RBool(true)},
+}
+
+func TestWriteRListArrayJSON(t *testing.T) {
+	for _, hashDates := range []bool{false, true} {
+		var buf bytes.Buffer
+		assert.NoError(t, WriteRListArrayJSON(&buf, streamTestLists, hashDates))
+		assert.Equal(t, string(RListArrayToJSON(streamTestLists, hashDates)), buf.String())
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteRListArrayJSON(&buf, nil, false))
+	assert.Equal(t, "[]", buf.String())
+}
+
+func TestRowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewRowWriter(&buf, true)
+	assert.NoError(t, w.WriteRow([]Variant{RInt(1), RDate(date.New(2024, 1, 2))}))
+	assert.NoError(t, w.WriteRow([]Variant{nil, RString("a")}))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, `[[1,"#2024-01-02#"],["","a"]]`, buf.String())
+	assert.Error(t, w.WriteRow(nil))
+}
+
+func TestReadRListArrayJSON(t *testing.T) {
+	for _, hashDates := range []bool{false, true} {
+		data := RListArrayToJSON(streamTestLists, hashDates)
+		lists, err := ReadRListArrayJSON(bytes.NewReader(data), hashDates, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, streamTestLists, lists)
+
+		lists, err = ReadRListArrayJSON(bytes.NewReader(data), hashDates, []Type{TypeRInt, TypeRString, TypeRDecimal, TypeRDate, TypeRDateTime, TypeEmpty})
+		assert.NoError(t, err)
+		assert.Equal(t, streamTestLists, lists)
+	}
+
+	lists, err := ReadRListArrayJSON(strings.NewReader("[]"), false, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, lists)
+
+	_, err = ReadRListArrayJSON(strings.NewReader("[[1,2],[3]]"), false, nil)
+	assert.Error(t, err)
+
+	_, err = ReadRListArrayJSON(strings.NewReader("{}"), false, nil)
+	assert.Error(t, err)
+
+	// the column type is detected while reading, conflicts result in a VariantList with the original values
+	lists, err = ReadRListArrayJSON(strings.NewReader(`[[null,1,null,"a",null],[2,2.5,"","",""],[3,3,4,true,null],[null,null,"x","b",null]]`), false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []RList{
+		IntList{0, 2, 3, 0},
+		DecimalList{decimal.FromInt(1), decimal.FromFloat(2.5), decimal.FromInt(3), 0},
+		VariantList{nil, RString(""), RInt(4), RString("x")},
+		VariantList{RString("a"), RString(""), RBool(true), RString("b")},
+		VariantList{nil, RString(""), nil, nil},
+	}, lists)
+}
+
+func TestRowReader(t *testing.T) {
+	r := NewRowReader(strings.NewReader(`[[1, "#2024-01-02#", 2.5], [null, "2024-01-02", true]]`), true)
+	row, err := r.ReadRow()
+	assert.NoError(t, err)
+	assert.Equal(t, []Variant{RInt(1), RDate(date.New(2024, 1, 2)), RDecimal(decimal.FromFloat(2.5))}, row)
+	row, err = r.ReadRow()
+	assert.NoError(t, err)
+	assert.Equal(t, []Variant{nil, RString("2024-01-02"), RBool(true)}, row)
+	_, err = r.ReadRow()
+	assert.Equal(t, io.EOF, err)
+}
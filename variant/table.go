@@ -0,0 +1,516 @@
+package variant
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/collate"
+)
+
+// Table is a table with named columns. Each column is stored as RList, all columns have the same length.
+type Table struct {
+	names   []string
+	columns []RList
+}
+
+// NewTable creates a new table from the given column names and lists.
+func NewTable(names []string, columns []RList) (*Table, error) {
+	if len(names) != len(columns) {
+		return nil, errors.New("number of names and columns differ")
+	}
+	for i, name := range names {
+		for j := 0; j < i; j++ {
+			if strings.EqualFold(names[j], name) {
+				return nil, fmt.Errorf("duplicate column %s", name)
+			}
+		}
+		if columns[i] == nil {
+			return nil, fmt.Errorf("column %s is nil", name)
+		}
+		if columns[i].Len() != columns[0].Len() {
+			return nil, fmt.Errorf("column %s has %d rows, expected %d", name, columns[i].Len(), columns[0].Len())
+		}
+	}
+	return &Table{names: names, columns: columns}, nil
+}
+
+// Names returns the column names.
+func (s *Table) Names() []string {
+	return s.names
+}
+
+// Columns returns the columns.
+func (s *Table) Columns() []RList {
+	return s.columns
+}
+
+// Len returns the number of rows.
+func (s *Table) Len() int {
+	if len(s.columns) == 0 {
+		return 0
+	}
+	return s.columns[0].Len()
+}
+
+// ColumnIndex returns the index of the column with the given name using case-insensitive search, -1 if not found.
+func (s *Table) ColumnIndex(name string) int {
+	for i, n := range s.names {
+		if n == name {
+			return i
+		}
+	}
+	for i, n := range s.names {
+		if strings.EqualFold(n, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Column returns the column with the given name using case-insensitive search.
+func (s *Table) Column(name string) (RList, bool) {
+	i := s.ColumnIndex(name)
+	if i < 0 {
+		return nil, false
+	}
+	return s.columns[i], true
+}
+
+// ColumnMap returns copies of the columns as map, e.g. to evaluate expressions. The copies can be
+// modified without changing the table.
+func (s *Table) ColumnMap() map[string]RList {
+	m := make(map[string]RList, len(s.names))
+	for i, name := range s.names {
+		m[name] = CloneList(s.columns[i])
+	}
+	return m
+}
+
+// Row returns the values of a row.
+func (s *Table) Row(index int) VariantMap {
+	m := make(VariantMap, len(s.names))
+	for i, name := range s.names {
+		m[name] = s.columns[i].Item(index)
+	}
+	return m
+}
+
+// AddColumn adds a column to the table.
+func (s *Table) AddColumn(name string, l RList) error {
+	if s.ColumnIndex(name) >= 0 {
+		return fmt.Errorf("duplicate column %s", name)
+	}
+	if len(s.columns) > 0 && l.Len() != s.Len() {
+		return fmt.Errorf("column %s has %d rows, expected %d", name, l.Len(), s.Len())
+	}
+	s.names = append(s.names, name)
+	s.columns = append(s.columns, l)
+	return nil
+}
+
+// Filter returns a new table with the rows where mask is true. The mask must have one item per row.
+func (s *Table) Filter(mask BoolList) (*Table, error) {
+	if len(mask) != s.Len() {
+		return nil, fmt.Errorf("mask has %d items, expected %d", len(mask), s.Len())
+	}
+	var indexes []int
+	for i, x := range mask {
+		if x {
+			indexes = append(indexes, i)
+		}
+	}
+	return s.take(indexes), nil
+}
+
+// Select returns a new table with copies of the given columns.
+func (s *Table) Select(names ...string) (*Table, error) {
+	t := &Table{}
+	for _, name := range names {
+		i := s.ColumnIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %s", name)
+		}
+		t.names = append(t.names, s.names[i])
+		t.columns = append(t.columns, CloneList(s.columns[i]))
+	}
+	return t, nil
+}
+
+// Append returns a new table with the rows of this table followed by the rows of the other table.
+// Columns are matched by name, both tables must have the same columns. If the column types differ,
+// the new column will be a VariantList.
+func (s *Table) Append(other *Table) (*Table, error) {
+	if len(other.names) != len(s.names) {
+		return nil, errors.New("tables have different columns")
+	}
+	t := &Table{names: append([]string(nil), s.names...), columns: make([]RList, len(s.columns))}
+	for i, name := range s.names {
+		l, ok := other.Column(name)
+		if !ok {
+			return nil, fmt.Errorf("column %s missing", name)
+		}
+		t.columns[i] = appendList(s.columns[i], l)
+	}
+	return t, nil
+}
+
+// Sort returns a new table sorted by the given key columns, see SortPermutation.
+func (s *Table) Sort(keys []string, desc []bool, collator *collate.Collator) (*Table, error) {
+	lists := make([]RList, len(keys))
+	for i, key := range keys {
+		l, ok := s.Column(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", key)
+		}
+		lists[i] = l
+	}
+	if len(lists) == 0 {
+		perm := make([]int, s.Len())
+		for i := range perm {
+			perm[i] = i
+		}
+		return s.take(perm), nil
+	}
+	return s.take(SortPermutation(lists, desc, collator)), nil
+}
+
+// Join returns a new table with the rows of this table combined with the rows of the other table
+// where the value in column key equals the value in column otherKey of the other table (see GroupBy,
+// strings are compared case-insensitively). Rows without matching row in the other table are omitted.
+// The result contains all columns of this table and all columns of the other table except otherKey.
+func (s *Table) Join(other *Table, key string, otherKey string) (*Table, error) {
+	return s.join(other, key, otherKey, false)
+}
+
+// LeftJoin works like Join, but keeps rows without matching row in the other table.
+// The columns of the other table are empty for these rows.
+func (s *Table) LeftJoin(other *Table, key string, otherKey string) (*Table, error) {
+	return s.join(other, key, otherKey, true)
+}
+
+func (s *Table) join(other *Table, key string, otherKey string, keepUnmatched bool) (*Table, error) {
+	k1, ok := s.Column(key)
+	if !ok {
+		return nil, fmt.Errorf("unknown column %s", key)
+	}
+	otherKeyIndex := other.ColumnIndex(otherKey)
+	if otherKeyIndex < 0 {
+		return nil, fmt.Errorf("unknown column %s", otherKey)
+	}
+	for i, name := range other.names {
+		if i != otherKeyIndex && s.ColumnIndex(name) >= 0 {
+			return nil, fmt.Errorf("duplicate column %s", name)
+		}
+	}
+
+	groups := GroupBy(other.columns[otherKeyIndex], false)
+	var left, right []int
+	for i := 0; i < k1.Len(); i++ {
+		g, ok := groups.Lookup(k1.Item(i))
+		if !ok {
+			if keepUnmatched {
+				left = append(left, i)
+				right = append(right, -1)
+			}
+			continue
+		}
+		for _, j := range groups.Indexes(g) {
+			left = append(left, i)
+			right = append(right, j)
+		}
+	}
+
+	t := s.take(left)
+	for i, name := range other.names {
+		if i != otherKeyIndex {
+			t.names = append(t.names, name)
+			t.columns = append(t.columns, takeOrEmpty(other.columns[i], right))
+		}
+	}
+	return t, nil
+}
+
+// take returns a new table with the rows at the given indexes.
+func (s *Table) take(indexes []int) *Table {
+	t := &Table{names: append([]string(nil), s.names...), columns: make([]RList, len(s.columns))}
+	for i, l := range s.columns {
+		t.columns[i] = Take(l, indexes)
+	}
+	return t
+}
+
+// tableJSON is the JSON representation of a Table.
+type tableJSON struct {
+	Columns []string
+	Types   []string
+	Rows    json.RawMessage
+}
+
+// MarshalJSON encodes the table as JSON. The rows are encoded as arrays of values with hashed dates,
+// the column types are included so that the table can be decoded with the same types.
+func (s *Table) MarshalJSON() ([]byte, error) {
+	types := make([]string, len(s.columns))
+	for i, l := range s.columns {
+		types[i] = listTypeName(l)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < s.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('[')
+		for j, l := range s.columns {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			if v := l.Item(i); v == nil {
+				buf.WriteString("null")
+			} else {
+				buf.Write(v.toJSON(true))
+			}
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte(']')
+
+	return json.Marshal(tableJSON{Columns: s.names, Types: types, Rows: buf.Bytes()})
+}
+
+// UnmarshalJSON decodes a table from JSON created by MarshalJSON.
+func (s *Table) UnmarshalJSON(data []byte) error {
+	var tj tableJSON
+	if err := json.Unmarshal(data, &tj); err != nil {
+		return err
+	}
+	if len(tj.Types) != len(tj.Columns) {
+		return errors.New("number of columns and types differ")
+	}
+
+	var rows [][]interface{}
+	d := json.NewDecoder(bytes.NewReader(tj.Rows))
+	d.UseNumber()
+	if err := d.Decode(&rows); err != nil {
+		return err
+	}
+
+	columns := make([]RList, len(tj.Columns))
+	for j := range columns {
+		t, ok := listTypesByName[tj.Types[j]]
+		if !ok {
+			return fmt.Errorf("unknown column type %s", tj.Types[j])
+		}
+		values := NewVariantList(len(rows))
+		for i, row := range rows {
+			if len(row) != len(columns) {
+				return fmt.Errorf("row %d has %d values, expected %d", i, len(row), len(columns))
+			}
+			v, err := jsonValueToVariant(row[j], t != TypeRString)
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+		columns[j] = ConvertList(values, t)
+	}
+
+	t, err := NewTable(tj.Columns, columns)
+	if err != nil {
+		return err
+	}
+	*s = *t
+	return nil
+}
+
+// WriteCSV writes the table as CSV including a header line with the column names.
+func (s *Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(s.names); err != nil {
+		return err
+	}
+	strs := make([]StringList, len(s.columns))
+	for i, l := range s.columns {
+		strs[i] = l.ToString()
+	}
+	record := make([]string, len(s.columns))
+	for i := 0; i < s.Len(); i++ {
+		for j := range strs {
+			record[j] = strs[j][i]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads a table from CSV with a header line containing the column names. The columns are
+// converted to the given types, all other columns will be StringLists.
+func ReadCSV(r io.Reader, types map[string]Type) (*Table, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("header line missing")
+	}
+
+	names := records[0]
+	columns := make([]RList, len(names))
+	for j, name := range names {
+		l := NewStringList(len(records) - 1)
+		for i := range l {
+			l[i] = records[i+1][j]
+		}
+		columns[j] = l
+		for k, t := range types {
+			if strings.EqualFold(k, name) {
+				columns[j] = ConvertList(l, t)
+				break
+			}
+		}
+	}
+	return NewTable(names, columns)
+}
+
+// ConvertList converts the list to the list type matching the given variant type.
+// TypeEmpty converts to a VariantList.
+func ConvertList(l RList, t Type) RList {
+	switch t {
+	case TypeRBool:
+		return l.ToBool()
+	case TypeRString:
+		if v, ok := l.(VariantList); ok {
+			// keep empty values empty
+			r := NewStringList(len(v))
+			for i, x := range v {
+				r[i] = ToString(x)
+			}
+			return r
+		}
+		return l.ToString()
+	case TypeRInt:
+		return l.ToInt()
+	case TypeRFloat:
+		return l.ToFloat64()
+	case TypeRDecimal:
+		return l.ToDecimal()
+	case TypeRDateTime:
+		return l.ToDateTime()
+	case TypeRDate:
+		return l.ToDate()
+	default:
+		return l.ToVariant()
+	}
+}
+
+var listTypesByName = map[string]Type{
+	"variant":  TypeEmpty,
+	"bool":     TypeRBool,
+	"string":   TypeRString,
+	"int":      TypeRInt,
+	"float":    TypeRFloat,
+	"decimal":  TypeRDecimal,
+	"datetime": TypeRDateTime,
+	"date":     TypeRDate,
+}
+
+// listTypeName returns the name of the list type used in the JSON representation of a table.
+func listTypeName(l RList) string {
+	switch l.(type) {
+	case BoolList:
+		return "bool"
+	case StringList:
+		return "string"
+	case IntList:
+		return "int"
+	case Float64List:
+		return "float"
+	case DecimalList:
+		return "decimal"
+	case DateTimeList:
+		return "datetime"
+	case DateList:
+		return "date"
+	default:
+		return "variant"
+	}
+}
+
+// jsonValueToVariant converts a value decoded with json.Decoder.UseNumber to Variant.
+// If parseDates is true, strings containing hashed dates are converted to dates.
+func jsonValueToVariant(v interface{}, parseDates bool) (Variant, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		return ParseNumber(string(x))
+	case string:
+		if !parseDates {
+			return RString(x), nil
+		}
+		return ToVariant2(x, true), nil
+	case bool:
+		return RBool(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v", v)
+	}
+}
+
+// appendList returns a new list with the items of both lists. If the list types differ, a VariantList is returned.
+func appendList(a, b RList) RList {
+	switch x := a.(type) {
+	case BoolList:
+		if y, ok := b.(BoolList); ok {
+			return append(append(BoolList(nil), x...), y...)
+		}
+	case IntList:
+		if y, ok := b.(IntList); ok {
+			return append(append(IntList(nil), x...), y...)
+		}
+	case Float64List:
+		if y, ok := b.(Float64List); ok {
+			return append(append(Float64List(nil), x...), y...)
+		}
+	case DecimalList:
+		if y, ok := b.(DecimalList); ok {
+			return append(append(DecimalList(nil), x...), y...)
+		}
+	case StringList:
+		if y, ok := b.(StringList); ok {
+			return append(append(StringList(nil), x...), y...)
+		}
+	case DateList:
+		if y, ok := b.(DateList); ok {
+			return append(append(DateList(nil), x...), y...)
+		}
+	case DateTimeList:
+		if y, ok := b.(DateTimeList); ok {
+			return append(append(DateTimeList(nil), x...), y...)
+		}
+	}
+	return append(append(VariantList(nil), a.ToVariant()...), b.ToVariant()...)
+}
+
+// takeOrEmpty works like Take, but returns a VariantList with empty values for negative indexes.
+func takeOrEmpty(l RList, indexes []int) RList {
+	for _, idx := range indexes {
+		if idx < 0 {
+			r := NewVariantList(len(indexes))
+			for i, idx := range indexes {
+				if idx >= 0 {
+					r[i] = l.Item(idx)
+				}
+			}
+			return r
+		}
+	}
+	return Take(l, indexes)
+}
@@ -0,0 +1,157 @@
+package variant
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+	"github.com/raceresult/go-model/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTable(t *testing.T) *Table {
+	tbl, err := NewTable(
+		[]string{"Bib", "Name", "Time", "DateOfBirth", "Contest"},
+		[]RList{
+			IntList{3, 1, 2},
+			StringList{"Miller", "Smith", "Zahn"},
+			DecimalList{decimal.FromFloat(3600.5), decimal.FromInt(3000), 0},
+			DateList{date.New(1980, 5, 1), date.ZeroDateVB, date.New(2001, 12, 31)},
+			VariantList{RInt(1), nil, RString("2")},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tbl
+}
+
+func TestNewTable(t *testing.T) {
+	_, err := NewTable([]string{"A", "B"}, []RList{IntList{1}, IntList{1, 2}})
+	assert.Error(t, err)
+
+	_, err = NewTable([]string{"A", "a"}, []RList{IntList{1}, IntList{2}})
+	assert.Error(t, err)
+
+	_, err = NewTable([]string{"A"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewTable([]string{"A", "B"}, []RList{IntList{1}, nil})
+	assert.EqualError(t, err, "column B is nil")
+}
+
+func TestTable_FilterSelectSort(t *testing.T) {
+	tbl := newTestTable(t)
+
+	f, err := tbl.Filter(BoolList{true, false, true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.Len())
+	name, _ := f.Column("name")
+	assert.Equal(t, StringList{"Miller", "Zahn"}, name)
+	_, err = tbl.Filter(BoolList{true, false})
+	assert.Error(t, err)
+	_, err = tbl.Filter(BoolList{true, false, true, true})
+	assert.Error(t, err)
+
+	p, err := tbl.Select("name", "BIB")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "Bib"}, p.Names())
+	_, err = tbl.Select("Unknown")
+	assert.Error(t, err)
+
+	// selected columns and the column map do not share data with the table
+	p.Columns()[1].Plus(IntList{10, 10, 10})
+	tbl.ColumnMap()["Bib"].Plus(IntList{10, 10, 10})
+	bibs, _ := tbl.Column("Bib")
+	assert.Equal(t, IntList{3, 1, 2}, bibs)
+
+	sorted, err := tbl.Sort([]string{"Bib"}, nil, nil)
+	assert.NoError(t, err)
+	bib, _ := sorted.Column("Bib")
+	assert.Equal(t, IntList{1, 2, 3}, bib)
+	assert.Equal(t, RString("Smith"), sorted.Row(0)["Name"])
+
+	// the original table is not modified
+	bib, _ = tbl.Column("Bib")
+	assert.Equal(t, IntList{3, 1, 2}, bib)
+
+	// without keys, the result is a copy as well
+	unsorted, err := tbl.Sort(nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NotSame(t, tbl, unsorted)
+	assert.NoError(t, unsorted.AddColumn("Extra", IntList{1, 2, 3}))
+	assert.Equal(t, -1, tbl.ColumnIndex("Extra"))
+	bib, _ = unsorted.Column("Bib")
+	assert.Equal(t, IntList{3, 1, 2}, bib)
+}
+
+func TestTable_Append(t *testing.T) {
+	tbl := newTestTable(t)
+	other, _ := NewTable(
+		[]string{"Name", "Bib", "Time", "DateOfBirth", "Contest"},
+		[]RList{StringList{"New"}, VariantList{RInt(4)}, DecimalList{1}, DateList{date.ZeroDateVB}, VariantList{nil}})
+
+	r, err := tbl.Append(other)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, r.Len())
+	name, _ := r.Column("Name")
+	assert.Equal(t, StringList{"Miller", "Smith", "Zahn", "New"}, name)
+	bib, _ := r.Column("Bib")
+	assert.Equal(t, VariantList{RInt(3), RInt(1), RInt(2), RInt(4)}, bib)
+
+	_, err = tbl.Append(&Table{})
+	assert.Error(t, err)
+}
+
+func TestTable_Join(t *testing.T) {
+	tbl := newTestTable(t)
+	contests, _ := NewTable([]string{"ID", "ContestName"}, []RList{IntList{1, 2}, StringList{"10k", "5k"}})
+
+	r, err := tbl.Join(contests, "Contest", "ID")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, r.Len())
+	assert.Equal(t, []string{"Bib", "Name", "Time", "DateOfBirth", "Contest", "ContestName"}, r.Names())
+	cn, _ := r.Column("ContestName")
+	assert.Equal(t, StringList{"10k", "5k"}, cn)
+
+	r, err = tbl.LeftJoin(contests, "Contest", "ID")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, r.Len())
+	cn, _ = r.Column("ContestName")
+	assert.Equal(t, VariantList{RString("10k"), nil, RString("5k")}, cn)
+
+	_, err = tbl.Join(tbl, "Bib", "Bib")
+	assert.Error(t, err)
+}
+
+func TestTable_JSON(t *testing.T) {
+	tbl := newTestTable(t)
+	assert.NoError(t, tbl.AddColumn("Started", DateTimeList{datetime.New(2024, 5, 1, 9, 0, 0), datetime.ZeroDate(), datetime.New(2024, 5, 1, 9, 0, 30)}))
+	assert.NoError(t, tbl.AddColumn("Note", StringList{"#2024-01-01#", "", "x"}))
+
+	bb, err := json.Marshal(tbl)
+	assert.NoError(t, err)
+
+	var r Table
+	assert.NoError(t, json.Unmarshal(bb, &r))
+	assert.Equal(t, tbl.Names(), r.Names())
+	assert.Equal(t, tbl.Columns(), r.Columns())
+}
+
+func TestTable_CSV(t *testing.T) {
+	tbl := newTestTable(t)
+	var buf bytes.Buffer
+	assert.NoError(t, tbl.WriteCSV(&buf))
+	assert.Equal(t, "Bib,Name,Time,DateOfBirth,Contest\n3,Miller,3600.5,1980-05-01,1\n1,Smith,3000,,\n2,Zahn,0,2001-12-31,2\n", buf.String())
+
+	r, err := ReadCSV(&buf, map[string]Type{"bib": TypeRInt, "Time": TypeRDecimal, "DateOfBirth": TypeRDate})
+	assert.NoError(t, err)
+	for _, name := range []string{"Bib", "Name", "Time", "DateOfBirth"} {
+		want, _ := tbl.Column(name)
+		got, _ := r.Column(name)
+		assert.Equal(t, want, got, name)
+	}
+	contest, _ := r.Column("Contest")
+	assert.Equal(t, StringList{"1", "", "2"}, contest)
+}
@@ -89,3 +89,32 @@ func IsDate(v Variant) bool {
 func IsEmpty(v Variant) bool {
 	return v == nil
 }
+
+var typeNames = [...]string{
+	TypeEmpty:     "empty",
+	TypeRBool:     "bool",
+	TypeRString:   "string",
+	TypeRInt:      "int",
+	TypeRFloat:    "float",
+	TypeRDecimal:  "decimal",
+	TypeRDateTime: "datetime",
+	TypeRDate:     "date",
+}
+
+// String returns the name of the type.
+func (s Type) String() string {
+	if s < 0 || int(s) >= len(typeNames) {
+		return "unknown"
+	}
+	return typeNames[s]
+}
+
+// ParseType returns the type with the given name as returned by Type.String.
+func ParseType(name string) (Type, bool) {
+	for t, n := range typeNames {
+		if n == name {
+			return Type(t), true
+		}
+	}
+	return TypeEmpty, false
+}
@@ -0,0 +1,137 @@
+package variant
+
+import (
+	"database/sql/driver"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raceresult/go-model/date"
+	"github.com/raceresult/go-model/datetime"
+)
+
+// Value wraps a Variant so that it can be used as struct field with encoding/json, encoding/xml and
+// database/sql while keeping its type.
+type Value struct {
+	V Variant
+
+	// ScanType defines the type of the value when scanning a string from a database column.
+	// Strings cannot be distinguished from decimal or date columns by the value only.
+	ScanType Type
+}
+
+// MarshalJSON encodes the value with MarshalTypedJSON.
+func (s Value) MarshalJSON() ([]byte, error) {
+	return MarshalTypedJSON(s.V)
+}
+
+// UnmarshalJSON decodes the value with UnmarshalTypedJSON.
+func (s *Value) UnmarshalJSON(data []byte) error {
+	v, err := UnmarshalTypedJSON(data)
+	if err != nil {
+		return err
+	}
+	s.V = v
+	return nil
+}
+
+// MarshalXML encodes the value as element with a type attribute.
+func (s Value) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	t, v := FormatTyped(s.V)
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "Type"}, Value: t})
+	return e.EncodeElement(v, start)
+}
+
+// UnmarshalXML decodes an element encoded by MarshalXML.
+func (s *Value) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var str string
+	if err := d.DecodeElement(&str, &start); err != nil {
+		return err
+	}
+	t := TypeRString.String()
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "Type" {
+			t = attr.Value
+		}
+	}
+	v, err := ParseTyped(t, str)
+	if err != nil {
+		return err
+	}
+	s.V = v
+	return nil
+}
+
+// Value implements driver.Valuer. Decimals are stored as strings, dates as yyyy-mm-dd and datetimes as
+// strings in the format of FormatTyped: zone-less values as yyyy-mm-dd hh:mm:ss, values with zone in
+// RFC 3339 format with offset.
+func (s Value) Value() (driver.Value, error) {
+	switch v := s.V.(type) {
+	case nil:
+		return nil, nil
+	case rBool:
+		return bool(v), nil
+	case rString:
+		return string(v), nil
+	case rInt:
+		return int64(v), nil
+	case rFloat:
+		return float64(v), nil
+	case rDecimal:
+		return v.toString(), nil
+	case rDate:
+		return codecDateString(date.Date(v)), nil
+	case rDateTime:
+		_, str := FormatTyped(v)
+		return str, nil
+	default:
+		return nil, errors.New("new type not implemented")
+	}
+}
+
+// Scan implements sql.Scanner. Strings are converted to ScanType if set; values with zone require
+// ScanType TypeRDateTime. Values of type time.Time are read as date if ScanType is TypeRDate, otherwise
+// as datetime as by datetime.DateTime.Scan: zone-less in UTC, with zone in any other location.
+func (s *Value) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		s.V = nil
+	case bool:
+		s.V = RBool(v)
+	case int64:
+		s.V = RInt(int(v))
+	case float64:
+		s.V = RFloat(v)
+	case time.Time:
+		if s.ScanType == TypeRDate {
+			s.V = RDate(date.NewAt(v))
+		} else {
+			var dt datetime.DateTime
+			if err := dt.Scan(v); err != nil {
+				return err
+			}
+			s.V = RDateTime(dt)
+		}
+	case []byte:
+		return s.scanString(string(v))
+	case string:
+		return s.scanString(v)
+	default:
+		return errors.New("incompatible type for Value: " + fmt.Sprintf("%T", src))
+	}
+	return nil
+}
+
+func (s *Value) scanString(str string) error {
+	if s.ScanType == TypeEmpty || s.ScanType == TypeRString {
+		s.V = RString(str)
+		return nil
+	}
+	v, err := ParseTyped(s.ScanType.String(), str)
+	if err != nil {
+		return err
+	}
+	s.V = v
+	return nil
+}